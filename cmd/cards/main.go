@@ -12,6 +12,18 @@ import (
 )
 
 func main() {
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "data"
+	}
+	store, err := web.NewFileStore(dataDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := web.RestoreLobbies(store); err != nil {
+		log.Fatal(err)
+	}
+
 	r := mux.NewRouter()
 
 	web.AddRoutes(r, "", "")
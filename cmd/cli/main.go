@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thecreatorguy/cards/pkg/game"
+)
+
+// Plays a game at the command line against CPU players
+func main() {
+	gameName := flag.String("game", "hearts", "the game to play")
+	name := flag.String("name", "You", "your name at the table")
+	players := flag.Int("players", 0, "how many players, including you, defaulting to as few as the game allows")
+	seed := flag.Int64("seed", 0, "the seed for the game, random if zero")
+	options := flag.String("options", "{}", "the game's options as JSON, anything left out takes its default")
+	flag.Parse()
+
+	gt, ok := game.LookupGameType(*gameName)
+	if !ok {
+		names := []string{}
+		for _, t := range game.GameTypes() {
+			names = append(names, t.Name)
+		}
+		log.Fatalf("[%s] is not a game, pick one of: %s", *gameName, strings.Join(names, ", "))
+	}
+	if *players == 0 {
+		*players = gt.MinPlayers
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	var o game.Options
+	if err := json.Unmarshal([]byte(*options), &o); err != nil {
+		log.Fatalf("Could not read the options: %v", err)
+	}
+
+	deciders := []game.Decider{&game.CLIPlayer{Name: *name}}
+	for i := 1; i < *players; i++ {
+		deciders = append(deciders, game.NewStrategyCPU(fmt.Sprintf("CPU %d", i), *seed - int64(i), gt.CPU))
+	}
+	g, err := gt.NewGame(deciders, *seed, o)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := <-g.Start()
+	fmt.Println("-------------")
+	for _, p := range result.Placements {
+		fmt.Printf("%d. %v (%v)\n", p.Place, p.Player, p.Score)
+	}
+}
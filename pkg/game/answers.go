@@ -0,0 +1,209 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PassSelection answers PassCardsQuestion with the indices in hand of the cards to pass,
+// and CribQuestion with the cards to put in the crib
+type PassSelection struct {
+	Cards []int `json:"cards"`
+}
+
+// CardPlay answers PlayOnTrickQuestion with the index in hand of the card to play,
+// DiscardQuestion with the card to discard, PegQuestion with the card to peg and
+// PlayCardQuestion with the card to put on the discard pile
+type CardPlay struct {
+	Card int `json:"card"`
+}
+
+// TrickBid answers BidQuestion with how many tricks the player expects to take, zero
+// being a nil bid in games that have them
+type TrickBid struct {
+	Tricks int `json:"tricks"`
+}
+
+// BlindNil answers BlindNilQuestion, bidding nil without looking at the hand or not
+type BlindNil struct {
+	Blind bool `json:"blind"`
+}
+
+// TrumpCall answers OrderUpQuestion and CallTrumpQuestion with the suit to make trump, and
+// whether the caller goes alone. An empty suit is a pass.
+type TrumpCall struct {
+	Suit Suit `json:"suit,omitempty"`
+	Alone bool `json:"alone,omitempty"`
+}
+
+type CallKind string
+const (
+	PassCall = CallKind("pass")
+	BidCall = CallKind("bid")
+	DoubleCall = CallKind("double")
+	RedoubleCall = CallKind("redouble")
+)
+
+// BridgeCall answers BidQuestion in an auction. Only bids have a level and strain.
+type BridgeCall struct {
+	Kind CallKind `json:"kind"`
+	Level int `json:"level,omitempty"`
+	Strain Suit `json:"strain,omitempty"`
+}
+
+// Wager answers BetQuestion with one of the options offered, RaiseQuestion with the bet to
+// raise to, and PlaceBetQuestion with the bet for the next blackjack hand
+type Wager struct {
+	Action string `json:"action"`
+	To int `json:"to,omitempty"`
+}
+
+// DrawChoice answers DrawQuestion, taking the top of the discard pile or else drawing from
+// the stock
+type DrawChoice struct {
+	FromDiscard bool `json:"fromDiscard"`
+}
+
+// KnockCall answers KnockQuestion, ending the hand or playing on
+type KnockCall struct {
+	Knock bool `json:"knock"`
+}
+
+// PlayDrawn answers PlayDrawnQuestion, playing the card just drawn or keeping it
+type PlayDrawn struct {
+	Play bool `json:"play"`
+}
+
+// SuitChoice answers DeclareSuitQuestion with the suit to follow an eight
+type SuitChoice struct {
+	Suit Suit `json:"suit"`
+}
+
+// InsuranceCall answers InsuranceQuestion, insuring against a dealer blackjack or not
+type InsuranceCall struct {
+	Insure bool `json:"insure"`
+}
+
+// BlackjackAction answers HandActionQuestion with one of the options offered
+type BlackjackAction struct {
+	Action string `json:"action"`
+}
+
+type ViolationCode string
+const (
+	MalformedAnswerViolation = ViolationCode("malformed_answer")
+	WrongCardCountViolation = ViolationCode("wrong_card_count")
+	OutOfRangeViolation = ViolationCode("out_of_range")
+	DuplicateIndexViolation = ViolationCode("duplicate_index")
+	MustFollowSuitViolation = ViolationCode("must_follow_suit")
+	HeartsNotBrokenViolation = ViolationCode("hearts_not_broken")
+	// The first trick has to be led with the opening card, which is the 2 of clubs unless
+	// it was taken out of the deck
+	MustLeadOpeningCardViolation = ViolationCode("must_lead_opening_card")
+	NoPointsOnFirstTrickViolation = ViolationCode("no_points_on_first_trick")
+	InvalidBidViolation = ViolationCode("invalid_bid")
+	SpadesNotBrokenViolation = ViolationCode("spades_not_broken")
+	InvalidTrumpViolation = ViolationCode("invalid_trump")
+	DealerMustCallViolation = ViolationCode("dealer_must_call")
+	InvalidBetViolation = ViolationCode("invalid_bet")
+	DiscardTakenCardViolation = ViolationCode("discard_taken_card")
+	OverThirtyOneViolation = ViolationCode("over_thirty_one")
+	MustMatchViolation = ViolationCode("must_match")
+	InvalidSuitViolation = ViolationCode("invalid_suit")
+	InvalidActionViolation = ViolationCode("invalid_action")
+)
+
+// RuleViolation explains why an answer was rejected. Code is meant for programs, Message
+// for people; Card and Suit fill in the details some codes need, like which suit has to be
+// followed.
+type RuleViolation struct {
+	Code ViolationCode `json:"code"`
+	Message string `json:"message"`
+	Index *int `json:"index,omitempty"`
+	Card *Card `json:"card,omitempty"`
+	Suit Suit `json:"suit,omitempty"`
+}
+
+func (v *RuleViolation) Error() string {
+	return v.Message
+}
+
+// ViolationReporter is implemented by deciders that want to know exactly which rule an
+// answer broke. Deciders that don't are sent the violation's message through ShowInfo.
+type ViolationReporter interface {
+	ShowViolation(*RuleViolation)
+}
+
+func ShowViolation(d Decider, v *RuleViolation) {
+	if reporter, ok := d.(ViolationReporter); ok {
+		reporter.ShowViolation(v)
+	} else {
+		d.ShowInfo(v.Message)
+	}
+}
+
+// ValidatePass checks the answer picks count different cards from the hand, and returns
+// their indices in order
+func ValidatePass(hand Deck, answer Answer, count int) ([]int, *RuleViolation) {
+	selection, ok := answer.(PassSelection)
+	if !ok {
+		return nil, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	if len(selection.Cards) != count {
+		return nil, &RuleViolation{
+			Code: WrongCardCountViolation,
+			Message: fmt.Sprintf("Must pick exactly %d cards", count),
+		}
+	}
+
+	indices := append([]int{}, selection.Cards...)
+	sort.Ints(indices)
+	for i, index := range indices {
+		if index < 0 || index >= len(hand) {
+			return nil, outOfRange(index)
+		}
+		if i > 0 && indices[i-1] == index {
+			return nil, &RuleViolation{
+				Code: DuplicateIndexViolation,
+				Message: fmt.Sprintf("Card %d was picked more than once", index),
+				Index: &index,
+			}
+		}
+	}
+	return indices, nil
+}
+
+// ValidateIndex checks the answer picks a card from the hand, and returns its index
+func ValidateIndex(hand Deck, answer Answer) (int, *RuleViolation) {
+	play, ok := answer.(CardPlay)
+	if !ok {
+		return 0, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	if play.Card < 0 || play.Card >= len(hand) {
+		return 0, outOfRange(play.Card)
+	}
+	return play.Card, nil
+}
+
+func outOfRange(index int) *RuleViolation {
+	return &RuleViolation{
+		Code: OutOfRangeViolation,
+		Message: fmt.Sprintf("There is no card %d in your hand", index),
+		Index: &index,
+	}
+}
+
+// ValidateBid checks the answer bids from min to max tricks, and returns the bid
+func ValidateBid(answer Answer, min int, max int) (int, *RuleViolation) {
+	bid, ok := answer.(TrickBid)
+	if !ok {
+		return 0, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	if bid.Tricks < min || bid.Tricks > max {
+		return 0, &RuleViolation{
+			Code: InvalidBidViolation,
+			Message: fmt.Sprintf("Bid must be from %d to %d tricks", min, max),
+		}
+	}
+	return bid.Tricks, nil
+}
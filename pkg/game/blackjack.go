@@ -0,0 +1,852 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	BlackjackMinPlayers = 1
+	BlackjackMaxPlayers = 7
+	// A player can split into at most this many hands
+	MaxSplitHands = 4
+	DealerStandsOn = 17
+)
+
+const (
+	PlaceBetQuestion = Question("place_bet")
+	InsuranceQuestion = Question("insurance")
+	HandActionQuestion = Question("hand_action")
+)
+
+// Options offered when it is a player's turn to play a hand, or to take insurance
+const (
+	HitOption = "hit"
+	StandOption = "stand"
+	DoubleOption = "double"
+	SplitOption = "split"
+	SurrenderOption = "surrender"
+	InsureOption = "insure"
+	DeclineOption = "decline"
+	BetOption = "bet"
+)
+
+type HandResult string
+const (
+	WonResult = HandResult("won")
+	LostResult = HandResult("lost")
+	PushResult = HandResult("push")
+	BlackjackResult = HandResult("blackjack")
+	BustResult = HandResult("bust")
+	SurrenderResult = HandResult("surrendered")
+)
+
+// Bets go down before the deal. Insurance is offered when the dealer shows an ace, and a
+// dealer blackjack ends the hand before anyone plays.
+var BlackjackPhases = PhaseTransitions{
+	BettingPhase: {InsurancePhase, PlayingPhase, ScoringPhase},
+	InsurancePhase: {PlayingPhase, ScoringPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {BettingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type BlackjackHand struct {
+	Cards Deck `json:"cards"`
+	Bet int `json:"bet"`
+	Doubled bool `json:"doubled,omitempty"`
+	Surrendered bool `json:"surrendered,omitempty"`
+	// Hands made by splitting are never blackjacks
+	Split bool `json:"split,omitempty"`
+	// Done once the player can do nothing more with the hand
+	Done bool `json:"done"`
+}
+
+type BlackjackPlayer struct {
+	*Seat
+	Chips int
+	// The bet placed for the next deal
+	Bet int
+	Hands []BlackjackHand
+	Insurance int
+	InsuranceOffered bool
+	// Chips at the start of the hand, to score the hand by
+	StartChips int
+	// Players who can't cover the smallest bet are out
+	Out bool
+}
+
+type BlackjackGame struct {
+	Players map[string]*BlackjackPlayer
+	Options BlackjackOptions
+	Shoe Deck
+	// Shuffles counts the times the shoe has been shuffled, so each shuffle can be reproduced
+	Shuffles int
+	// The dealer's hand, the second card of which stays face down until the dealer plays
+	Dealer Deck
+	HoleShown bool
+	Table
+}
+
+type BlackjackHandInfo struct {
+	Cards Deck `json:"cards"`
+	Bet int `json:"bet"`
+	Total int `json:"total"`
+	Soft bool `json:"soft"`
+	Done bool `json:"done"`
+}
+
+type BlackjackPlayerInfo struct {
+	Score int `json:"score"`
+	Bet int `json:"bet"`
+	Hands []BlackjackHandInfo `json:"hands"`
+	Insurance int `json:"insurance,omitempty"`
+	Out bool `json:"out"`
+	Lead bool `json:"lead"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type BlackjackGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]BlackjackPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	// Only the cards the dealer is showing
+	DealerHand Deck `json:"dealerHand"`
+	DealerTotal int `json:"dealerTotal"`
+	ShoeSize int `json:"shoeSize"`
+	MinBet int `json:"minBet"`
+	MaxBet int `json:"maxBet"`
+	HandsLeft int `json:"handsLeft"`
+	Hand Deck `json:"hand"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewBlackjackGame(deciders []Decider, seed int64, options BlackjackOptions) *BlackjackGame {
+	g := &BlackjackGame{
+		Players: map[string]*BlackjackPlayer{},
+		Options: options,
+	}
+	// Running out of time bets the least, and stands on whatever the hand is
+	g.Init(g, seed, BlackjackPhases, BettingPhase, BlackjackTimeout)
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &BlackjackPlayer{Seat: s, Chips: options.StartingChips, StartChips: options.StartingChips}
+	})
+	return g
+}
+
+func (g *BlackjackGame) GetDeciderInfo(decider Decider) interface{} {
+	turn, _ := g.NextHand()
+	playerInfo := map[string]BlackjackPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		info := BlackjackPlayerInfo{
+			Score: p.Chips,
+			Bet: p.Bet,
+			Hands: []BlackjackHandInfo{},
+			Insurance: p.Insurance,
+			Out: p.Out,
+			Lead: turn == p,
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+		for _, h := range p.Hands {
+			total, soft := BlackjackTotal(h.Cards)
+			info.Hands = append(info.Hands, BlackjackHandInfo{Cards: h.Cards, Bet: h.Bet, Total: total, Soft: soft, Done: h.Done})
+			if g.Phase != BettingPhase {
+				info.Bet += h.Bet
+			}
+		}
+		playerInfo[name] = info
+	}
+
+	dealer := g.DealerShowing()
+	dealerTotal, _ := BlackjackTotal(dealer)
+	var hand Deck
+	if p := g.Players[decider.GetName()]; len(p.Hands) > 0 {
+		hand = p.Hands[0].Cards
+		if turn == p {
+			_, i := g.NextHand()
+			hand = p.Hands[i].Cards
+		}
+	}
+
+	return &BlackjackGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		DealerHand: dealer,
+		DealerTotal: dealerTotal,
+		ShoeSize: len(g.Shoe),
+		MinBet: g.Options.MinBet,
+		MaxBet: g.Options.MaxBet,
+		HandsLeft: g.Options.Hands - g.Round,
+		Hand: hand,
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (bg *BlackjackGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range bg.PlayerOrder {
+		info := bg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v", name, info.Score)
+		if info.Out {
+			b.WriteString(", out")
+		}
+		for _, h := range info.Hands {
+			fmt.Fprintf(&b, ", %v %v on %v", h.Cards, h.Total, h.Bet)
+		}
+		b.WriteString(" ) ")
+	}
+	fmt.Fprintf(&b, "\nDealer: %v %v", bg.DealerHand, bg.DealerTotal)
+	fmt.Fprintf(&b, "\nHand: %v", bg.Hand)
+	return b.String()
+}
+
+// BlackjackTotal counts the cards the best way for the player, with an ace counted as
+// eleven if that doesn't bust the hand, which makes the total soft
+func BlackjackTotal(cards Deck) (int, bool) {
+	total, aces := 0, 0
+	for _, c := range cards {
+		total += c.PipValue()
+		if c.Value == Ace {
+			aces++
+		}
+	}
+	if aces > 0 && total + 10 <= 21 {
+		return total + 10, true
+	}
+	return total, false
+}
+
+// IsBlackjack is true for an ace and a ten card dealt together, and not made by splitting
+func (h BlackjackHand) IsBlackjack() bool {
+	total, _ := BlackjackTotal(h.Cards)
+	return len(h.Cards) == 2 && total == 21 && !h.Split
+}
+
+// DealerShowing is the dealer's hand as the players see it
+func (g *BlackjackGame) DealerShowing() Deck {
+	if g.HoleShown || len(g.Dealer) < 2 {
+		return g.Dealer.Copy()
+	}
+	return g.Dealer[:1].Copy()
+}
+
+// BetLimits returns the least and most the player can bet on the next hand
+func (g *BlackjackGame) BetLimits(name string) (int, int) {
+	return g.Options.MinBet, min(g.Options.MaxBet, g.Players[name].Chips)
+}
+
+// NextHand returns the first player with a hand still to play, and which of their hands
+// it is, or nil once every hand is played
+func (g *BlackjackGame) NextHand() (*BlackjackPlayer, int) {
+	if g.Phase != PlayingPhase {
+		return nil, 0
+	}
+	for _, name := range g.PlayerOrder {
+		p := g.Players[name]
+		for i, h := range p.Hands {
+			if !h.Done {
+				return p, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+// HandOptions lists what the player can do with their hand. Doubling and splitting need
+// chips to match the bet, and only a pair of the same value can be split. Surrender is
+// only offered as the first move on the hand as dealt.
+func (g *BlackjackGame) HandOptions(p *BlackjackPlayer, i int) []string {
+	h := p.Hands[i]
+	options := []string{HitOption, StandOption}
+	if len(h.Cards) != 2 {
+		return options
+	}
+	if p.Chips >= h.Bet {
+		options = append(options, DoubleOption)
+		if h.Cards[0].Value == h.Cards[1].Value && len(p.Hands) < MaxSplitHands {
+			options = append(options, SplitOption)
+		}
+	}
+	if g.Options.Surrender && len(p.Hands) == 1 && !h.Split {
+		options = append(options, SurrenderOption)
+	}
+	return options
+}
+
+func (g *BlackjackGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case PlaceBetQuestion:
+		least, most := g.BetLimits(name)
+		return Prompt{
+			Question: q,
+			Kind: NumberPrompt,
+			Text: fmt.Sprintf("You have %d chips. Place your bet", p.Chips),
+			Min: least,
+			Max: most,
+		}
+	case InsuranceQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("The dealer shows an ace. Insure your bet for %d?", p.Hands[0].Bet / 2),
+			Hand: p.Hands[0].Cards.Copy(),
+			Options: []string{InsureOption, DeclineOption},
+		}
+	case HandActionQuestion:
+		turn, i := g.NextHand()
+		if turn != p {
+			break
+		}
+		total, soft := BlackjackTotal(p.Hands[i].Cards)
+		text := fmt.Sprintf("You have %d", total)
+		if soft {
+			text = fmt.Sprintf("You have soft %d", total)
+		}
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("%v, the dealer shows %v", text, g.Dealer[0]),
+			Hand: p.Hands[i].Cards.Copy(),
+			Options: g.HandOptions(p, i),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *BlackjackGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case PlaceBetQuestion:
+		return Wager{Action: BetOption, To: r.Number}
+	case InsuranceQuestion:
+		return InsuranceCall{Insure: r.Option == InsureOption}
+	case HandActionQuestion:
+		return BlackjackAction{Action: r.Option}
+	}
+	return nil
+}
+
+func (g *BlackjackGame) GetPlayer(i int) *BlackjackPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// GameOver is true once the hands have all been played, or nobody can bet
+func (g *BlackjackGame) GameOver() bool {
+	return g.Cancelled || g.Phase == FinishedPhase
+}
+
+// Scores gives every player their chips, counting any bet already down
+func (g *BlackjackGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Chips + p.Bet
+	}
+	return scores
+}
+
+func (g *BlackjackGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+// ShoeRand returns the random source for the current shuffle of the shoe, so any shuffle
+// can be reproduced on its own
+func (g *BlackjackGame) ShoeRand() *rand.Rand {
+	return g.SeededRand(int64(g.Shuffles))
+}
+
+// shuffleShoe makes up a new shoe from every deck but the cards out on the table
+func (g *BlackjackGame) shuffleShoe() PileReshuffled {
+	g.Shuffles++
+	shoe := Deck{}
+	for i := 0; i < g.Options.Decks; i++ {
+		shoe = append(shoe, NewDeck()...)
+	}
+	table := g.Dealer.Copy()
+	for _, p := range g.Players {
+		for _, h := range p.Hands {
+			table = append(table, h.Cards...)
+		}
+	}
+	g.Shoe = shoe.Without(table)
+	g.Shoe.Shuffle(g.ShoeRand())
+	return PileReshuffled{Round: g.Round, Cards: len(g.Shoe)}
+}
+
+// draw deals the next card from the shoe, shuffling up a new one if it has run out
+func (g *BlackjackGame) draw(events *[]Event) Card {
+	if len(g.Shoe) == 0 {
+		*events = append(*events, g.shuffleShoe())
+	}
+	return g.Shoe.Deal()
+}
+
+func (g *BlackjackGame) PlayRound() bool {
+	if g.Phase == BettingPhase {
+		for _, name := range g.PlayerOrder {
+			if p := g.Players[name]; !p.Out && p.Bet == 0 {
+				if cancelled := p.PlaceBet(g); cancelled {
+					return true
+				}
+				g.NotifyAll()
+			}
+		}
+		g.Deal()
+	}
+
+	if g.Phase == InsurancePhase {
+		for _, name := range g.PlayerOrder {
+			p := g.Players[name]
+			if len(p.Hands) == 0 || p.InsuranceOffered || p.Hands[0].Bet / 2 == 0 || p.Chips < p.Hands[0].Bet / 2 {
+				continue
+			}
+			if cancelled := p.OfferInsurance(g); cancelled {
+				return true
+			}
+			g.NotifyAll()
+		}
+		g.Peek()
+	}
+
+	for g.Phase == PlayingPhase {
+		p, i := g.NextHand()
+		if p == nil {
+			g.setPhase(ScoringPhase)
+			break
+		}
+		if cancelled := p.PlayHand(g, i); cancelled {
+			return true
+		}
+		g.NotifyAll()
+	}
+
+	// Everything is settled before any of it is announced, so a game saved on hearing
+	// about it carries on from the next hand
+	round := g.Round
+	events := g.PlayDealer()
+	roundPoints, settled := g.Settle()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	out := 0
+	for _, p := range g.Players {
+		p.StartChips = p.Chips
+		p.Out = p.Chips < g.Options.MinBet
+		if p.Out {
+			out++
+		}
+	}
+	g.Round++
+	if g.Round >= g.Options.Hands || out == g.NumPlayers() {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(BettingPhase)
+	}
+	for _, e := range events {
+		g.emit(e)
+	}
+	for _, e := range settled {
+		g.emit(e)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+func (p *BlackjackPlayer) PlaceBet(g *BlackjackGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, PlaceBetQuestion, g)
+		if cancelled {
+			return true
+		}
+		least, most := g.BetLimits(p.GetName())
+		wager, ok := answer.(Wager)
+		if !ok || wager.To < least || wager.To > most {
+			ShowViolation(p.Decider, &RuleViolation{
+				Code: InvalidBetViolation,
+				Message: fmt.Sprintf("Bet between %d and %d", least, most),
+			})
+			continue
+		}
+		p.Chips -= wager.To
+		p.Bet = wager.To
+		g.emit(BetPlaced{Round: g.Round, Player: p.GetName(), Action: BetOption, Bet: p.Bet, Pot: g.TotalBet()})
+		return false
+	}
+}
+
+// TotalBet is every chip bet on the table
+func (g *BlackjackGame) TotalBet() int {
+	total := 0
+	for _, p := range g.Players {
+		// Hands from the last deal stay on the table until the next
+		if g.Phase == BettingPhase {
+			total += p.Bet
+			continue
+		}
+		total += p.Insurance
+		for _, h := range p.Hands {
+			total += h.Bet
+		}
+	}
+	return total
+}
+
+// Deal puts everyone's bet on a hand and deals a card each round the table and to the
+// dealer, twice. The shoe is shuffled first once it has been dealt down to the cut card.
+func (g *BlackjackGame) Deal() {
+	g.Dealer = Deck{}
+	g.HoleShown = false
+	for _, p := range g.Players {
+		p.Hands = nil
+		p.Insurance, p.InsuranceOffered = 0, false
+		if !p.Out {
+			p.Hands = []BlackjackHand{{Cards: Deck{}, Bet: p.Bet}}
+			p.Bet = 0
+		}
+	}
+	events := []Event{}
+	if len(g.Shoe) <= g.Options.CutCard() {
+		events = append(events, g.shuffleShoe())
+	}
+	for i := 0; i < 2; i++ {
+		for _, name := range g.PlayerOrder {
+			if p := g.Players[name]; !p.Out {
+				p.Hands[0].Cards = append(p.Hands[0].Cards, g.draw(&events))
+			}
+		}
+		g.Dealer = append(g.Dealer, g.draw(&events))
+	}
+	for _, p := range g.Players {
+		if len(p.Hands) > 0 && p.Hands[0].IsBlackjack() {
+			p.Hands[0].Done = true
+		}
+	}
+
+	// The dealer checks for blackjack under a ten straight away, and under an ace once
+	// insurance has been offered
+	up := g.Dealer[0]
+	switch {
+	case up.Value == Ace:
+		g.setPhase(InsurancePhase)
+	case up.PipValue() == 10 && (BlackjackHand{Cards: g.Dealer}).IsBlackjack():
+		g.HoleShown = true
+		g.setPhase(ScoringPhase)
+	default:
+		g.setPhase(PlayingPhase)
+	}
+
+	for _, e := range events {
+		g.emit(e)
+	}
+	for _, name := range g.PlayerOrder {
+		if p := g.Players[name]; !p.Out {
+			g.emit(HandDealt{Round: g.Round, Player: name, Hand: p.Hands[0].Cards.Copy()})
+		}
+	}
+	g.emit(UpcardDealt{Round: g.Round, Card: up})
+	g.NotifyAll()
+}
+
+func (p *BlackjackPlayer) OfferInsurance(g *BlackjackGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	answer, cancelled := g.Ask(p.Seat, InsuranceQuestion, g)
+	if cancelled {
+		return true
+	}
+	p.InsuranceOffered = true
+	action := DeclineOption
+	if call, _ := answer.(InsuranceCall); call.Insure {
+		action = InsureOption
+		p.Insurance = p.Hands[0].Bet / 2
+		p.Chips -= p.Insurance
+	}
+	g.emit(BetPlaced{Round: g.Round, Player: p.GetName(), Action: action, Bet: p.Insurance, Pot: g.TotalBet()})
+	return false
+}
+
+// Peek has the dealer look under their ace, ending the hand if they have blackjack
+func (g *BlackjackGame) Peek() {
+	if (BlackjackHand{Cards: g.Dealer}).IsBlackjack() {
+		g.HoleShown = true
+		g.setPhase(ScoringPhase)
+	} else {
+		g.setPhase(PlayingPhase)
+	}
+}
+
+func (p *BlackjackPlayer) PlayHand(g *BlackjackGame, i int) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, HandActionQuestion, g)
+		if cancelled {
+			return true
+		}
+		action, ok := answer.(BlackjackAction)
+		allowed := false
+		for _, option := range g.HandOptions(p, i) {
+			allowed = allowed || ok && option == action.Action
+		}
+		if !allowed {
+			ShowViolation(p.Decider, &RuleViolation{Code: InvalidActionViolation, Message: "You can not " + action.Action + " now"})
+			continue
+		}
+
+		events := g.act(p, i, action.Action)
+		total, _ := BlackjackTotal(p.Hands[i].Cards)
+		g.emit(HandActed{
+			Round: g.Round,
+			Player: p.GetName(),
+			Hand: i,
+			Action: action.Action,
+			Cards: p.Hands[i].Cards.Copy(),
+			Total: total,
+		})
+		for _, e := range events {
+			g.emit(e)
+		}
+		return false
+	}
+}
+
+// act carries out the player's move on their hand. A hand is done once it reaches 21 or
+// busts, and a double takes exactly one more card. Split hands are dealt their second
+// cards straight away, and split aces get no more than that.
+func (g *BlackjackGame) act(p *BlackjackPlayer, i int, action string) []Event {
+	events := []Event{}
+	h := &p.Hands[i]
+	switch action {
+	case HitOption:
+		h.Cards = append(h.Cards, g.draw(&events))
+	case StandOption:
+		h.Done = true
+	case DoubleOption:
+		p.Chips -= h.Bet
+		h.Bet *= 2
+		h.Doubled, h.Done = true, true
+		h.Cards = append(h.Cards, g.draw(&events))
+	case SplitOption:
+		p.Chips -= h.Bet
+		first, second := h.Cards[0], h.Cards[1]
+		left := BlackjackHand{Cards: Deck{first, g.draw(&events)}, Bet: h.Bet, Split: true}
+		right := BlackjackHand{Cards: Deck{second, g.draw(&events)}, Bet: h.Bet, Split: true}
+		left.Done, right.Done = first.Value == Ace, second.Value == Ace
+		hands := append([]BlackjackHand{}, p.Hands[:i]...)
+		hands = append(hands, left, right)
+		p.Hands = append(hands, p.Hands[i+1:]...)
+		for j := i; j <= i + 1; j++ {
+			if total, _ := BlackjackTotal(p.Hands[j].Cards); total >= 21 {
+				p.Hands[j].Done = true
+			}
+		}
+		return events
+	case SurrenderOption:
+		h.Surrendered, h.Done = true, true
+	}
+	if total, _ := BlackjackTotal(h.Cards); total >= 21 {
+		h.Done = true
+	}
+	return events
+}
+
+// PlayDealer turns up the hole card and draws to 17, hitting a soft 17 if the table's
+// rules say so. The dealer doesn't draw when every hand is already settled.
+func (g *BlackjackGame) PlayDealer() []Event {
+	g.HoleShown = true
+	events := []Event{}
+	live := false
+	for _, p := range g.Players {
+		for _, h := range p.Hands {
+			total, _ := BlackjackTotal(h.Cards)
+			live = live || !h.Surrendered && total <= 21 && !h.IsBlackjack()
+		}
+	}
+	dealerBlackjack := (BlackjackHand{Cards: g.Dealer}).IsBlackjack()
+	for live && !dealerBlackjack {
+		total, soft := BlackjackTotal(g.Dealer)
+		if total > DealerStandsOn || total == DealerStandsOn && !(soft && g.Options.HitSoft17) {
+			break
+		}
+		g.Dealer = append(g.Dealer, g.draw(&events))
+	}
+	total, _ := BlackjackTotal(g.Dealer)
+	return append(events, DealerPlayed{Round: g.Round, Hand: g.Dealer.Copy(), Total: total, Blackjack: dealerBlackjack})
+}
+
+// Settle pays out every hand against the dealer's. Blackjack pays three to two, other
+// wins even money, and insurance two to one if the dealer has blackjack. A surrender
+// gets half the bet back. It returns how many chips each player won or lost on the hand.
+func (g *BlackjackGame) Settle() (map[string]int, []Event) {
+	dealerTotal, _ := BlackjackTotal(g.Dealer)
+	dealerBlackjack := (BlackjackHand{Cards: g.Dealer}).IsBlackjack()
+	settled := []Event{}
+	roundPoints := map[string]int{}
+	for _, name := range g.PlayerOrder {
+		p := g.Players[name]
+		if dealerBlackjack {
+			p.Chips += p.Insurance * 3
+		}
+		for i, h := range p.Hands {
+			total, _ := BlackjackTotal(h.Cards)
+			result, payout := LostResult, 0
+			switch {
+			case h.Surrendered:
+				result, payout = SurrenderResult, h.Bet / 2
+			case total > 21:
+				result = BustResult
+			case h.IsBlackjack() && dealerBlackjack:
+				result, payout = PushResult, h.Bet
+			case h.IsBlackjack():
+				result, payout = BlackjackResult, h.Bet + h.Bet * 3 / 2
+			case dealerBlackjack:
+			case dealerTotal > 21 || total > dealerTotal:
+				result, payout = WonResult, h.Bet * 2
+			case total == dealerTotal:
+				result, payout = PushResult, h.Bet
+			}
+			p.Chips += payout
+			settled = append(settled, HandSettled{
+				Round: g.Round,
+				Player: name,
+				Hand: i,
+				Cards: h.Cards.Copy(),
+				Bet: h.Bet,
+				Payout: payout,
+				Result: result,
+			})
+		}
+		roundPoints[name] = p.Chips - p.StartChips
+	}
+	return roundPoints, settled
+}
+
+// BlackjackTimeout bets the least it can, declines insurance, and stands
+func BlackjackTimeout(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	bg, ok := g.(*BlackjackGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	switch q {
+	case PlaceBetQuestion:
+		least, _ := bg.BetLimits(d.GetName())
+		return Wager{Action: BetOption, To: least}
+	case InsuranceQuestion:
+		return InsuranceCall{Insure: false}
+	case HandActionQuestion:
+		return BlackjackAction{Action: StandOption}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// BlackjackCPU bets a little over the minimum, never takes insurance, and plays basic
+// strategy for the dealer's upcard
+func BlackjackCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	bg, ok := g.(*BlackjackGame)
+	if !ok || q != HandActionQuestion {
+		if q == PlaceBetQuestion && ok {
+			least, most := bg.BetLimits(d.GetName())
+			return Wager{Action: BetOption, To: min(least * (1 + r.Intn(3)), most)}
+		}
+		return BlackjackTimeout(r, d, q, g)
+	}
+	p, i := bg.NextHand()
+	if p == nil || p.GetName() != d.GetName() {
+		return BlackjackTimeout(r, d, q, g)
+	}
+	options := bg.HandOptions(p, i)
+	can := func(option string) bool {
+		for _, o := range options {
+			if o == option {
+				return true
+			}
+		}
+		return false
+	}
+	return BlackjackAction{Action: BasicStrategy(p.Hands[i].Cards, bg.Dealer[0], can)}
+}
+
+// BasicStrategy is the usual play for a hand against the dealer's upcard, falling back to
+// hitting or standing when a double, split or surrender isn't allowed
+func BasicStrategy(cards Deck, up Card, can func(string) bool) string {
+	dealer := up.PipValue()
+	if up.Value == Ace {
+		dealer = 11
+	}
+	total, soft := BlackjackTotal(cards)
+	between := func(low int, high int) bool {
+		return dealer >= low && dealer <= high
+	}
+
+	if len(cards) == 2 && cards[0].Value == cards[1].Value && can(SplitOption) {
+		split := false
+		switch pair := cards[0].PipValue(); {
+		case cards[0].Value == Ace || pair == 8:
+			split = true
+		case pair == 2 || pair == 3 || pair == 7:
+			split = between(2, 7)
+		case pair == 6:
+			split = between(2, 6)
+		case pair == 4:
+			split = between(5, 6)
+		case pair == 9:
+			split = between(2, 6) || between(8, 9)
+		}
+		if split {
+			return SplitOption
+		}
+	}
+
+	if !soft && can(SurrenderOption) && (total == 16 && dealer >= 9 || total == 15 && dealer == 10) {
+		return SurrenderOption
+	}
+
+	double := false
+	switch {
+	case soft && total >= 19:
+		return StandOption
+	case soft && total == 18:
+		double = between(3, 6)
+		if !double || !can(DoubleOption) {
+			if between(2, 8) {
+				return StandOption
+			}
+			return HitOption
+		}
+	case soft:
+		double = total >= 17 && between(3, 6) || total >= 15 && between(4, 6) || between(5, 6)
+	case total >= 17:
+		return StandOption
+	case total >= 13:
+		if between(2, 6) {
+			return StandOption
+		}
+		return HitOption
+	case total == 12:
+		if between(4, 6) {
+			return StandOption
+		}
+		return HitOption
+	case total == 11:
+		double = dealer <= 10
+	case total == 10:
+		double = dealer <= 9
+	case total == 9:
+		double = between(3, 6)
+	}
+	if double && can(DoubleOption) {
+		return DoubleOption
+	}
+	return HitOption
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+)
+
+func newTestBlackjackGame() *BlackjackGame {
+	options := BlackjackOptions{StartingChips: 1000, MinBet: 10, MaxBet: 500, Hands: 10, Decks: 1, Penetration: 75}
+	return NewBlackjackGame([]Decider{NewRandomCPU("a", 1)}, 1, options)
+}
+
+func TestBlackjackSettle(t *testing.T) {
+	tests := []struct {
+		name string
+		dealer string
+		hands []BlackjackHand
+		insurance int
+		want int
+	}{
+		{"blackjack pays three to two", "10H 7C", []BlackjackHand{{Cards: cards("AS KD"), Bet: 100}}, 0, 150},
+		{"blackjacks push", "AH KC", []BlackjackHand{{Cards: cards("AS KD"), Bet: 100}}, 0, 0},
+		{"win", "10H 7C", []BlackjackHand{{Cards: cards("10S 9D"), Bet: 100}}, 0, 100},
+		{"push", "10H 9C", []BlackjackHand{{Cards: cards("10S 9D"), Bet: 100}}, 0, 0},
+		{"dealer busts", "10H 6C 9S", []BlackjackHand{{Cards: cards("10S 2D"), Bet: 100}}, 0, 100},
+		{"bust", "10H 7C", []BlackjackHand{{Cards: cards("10S 5D 9C"), Bet: 100}}, 0, -100},
+		{"double", "10H QC", []BlackjackHand{{Cards: cards("10S 5D 6C"), Bet: 200, Doubled: true, Done: true}}, 0, 200},
+		{"surrender", "10H 9C", []BlackjackHand{{Cards: cards("10S 6D"), Bet: 100, Surrendered: true}}, 0, -50},
+		// An ace and a ten made by splitting is 21, which is not a blackjack
+		{"split", "10H 8C", []BlackjackHand{{Cards: cards("AS KD"), Bet: 100, Split: true}, {Cards: cards("AH 5C"), Bet: 100, Split: true}}, 0, 0},
+		{"split against blackjack", "AD KC", []BlackjackHand{{Cards: cards("AS KD"), Bet: 100, Split: true}, {Cards: cards("AH 9C"), Bet: 100, Split: true}}, 0, -200},
+		// Insurance pays two to one when the dealer has blackjack, and is lost otherwise
+		{"insurance pays", "AH KC", []BlackjackHand{{Cards: cards("10S 9D"), Bet: 100}}, 50, 0},
+		{"insurance lost", "AH 7C", []BlackjackHand{{Cards: cards("10S 9D"), Bet: 100}}, 50, 50},
+		{"insured blackjack", "AH KC", []BlackjackHand{{Cards: cards("AS KD"), Bet: 100}}, 50, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestBlackjackGame()
+			g.Dealer = cards(tt.dealer)
+			p := g.Players["a"]
+			p.StartChips = p.Chips
+			p.Hands = tt.hands
+			p.Insurance = tt.insurance
+			p.Chips -= tt.insurance
+			for _, h := range tt.hands {
+				p.Chips -= h.Bet
+			}
+
+			points, _ := g.Settle()
+			if points["a"] != tt.want || p.Chips != 1000 + tt.want {
+				t.Errorf("got %d with %d chips, want %d", points["a"], p.Chips, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlackjackSplit(t *testing.T) {
+	g := newTestBlackjackGame()
+	g.Shoe = cards("3C KH 9D 2S")
+	p := g.Players["a"]
+	p.Chips = 900
+	p.Hands = []BlackjackHand{{Cards: cards("8S 8D"), Bet: 100}}
+
+	g.act(p, 0, SplitOption)
+	if p.Chips != 800 || len(p.Hands) != 2 {
+		t.Fatalf("got %d chips and %d hands, want 800 and 2", p.Chips, len(p.Hands))
+	}
+	for i, want := range []string{"8S 3C", "8D KH"} {
+		h := p.Hands[i]
+		if h.Cards.String() != cards(want).String() || h.Bet != 100 || !h.Split || h.Done {
+			t.Errorf("hand %d is %+v, want %s for 100", i, h, want)
+		}
+	}
+	if len(g.Shoe) != 2 {
+		t.Errorf("got %d cards left in the shoe, want 2", len(g.Shoe))
+	}
+}
+
+func TestBlackjackSplitAces(t *testing.T) {
+	g := newTestBlackjackGame()
+	g.Shoe = cards("KC 5H")
+	p := g.Players["a"]
+	p.Chips = 900
+	p.Hands = []BlackjackHand{{Cards: cards("AS AD"), Bet: 100}}
+
+	g.act(p, 0, SplitOption)
+	// Split aces get one card each and no more
+	for i, h := range p.Hands {
+		if !h.Done || len(h.Cards) != 2 || h.IsBlackjack() {
+			t.Errorf("hand %d is %+v, want it done with two cards and no blackjack", i, h)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+)
+
+type BlackjackOptions struct {
+	StartingChips int `json:"starting_chips"`
+	MinBet int `json:"min_bet"`
+	MaxBet int `json:"max_bet"`
+	// The game is over after this many hands, or once nobody can cover the smallest bet
+	Hands int `json:"hands"`
+	Decks int `json:"decks"`
+	// How much of the shoe, as a percentage, is dealt before it is shuffled again
+	Penetration int `json:"penetration"`
+	// The dealer stands on a soft 17 unless this is set
+	HitSoft17 bool `json:"hit_soft_17"`
+	Surrender bool `json:"surrender"`
+}
+
+func (o BlackjackOptions) Validate() error {
+	if o.MinBet < 1 || o.MaxBet < o.MinBet {
+		return fmt.Errorf("Smallest bet must be at least 1, and the largest at least the smallest")
+	}
+	if o.StartingChips < o.MinBet {
+		return fmt.Errorf("Players must start with at least the smallest bet")
+	}
+	if o.Hands < 1 {
+		return fmt.Errorf("Must play at least 1 hand")
+	}
+	if o.Decks < 1 || o.Decks > 8 {
+		return fmt.Errorf("Shoe must hold between 1 and 8 decks")
+	}
+	if o.Penetration < 1 || o.Penetration > 100 {
+		return fmt.Errorf("Penetration must be between 1 and 100 percent")
+	}
+	return nil
+}
+
+// CutCard is how many cards are left in the shoe when it is due to be shuffled
+func (o BlackjackOptions) CutCard() int {
+	return o.Decks * len(CardValues) * len(Suits) * (100 - o.Penetration) / 100
+}
+
+var BlackjackType = &GameType{
+	Name: "blackjack",
+	Title: "Blackjack",
+	MinPlayers: BlackjackMinPlayers,
+	MaxPlayers: BlackjackMaxPlayers,
+	Options: []Option{
+		{Key: "starting_chips", Label: "Starting chips", Type: NumberOption, Default: 1000, Min: 1},
+		{Key: "min_bet", Label: "Smallest bet", Type: NumberOption, Default: 10, Min: 1},
+		{Key: "max_bet", Label: "Largest bet", Type: NumberOption, Default: 500, Min: 1},
+		{Key: "hands", Label: "Hands to play", Type: NumberOption, Default: 20, Min: 1},
+		{Key: "decks", Label: "Decks in the shoe", Type: NumberOption, Default: 6, Min: 1, Max: 8},
+		{Key: "penetration", Label: "Percent of the shoe dealt before shuffling", Type: NumberOption, Default: 75, Min: 1, Max: 100},
+		{Key: "hit_soft_17", Label: "Dealer hits soft 17", Type: BoolOption, Default: false},
+		{Key: "surrender", Label: "Surrender allowed", Type: BoolOption, Default: true},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o BlackjackOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewBlackjackGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseBlackjackSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreBlackjackGame(s, deciders)
+	},
+	CPU: BlackjackCPU,
+	Validate: func(options Options) error {
+		var o BlackjackOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(BlackjackType)
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BlackjackSnapshot is everything needed to carry on a game of blackjack
+type BlackjackSnapshot struct {
+	TableSnapshot
+	Options BlackjackOptions `json:"options"`
+	Shoe Deck `json:"shoe"`
+	Shuffles int `json:"shuffles"`
+	Dealer Deck `json:"dealer"`
+	HoleShown bool `json:"holeShown"`
+	Players map[string]BlackjackPlayerSnapshot `json:"players"`
+}
+
+type BlackjackPlayerSnapshot struct {
+	Chips int `json:"chips"`
+	Bet int `json:"bet"`
+	Hands []BlackjackHand `json:"hands"`
+	Insurance int `json:"insurance"`
+	InsuranceOffered bool `json:"insuranceOffered"`
+	StartChips int `json:"startChips"`
+	Out bool `json:"out"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func copyHands(hands []BlackjackHand) []BlackjackHand {
+	copied := []BlackjackHand{}
+	for _, h := range hands {
+		h.Cards = h.Cards.Copy()
+		copied = append(copied, h)
+	}
+	return copied
+}
+
+func (g *BlackjackGame) Snapshot() *BlackjackSnapshot {
+	players := map[string]BlackjackPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = BlackjackPlayerSnapshot{
+			Chips: p.Chips,
+			Bet: p.Bet,
+			Hands: copyHands(p.Hands),
+			Insurance: p.Insurance,
+			InsuranceOffered: p.InsuranceOffered,
+			StartChips: p.StartChips,
+			Out: p.Out,
+			TimeBank: p.TimeBank(),
+		}
+	}
+
+	return &BlackjackSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Shoe: g.Shoe.Copy(),
+		Shuffles: g.Shuffles,
+		Dealer: g.Dealer.Copy(),
+		HoleShown: g.HoleShown,
+		Players: players,
+	}
+}
+
+func (g *BlackjackGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreBlackjackGame sets up a game as it was in the snapshot, with the deciders taking
+// the seats with their names
+func RestoreBlackjackGame(s *BlackjackSnapshot, deciders []Decider) (*BlackjackGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewBlackjackGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Shoe = s.Shoe.Copy()
+	g.Shuffles = s.Shuffles
+	g.Dealer = s.Dealer.Copy()
+	g.HoleShown = s.HoleShown
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Chips = ps.Chips
+		p.Bet = ps.Bet
+		p.Hands = copyHands(ps.Hands)
+		p.Insurance = ps.Insurance
+		p.InsuranceOffered = ps.InsuranceOffered
+		p.StartChips = ps.StartChips
+		p.Out = ps.Out
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks nobody has a negative number of chips or more hands than splitting allows, and that the dealer has a hand once the cards are out
+func (s *BlackjackSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(BlackjackMinPlayers, BlackjackMaxPlayers, BlackjackPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	for _, name := range s.PlayerOrder {
+		ps := s.Players[name]
+		if ps.Chips < 0 || ps.Bet < 0 || ps.Insurance < 0 {
+			return fmt.Errorf("[%v] has a negative number of chips", name)
+		}
+		if len(ps.Hands) > MaxSplitHands {
+			return fmt.Errorf("[%v] has more than %d hands", name, MaxSplitHands)
+		}
+	}
+	if s.Phase == InsurancePhase || s.Phase == PlayingPhase || s.Phase == ScoringPhase {
+		if len(s.Dealer) < 2 {
+			return fmt.Errorf("the dealer has not been dealt a hand")
+		}
+	}
+	return nil
+}
+
+func ParseBlackjackSnapshot(data []byte) (*BlackjackSnapshot, error) {
+	s := &BlackjackSnapshot{}
+	return s, parseSnapshot(data, s)
+}
@@ -0,0 +1,920 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	BridgePlayers = 4
+	BridgeHandSize = 13
+)
+
+// NoTrump is the strain of a contract played without trumps
+const NoTrump = Suit("notrump")
+
+// PlayFromDummyQuestion asks the declarer to play a card from dummy's hand
+const PlayFromDummyQuestion = Question("play_from_dummy")
+
+// BridgeStrains are in the order bids of the same level rank
+var BridgeStrains = []Suit{Clubs, Diamonds, Hearts, Spades, NoTrump}
+
+// A deal everyone passes on goes straight to scoring, and is scored at nothing
+var BridgePhases = PhaseTransitions{
+	DealingPhase: {BiddingPhase},
+	BiddingPhase: {PlayingPhase, ScoringPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+// Contract is what the auction ended on, played by the declarer with dummy's hand as well
+// as their own
+type Contract struct {
+	Level int `json:"level"`
+	Strain Suit `json:"strain"`
+	// 1 when doubled, 2 when redoubled
+	Doubled int `json:"doubled"`
+	Declarer string `json:"declarer"`
+}
+
+func (c Contract) String() string {
+	s := fmt.Sprintf("%d %v", c.Level, c.Strain)
+	switch c.Doubled {
+	case 1:
+		s += " doubled"
+	case 2:
+		s += " redoubled"
+	}
+	return s + " by " + c.Declarer
+}
+
+type AuctionEntry struct {
+	Player string `json:"player"`
+	Call BridgeCall `json:"call"`
+}
+
+type BridgePlayer struct {
+	*Seat
+	Hand Deck
+	// The hand as it was dealt, for scoring honors
+	Dealt Deck
+	TricksWon int
+}
+
+type BridgeTeam struct {
+	Name string
+	Players []string
+	Score int
+	// Points below the line towards the current game, and the games won in the current
+	// rubber
+	Below int
+	Games int
+}
+
+type BridgeGame struct {
+	Players map[string]*BridgePlayer
+	Teams []*BridgeTeam
+	Options BridgeOptions
+	Auction []AuctionEntry
+	// Set once the auction is over, unless everyone passed
+	Contract *Contract
+	// How many rubbers have been finished
+	Rubbers int
+	Table
+	TrickTaking
+}
+
+type BridgePlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Team string `json:"team"`
+	Bid string `json:"bid"`
+	TricksWon int `json:"tricksWon"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	Declarer bool `json:"declarer"`
+	Dummy bool `json:"dummy"`
+	// Dummy's hand is on the table for everyone once the opening lead is made
+	Hand Deck `json:"hand,omitempty"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type BridgeTeamInfo struct {
+	Name string `json:"name"`
+	Players []string `json:"players"`
+	Score int `json:"score"`
+	Below int `json:"below"`
+	Games int `json:"games"`
+	Vulnerable bool `json:"vulnerable"`
+}
+
+type BridgeGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]BridgePlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Teams []BridgeTeamInfo `json:"teams"`
+	Scoring BridgeScoring `json:"scoring"`
+	Auction []AuctionEntry `json:"auction"`
+	Contract *Contract `json:"contract,omitempty"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Trick int `json:"trick"`
+	Hand Deck `json:"hand"`
+	DummyHand Deck `json:"dummyHand,omitempty"`
+	LegalPlays Deck `json:"legalPlays"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewBridgeGame(deciders []Decider, seed int64, options BridgeOptions) *BridgeGame {
+	g := &BridgeGame{
+		Players: map[string]*BridgePlayer{},
+		Teams: []*BridgeTeam{},
+		Options: options,
+		Auction: []AuctionEntry{},
+	}
+	g.Init(g, seed, BridgePhases, DealingPhase, BridgeTimeout)
+	g.InitTricks(bridgeTricks{g: g})
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &BridgePlayer{Seat: s}
+	})
+	for i := 0; i < len(g.PlayerOrder) / 2; i++ {
+		partners := []string{g.PlayerOrder[i], g.PlayerOrder[i + len(g.PlayerOrder) / 2]}
+		g.Teams = append(g.Teams, &BridgeTeam{Name: strings.Join(partners, " & "), Players: partners})
+	}
+	return g
+}
+
+func (g *BridgeGame) GetDeciderInfo(decider Decider) interface{} {
+	dummy := g.Dummy()
+	playerInfo := map[string]BridgePlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		info := BridgePlayerInfo{
+			NumCards: len(p.Hand),
+			Score: g.Team(name).Score,
+			Team: g.Team(name).Name,
+			Bid: g.LastCall(name),
+			TricksWon: p.TricksWon,
+			Lead: name == g.Leader,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			Declarer: g.Contract != nil && name == g.Contract.Declarer,
+			Dummy: name == dummy,
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+		if name == dummy && g.DummyVisible() {
+			info.Hand = p.Hand
+		}
+		playerInfo[name] = info
+	}
+	teams := []BridgeTeamInfo{}
+	for _, t := range g.Teams {
+		teams = append(teams, BridgeTeamInfo{
+			Name: t.Name,
+			Players: t.Players,
+			Score: t.Score,
+			Below: t.Below,
+			Games: t.Games,
+			Vulnerable: g.Vulnerable(t),
+		})
+	}
+
+	var dummyHand Deck
+	if g.DummyVisible() {
+		dummyHand = g.Players[dummy].Hand
+	}
+
+	return &BridgeGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Teams: teams,
+		Scoring: g.Options.Scoring,
+		Auction: g.Auction,
+		Contract: g.Contract,
+		CurrentTrick: g.CurrentTrick,
+		Tricks: g.Tricks,
+		Trick: g.Trick,
+		Hand: g.Players[decider.GetName()].Hand,
+		DummyHand: dummyHand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (bg *BridgeGameInfo) String() string {
+	var b strings.Builder
+	for _, t := range bg.Teams {
+		fmt.Fprintf(&b, "( %v: %v", t.Name, t.Score)
+		if bg.Scoring == RubberScoring {
+			fmt.Fprintf(&b, ", %v below, %v games", t.Below, t.Games)
+		}
+		if t.Vulnerable {
+			b.WriteString(", vulnerable")
+		}
+		b.WriteString(" ) ")
+	}
+	b.WriteString("\nAuction: ")
+	for _, e := range bg.Auction {
+		fmt.Fprintf(&b, "( %v: %v ) ", e.Player, e.Call)
+	}
+	if bg.Contract != nil {
+		fmt.Fprintf(&b, "\nContract: %v", bg.Contract)
+		b.WriteString("\nTricks: ")
+		for _, name := range bg.PlayerOrder {
+			fmt.Fprintf(&b, "( %v: %v ) ", name, bg.PlayerInfo[name].TricksWon)
+		}
+	}
+	if bg.DummyHand != nil {
+		fmt.Fprintf(&b, "\nDummy: %v", bg.DummyHand)
+	}
+	fmt.Fprintf(&b, "\nCurrent Trick: %v\n", bg.CurrentTrick)
+	fmt.Fprintf(&b, "Hand: %v", bg.Hand.NumberedString())
+	return b.String()
+}
+
+func (c BridgeCall) String() string {
+	if c.Kind == BidCall {
+		return fmt.Sprintf("%d %v", c.Level, c.Strain)
+	}
+	return string(c.Kind)
+}
+
+// ParseBridgeCall reads a call written the way BridgeCall.String writes it
+func ParseBridgeCall(s string) (BridgeCall, bool) {
+	switch CallKind(s) {
+	case PassCall, DoubleCall, RedoubleCall:
+		return BridgeCall{Kind: CallKind(s)}, true
+	}
+	var level int
+	var strain string
+	if _, err := fmt.Sscanf(s, "%d %s", &level, &strain); err != nil {
+		return BridgeCall{}, false
+	}
+	return BridgeCall{Kind: BidCall, Level: level, Strain: Suit(strain)}, true
+}
+
+func strainIndex(s Suit) int {
+	for i, strain := range BridgeStrains {
+		if strain == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// outranks is true if the bid is higher than the other bid
+func (c BridgeCall) outranks(other BridgeCall) bool {
+	if c.Level != other.Level {
+		return c.Level > other.Level
+	}
+	return strainIndex(c.Strain) > strainIndex(other.Strain)
+}
+
+func (g *BridgeGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case BidQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: "Make a call",
+			Hand: p.Hand.Copy(),
+			Options: g.LegalCalls(name),
+		}
+	case PlayOnTrickQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	case PlayFromDummyQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card from dummy",
+			Hand: g.Players[g.Dummy()].Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *BridgeGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case BidQuestion:
+		call, ok := ParseBridgeCall(r.Option)
+		if !ok {
+			return nil
+		}
+		return call
+	case PlayOnTrickQuestion, PlayFromDummyQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	}
+	return nil
+}
+
+func (g *BridgeGame) GetPlayer(i int) *BridgePlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+func (g *BridgeGame) Team(name string) *BridgeTeam {
+	return g.Teams[g.GetOrder(name) % len(g.Teams)]
+}
+
+// Partner returns the name of the player sitting across
+func (g *BridgeGame) Partner(name string) string {
+	return g.PlayerOrder[(g.GetOrder(name) + g.NumPlayers() / 2) % g.NumPlayers()]
+}
+
+// Dealer is the seat of the player dealing this board, which moves to the left each board
+func (g *BridgeGame) Dealer() int {
+	return g.Round % g.NumPlayers()
+}
+
+// Dummy returns the declarer's partner, or nothing before there is a contract
+func (g *BridgeGame) Dummy() string {
+	if g.Contract == nil {
+		return ""
+	}
+	return g.Partner(g.Contract.Declarer)
+}
+
+// DummyVisible is true once the opening lead has been made
+func (g *BridgeGame) DummyVisible() bool {
+	return g.Contract != nil && (len(g.Tricks) > 0 || len(g.CurrentTrick) > 0)
+}
+
+// Vulnerable is true for a team that has won a game towards the rubber, or in duplicate
+// when the board says so
+func (g *BridgeGame) Vulnerable(t *BridgeTeam) bool {
+	if g.Options.Scoring == DuplicateScoring {
+		v := duplicateVulnerability[g.Round % len(duplicateVulnerability)]
+		return v == "both" || v == "ns" && t == g.Teams[0] || v == "ew" && t == g.Teams[1]
+	}
+	return t.Games > 0
+}
+
+// Finished is true once every rubber or board has been played
+func (g *BridgeGame) Finished() bool {
+	if g.Options.Scoring == DuplicateScoring {
+		return g.Round >= g.Options.Boards
+	}
+	return g.Rubbers >= g.Options.Rubbers
+}
+
+func (g *BridgeGame) GameOver() bool {
+	return g.Cancelled || g.Finished()
+}
+
+func (g *BridgeGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name := range g.Players {
+		scores[name] = g.Team(name).Score
+	}
+	return scores
+}
+
+func (g *BridgeGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+func (g *BridgeGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	if g.Phase == BiddingPhase {
+		if cancelled := g.RunAuction(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == PlayingPhase {
+		for g.Trick < BridgeHandSize {
+			if cancelled := g.PlayTrick(); cancelled {
+				return true
+			}
+		}
+		g.setPhase(ScoringPhase)
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Leader = ""
+	g.Round++
+	if g.Finished() {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal hands out the next board
+func (g *BridgeGame) Deal() {
+	g.StartTricks()
+	g.Auction = []AuctionEntry{}
+	g.Contract = nil
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.TricksWon = 0
+	}
+	d := NewDeck()
+	d.Shuffle(g.RoundRand())
+	pi := (g.Dealer() + 1) % g.NumPlayers()
+	for !d.Empty() {
+		g.GetPlayer(pi).Hand = append(g.GetPlayer(pi).Hand, d.Deal())
+		pi = (pi + 1) % g.NumPlayers()
+	}
+	for _, p := range g.Players {
+		p.Hand.Sort()
+		p.Dealt = p.Hand.Copy()
+	}
+	g.setPhase(BiddingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// lastBid returns the index of the last bid in the auction, or -1 if nobody has bid
+func (g *BridgeGame) lastBid() int {
+	for i := len(g.Auction) - 1; i >= 0; i-- {
+		if g.Auction[i].Call.Kind == BidCall {
+			return i
+		}
+	}
+	return -1
+}
+
+// doubled returns 1 if the last bid has been doubled, 2 if redoubled as well, or 0
+func (g *BridgeGame) doubled() int {
+	doubled := 0
+	for i := g.lastBid() + 1; i < len(g.Auction); i++ {
+		switch g.Auction[i].Call.Kind {
+		case DoubleCall:
+			doubled = 1
+		case RedoubleCall:
+			doubled = 2
+		}
+	}
+	return doubled
+}
+
+// LastCall is the player's latest call in the auction, empty if they have not made one
+func (g *BridgeGame) LastCall(name string) string {
+	for i := len(g.Auction) - 1; i >= 0; i-- {
+		if g.Auction[i].Player == name {
+			return g.Auction[i].Call.String()
+		}
+	}
+	return ""
+}
+
+// AuctionOver is true after three passes in a row follow a bid, or all four players pass
+func (g *BridgeGame) AuctionOver() bool {
+	n := len(g.Auction)
+	if n < g.NumPlayers() {
+		return false
+	}
+	for _, e := range g.Auction[n - 3:] {
+		if e.Call.Kind != PassCall {
+			return false
+		}
+	}
+	return g.lastBid() != -1 || n == g.NumPlayers()
+}
+
+// CheckCall returns the rule broken by the player making the call. A bid has to outrank
+// the last one, only the other side's bid can be doubled, and only a double of your own
+// side's bid can be redoubled.
+func (g *BridgeGame) CheckCall(name string, answer Answer) (BridgeCall, *RuleViolation) {
+	call, ok := answer.(BridgeCall)
+	if !ok {
+		return call, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	last := g.lastBid()
+	invalid := func(message string) (BridgeCall, *RuleViolation) {
+		return call, &RuleViolation{Code: InvalidBidViolation, Message: message}
+	}
+
+	switch call.Kind {
+	case PassCall:
+		return call, nil
+	case BidCall:
+		if call.Level < 1 || call.Level > 7 || strainIndex(call.Strain) == -1 {
+			return invalid("Bid from 1 to 7 of a suit or no trump")
+		}
+		if last != -1 && !call.outranks(g.Auction[last].Call) {
+			return invalid("Bid must be higher than " + g.Auction[last].Call.String())
+		}
+		return call, nil
+	case DoubleCall:
+		if last == -1 || g.Team(g.Auction[last].Player) == g.Team(name) || g.doubled() != 0 {
+			return invalid("Only an undoubled bid by the other side can be doubled")
+		}
+		return call, nil
+	case RedoubleCall:
+		if last == -1 || g.Team(g.Auction[last].Player) != g.Team(name) || g.doubled() != 1 {
+			return invalid("Only a double of your side's bid can be redoubled")
+		}
+		return call, nil
+	}
+	return invalid("[" + string(call.Kind) + "] is not a call")
+}
+
+// LegalCalls lists every call the player could make, written for prompts
+func (g *BridgeGame) LegalCalls(name string) []string {
+	calls := []BridgeCall{{Kind: PassCall}, {Kind: DoubleCall}, {Kind: RedoubleCall}}
+	for level := 1; level <= 7; level++ {
+		for _, s := range BridgeStrains {
+			calls = append(calls, BridgeCall{Kind: BidCall, Level: level, Strain: s})
+		}
+	}
+	legal := []string{}
+	for _, c := range calls {
+		if _, violation := g.CheckCall(name, c); violation == nil {
+			legal = append(legal, c.String())
+		}
+	}
+	return legal
+}
+
+// RunAuction goes round the table from the dealer, picking up after any calls already
+// made, until the auction is over. The declarer is whoever on the side that won the
+// contract first bid its strain, and the player to their left leads.
+func (g *BridgeGame) RunAuction() bool {
+	for !g.AuctionOver() {
+		p := g.GetPlayer((g.Dealer() + len(g.Auction)) % g.NumPlayers())
+		call, cancelled := p.MakeCall(g)
+		if cancelled {
+			return true
+		}
+		g.Auction = append(g.Auction, AuctionEntry{Player: p.GetName(), Call: call})
+		g.emit(CallMade{Round: g.Round, Player: p.GetName(), Call: call})
+		g.NotifyAll()
+	}
+
+	last := g.lastBid()
+	if last == -1 {
+		g.setPhase(ScoringPhase)
+		return false
+	}
+	final := g.Auction[last]
+	declarer := final.Player
+	for _, e := range g.Auction {
+		if e.Call.Kind == BidCall && e.Call.Strain == final.Call.Strain && g.Team(e.Player) == g.Team(final.Player) {
+			declarer = e.Player
+			break
+		}
+	}
+	g.Contract = &Contract{
+		Level: final.Call.Level,
+		Strain: final.Call.Strain,
+		Doubled: g.doubled(),
+		Declarer: declarer,
+	}
+	g.Leader = g.PlayerOrder[(g.GetOrder(declarer) + 1) % g.NumPlayers()]
+	g.setPhase(PlayingPhase)
+	return false
+}
+
+func (p *BridgePlayer) MakeCall(g *BridgeGame) (BridgeCall, bool) {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, BidQuestion, g)
+		if cancelled {
+			return BridgeCall{}, true
+		}
+		call, violation := g.CheckCall(p.GetName(), answer)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		return call, false
+	}
+}
+
+func (g *BridgeGame) PlayTrick() bool {
+	play := func(name string) (Card, bool) {
+		return g.PlayOnTrick(g.Players[name])
+	}
+	played := func(name string, card Card) {
+		g.emit(CardPlayed{Round: g.Round, Trick: g.Trick, Player: name, Card: card})
+		g.NotifyAll()
+	}
+	trick := g.Trick
+	won, cancelled := g.PlayCards(SeatsFrom(g.PlayerOrder, g.Leader), play, played)
+	if cancelled {
+		return true
+	}
+
+	g.Players[won.Winner].TricksWon++
+	g.emit(TrickWon{Round: g.Round, Trick: trick, Leader: won.Leader, Winner: won.Winner, Cards: won.Cards})
+	g.NotifyAll()
+
+	return false
+}
+
+// bridgeTricks are the rules of a bridge trick: the contract's strain is trumps, with
+// nothing trumps at no trump
+type bridgeTricks struct {
+	PlainTricks
+	g *BridgeGame
+}
+
+func (r bridgeTricks) TrumpSuit() Suit {
+	if r.g.Contract == nil {
+		return ""
+	}
+	return r.g.Contract.Strain
+}
+
+// PlayOnTrick has the player play a card, except for dummy, whose cards the declarer plays
+func (g *BridgeGame) PlayOnTrick(p *BridgePlayer) (Card, bool) {
+	asked, q := p, PlayOnTrickQuestion
+	if p.GetName() == g.Dummy() {
+		asked, q = g.Players[g.Contract.Declarer], PlayFromDummyQuestion
+	}
+	asked.StartTurn(&g.Table)
+	defer asked.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(asked.Seat, q, g)
+		if cancelled {
+			return Card{}, true
+		}
+
+		index, violation := ValidateIndex(p.Hand, answer)
+		if violation != nil {
+			ShowViolation(asked.Decider, violation)
+			continue
+		}
+
+		card := p.Hand[index]
+		if violation := g.CheckPlay(p.Hand, card); violation != nil {
+			ShowViolation(asked.Decider, violation)
+			continue
+		}
+
+		p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
+		return card, false
+	}
+}
+
+// LegalPlays returns the cards the player may play, from dummy's hand when they are the
+// declarer playing for dummy, or nothing if they are not being asked to play right now
+func (g *BridgeGame) LegalPlays(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok {
+		return Deck{}
+	}
+	var hand Deck
+	switch p.Asking() {
+	case PlayOnTrickQuestion:
+		hand = p.Hand
+	case PlayFromDummyQuestion:
+		hand = g.Players[g.Dummy()].Hand
+	}
+	return g.LegalCards(hand)
+}
+
+// ScoreRound scores the contract and returns the points each player's team scored on
+// the board. In rubber scoring, honors count for whoever held them, and the first team
+// to two games wins the rubber and its bonus.
+func (g *BridgeGame) ScoreRound() map[string]int {
+	roundPoints := map[string]int{}
+	for _, name := range g.PlayerOrder {
+		roundPoints[name] = 0
+	}
+	if g.Contract == nil {
+		return roundPoints
+	}
+
+	declarers := g.Team(g.Contract.Declarer)
+	defenders := g.Team(g.PlayerOrder[(g.GetOrder(g.Contract.Declarer) + 1) % g.NumPlayers()])
+	tricks := 0
+	for _, name := range declarers.Players {
+		tricks += g.Players[name].TricksWon
+	}
+	vulnerable := g.Vulnerable(declarers)
+	below, above, penalty := ContractScore(*g.Contract, tricks, vulnerable)
+	points := map[*BridgeTeam]int{declarers: below + above, defenders: penalty}
+
+	if g.Options.Scoring == DuplicateScoring {
+		switch {
+		case penalty > 0:
+		case below >= GamePoints && vulnerable:
+			points[declarers] += VulnerableGameBonus
+		case below >= GamePoints:
+			points[declarers] += GameBonus
+		default:
+			points[declarers] += PartScoreBonus
+		}
+	} else {
+		for name, p := range g.Players {
+			points[g.Team(name)] += HonorsScore(p.Dealt, g.Contract.Strain)
+		}
+		declarers.Below += below
+		if declarers.Below >= GamePoints {
+			declarers.Games++
+			for _, t := range g.Teams {
+				t.Below = 0
+			}
+		}
+		if declarers.Games == 2 {
+			if defenders.Games > 0 {
+				points[declarers] += SlowRubberBonus
+			} else {
+				points[declarers] += FastRubberBonus
+			}
+			for _, t := range g.Teams {
+				t.Games = 0
+			}
+			g.Rubbers++
+		}
+	}
+
+	for t, p := range points {
+		t.Score += p
+		for _, name := range t.Players {
+			roundPoints[name] = p
+		}
+	}
+	return roundPoints
+}
+
+// BridgeTimeout passes in the auction, so nobody is put in a contract they did not bid,
+// and plays the cards like BridgeCPU
+func BridgeTimeout(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	if _, ok := g.(*BridgeGame); ok && q == BidQuestion {
+		return BridgeCall{Kind: PassCall}
+	}
+	return BridgeCPU(r, d, q, g)
+}
+
+// BridgeCPU bids a simple natural system on high card points and suit length, never
+// doubling, and plays the lowest card that wins the trick, or else its lowest card
+func BridgeCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	bg, ok := g.(*BridgeGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	switch q {
+	case BidQuestion:
+		return bg.suggestCall(d.GetName())
+	case PlayOnTrickQuestion, PlayFromDummyQuestion:
+		hand := bg.Players[d.GetName()].Hand
+		if q == PlayFromDummyQuestion {
+			hand = bg.Players[bg.Dummy()].Hand
+		}
+		return CardPlay{hand.Index(bg.suggestPlay(hand))}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// HighCardPoints counts 4 for each ace, 3 for a king, 2 for a queen and 1 for a jack
+func HighCardPoints(hand Deck) int {
+	points := 0
+	for _, c := range hand {
+		switch c.Value {
+		case Ace:
+			points += 4
+		case King:
+			points += 3
+		case Queen:
+			points += 2
+		case Jack:
+			points++
+		}
+	}
+	return points
+}
+
+func suitLengths(hand Deck) map[Suit]int {
+	lengths := map[Suit]int{}
+	for _, c := range hand {
+		lengths[c.Suit]++
+	}
+	return lengths
+}
+
+func (g *BridgeGame) suggestCall(name string) BridgeCall {
+	hand := g.Players[name].Hand
+	points := HighCardPoints(hand)
+	lengths := suitLengths(hand)
+	longest := Clubs
+	doubletons := 0
+	balanced := true
+	for _, s := range []Suit{Clubs, Diamonds, Hearts, Spades} {
+		if lengths[s] >= lengths[longest] {
+			longest = s
+		}
+		if lengths[s] == 2 {
+			doubletons++
+		}
+		balanced = balanced && lengths[s] >= 2 && lengths[s] <= 5
+	}
+	balanced = balanced && doubletons <= 1
+
+	var partnerBid, ownBid *BridgeCall
+	for _, e := range g.Auction {
+		call := e.Call
+		if call.Kind != BidCall {
+			continue
+		}
+		switch e.Player {
+		case name:
+			ownBid = &call
+		case g.Partner(name):
+			partnerBid = &call
+		}
+	}
+
+	// lowest is the cheapest bid in the strain that outranks the auction so far
+	lowest := func(strain Suit) BridgeCall {
+		call := BridgeCall{Kind: BidCall, Level: 1, Strain: strain}
+		if last := g.lastBid(); last != -1 && !call.outranks(g.Auction[last].Call) {
+			call.Level = g.Auction[last].Call.Level
+			if !call.outranks(g.Auction[last].Call) {
+				call.Level++
+			}
+		}
+		return call
+	}
+
+	call := BridgeCall{Kind: PassCall}
+	switch {
+	case ownBid == nil && partnerBid == nil && g.lastBid() == -1 && points >= 15 && points <= 17 && balanced:
+		call = BridgeCall{Kind: BidCall, Level: 1, Strain: NoTrump}
+	case ownBid == nil && partnerBid == nil && points >= 13:
+		call = lowest(longest)
+	case ownBid == nil && partnerBid != nil && partnerBid.Strain == NoTrump && points >= 10:
+		call = BridgeCall{Kind: BidCall, Level: 3, Strain: NoTrump}
+	case ownBid == nil && partnerBid != nil && partnerBid.Strain != NoTrump && lengths[partnerBid.Strain] >= 3 && points >= 6:
+		call = lowest(partnerBid.Strain)
+		if points >= 13 && (partnerBid.Strain == Hearts || partnerBid.Strain == Spades) {
+			call.Level = 4
+		}
+	case ownBid == nil && partnerBid != nil && points >= 6:
+		call = lowest(longest)
+	}
+	if call.Level > 4 {
+		return BridgeCall{Kind: PassCall}
+	}
+	if _, violation := g.CheckCall(name, call); violation != nil {
+		return BridgeCall{Kind: PassCall}
+	}
+	return call
+}
+
+func (g *BridgeGame) suggestPlay(hand Deck) Card {
+	trump := g.Contract.Strain
+	var lowestWinner, lowest *Card
+	for i, c := range hand {
+		if g.CheckPlay(hand, c) != nil {
+			continue
+		}
+		card := &hand[i]
+		if lowest == nil || bridgeCardOrder(c, trump) < bridgeCardOrder(*lowest, trump) {
+			lowest = card
+		}
+		trick := append(g.CurrentTrick.Copy(), c)
+		wins := g.TrickWinner(trick) == len(trick) - 1
+		if wins && (lowestWinner == nil || bridgeCardOrder(c, trump) < bridgeCardOrder(*lowestWinner, trump)) {
+			lowestWinner = card
+		}
+	}
+	if lowestWinner != nil {
+		return *lowestWinner
+	}
+	return *lowest
+}
+
+// bridgeCardOrder ranks cards for throwing away, with trumps kept for last
+func bridgeCardOrder(c Card, trump Suit) int {
+	if c.Suit == trump {
+		return len(CardValues) + c.ValueIndex()
+	}
+	return c.ValueIndex()
+}
@@ -0,0 +1,20 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBridgeTimeoutPasses(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+	g := NewBridgeGame(deciders, 1, BridgeOptions{Scoring: RubberScoring, Rubbers: 1})
+	g.Players["a"].Hand = cards("AS KS QS JS AH KH QH AD KD AC KC QC JC")
+	r := rand.New(rand.NewSource(1))
+	// Even with a hand worth a slam, and with a bid to double
+	for _, auction := range [][]AuctionEntry{{}, {{Player: "d", Call: BridgeCall{Kind: BidCall, Level: 1, Strain: Hearts}}}} {
+		g.Auction = auction
+		if call := g.Fallback(r, deciders[0], BidQuestion, g); call != (BridgeCall{Kind: PassCall}) {
+			t.Errorf("timing out after %+v called %+v, want a pass", auction, call)
+		}
+	}
+}
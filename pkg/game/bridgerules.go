@@ -0,0 +1,185 @@
+package game
+
+import (
+	"fmt"
+)
+
+type BridgeScoring string
+const (
+	// Games are won by scoring 100 below the line, and the first side to win two games
+	// takes the rubber
+	RubberScoring = BridgeScoring("rubber")
+	// Every board is scored on its own, with bonuses for game and part scores in place of
+	// the rubber
+	DuplicateScoring = BridgeScoring("duplicate")
+)
+
+const (
+	GamePoints = 100
+	PartScoreBonus = 50
+	// Game bonuses in duplicate, and rubber bonuses for winning two games to none or to one
+	GameBonus = 300
+	VulnerableGameBonus = 500
+	FastRubberBonus = 700
+	SlowRubberBonus = 500
+)
+
+type BridgeOptions struct {
+	Scoring BridgeScoring `json:"scoring"`
+	// How many rubbers are played in rubber scoring, and how many boards in duplicate
+	Rubbers int `json:"rubbers"`
+	Boards int `json:"boards"`
+}
+
+func (o BridgeOptions) Validate() error {
+	if o.Scoring != RubberScoring && o.Scoring != DuplicateScoring {
+		return fmt.Errorf("[%v] is not a way to score bridge", o.Scoring)
+	}
+	if o.Rubbers < 1 || o.Boards < 1 {
+		return fmt.Errorf("Must play at least one rubber or board")
+	}
+	return nil
+}
+
+// duplicateVulnerability lists who is vulnerable on each of the 16 boards before they
+// repeat. The first seat is north, so north-south is the first team and east-west the
+// second.
+var duplicateVulnerability = []string{"", "ns", "ew", "both", "ns", "ew", "both", "", "ew", "both", "", "ns", "both", "", "ns", "ew"}
+
+// trickValue is what each trick over six is worth below the line, undoubled
+func trickValue(strain Suit, trick int) int {
+	switch strain {
+	case Clubs, Diamonds:
+		return 20
+	case NoTrump:
+		if trick == 1 {
+			return 40
+		}
+	}
+	return 30
+}
+
+// ContractScore returns the points the declaring side scores for making the contract
+// with the tricks, below and above the line, or if the contract goes down, the penalty
+// the defenders score. Game and rubber bonuses are left to the scoring in use.
+func ContractScore(c Contract, tricks int, vulnerable bool) (below int, above int, penalty int) {
+	multiplier := 1 << uint(c.Doubled)
+	need := c.Level + 6
+	if tricks < need {
+		for i := 1; i <= need - tricks; i++ {
+			switch {
+			case c.Doubled == 0 && vulnerable:
+				penalty += 100
+			case c.Doubled == 0:
+				penalty += 50
+			case i == 1 && vulnerable:
+				penalty += 200 * multiplier / 2
+			case i == 1:
+				penalty += 100 * multiplier / 2
+			case i <= 3 && !vulnerable:
+				penalty += 200 * multiplier / 2
+			default:
+				penalty += 300 * multiplier / 2
+			}
+		}
+		return 0, 0, penalty
+	}
+
+	for i := 1; i <= c.Level; i++ {
+		below += trickValue(c.Strain, i) * multiplier
+	}
+	for i := need + 1; i <= tricks; i++ {
+		switch {
+		case c.Doubled == 0:
+			above += trickValue(c.Strain, i - 6)
+		case vulnerable:
+			above += 200 * multiplier / 2
+		default:
+			above += 100 * multiplier / 2
+		}
+	}
+	above += 50 * c.Doubled
+	switch {
+	case c.Level == 7 && vulnerable:
+		above += 1500
+	case c.Level == 7:
+		above += 1000
+	case c.Level == 6 && vulnerable:
+		above += 750
+	case c.Level == 6:
+		above += 500
+	}
+	return below, above, 0
+}
+
+// HonorsScore returns the bonus for holding honors in one hand as dealt: 150 for all five
+// top trumps or all four aces in no trump, and 100 for four of the five top trumps
+func HonorsScore(hand Deck, strain Suit) int {
+	if strain == NoTrump {
+		aces := 0
+		for _, c := range hand {
+			if c.Value == Ace {
+				aces++
+			}
+		}
+		if aces == 4 {
+			return 150
+		}
+		return 0
+	}
+
+	honors := 0
+	for _, v := range []CardValue{Ace, King, Queen, Jack, Ten} {
+		if hand.Contains(v, strain) {
+			honors++
+		}
+	}
+	switch honors {
+	case 5:
+		return 150
+	case 4:
+		return 100
+	}
+	return 0
+}
+
+var BridgeType = &GameType{
+	Name: "bridge",
+	Title: "Contract Bridge",
+	MinPlayers: BridgePlayers,
+	MaxPlayers: BridgePlayers,
+	Options: []Option{
+		{Key: "scoring", Label: "Scoring", Type: ChoiceOption, Default: string(RubberScoring), Choices: []Choice{
+			{string(RubberScoring), "Rubber"},
+			{string(DuplicateScoring), "Duplicate"},
+		}},
+		{Key: "rubbers", Label: "Rubbers (rubber scoring)", Type: NumberOption, Default: 1, Min: 1},
+		{Key: "boards", Label: "Boards (duplicate scoring)", Type: NumberOption, Default: 8, Min: 1},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o BridgeOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewBridgeGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseBridgeSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreBridgeGame(s, deciders)
+	},
+	CPU: BridgeCPU,
+	Validate: func(options Options) error {
+		var o BridgeOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(BridgeType)
+}
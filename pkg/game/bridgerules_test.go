@@ -0,0 +1,70 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestContractScoreUndertricks(t *testing.T) {
+	tests := []struct {
+		doubled int
+		vulnerable bool
+		down int
+		want int
+	}{
+		{0, false, 1, 50},
+		{0, false, 3, 150},
+		{0, true, 2, 200},
+		{1, false, 1, 100},
+		{1, false, 2, 300},
+		{1, false, 3, 500},
+		{1, false, 4, 800},
+		{1, false, 5, 1100},
+		{1, true, 1, 200},
+		{1, true, 2, 500},
+		{1, true, 3, 800},
+		{1, true, 4, 1100},
+		{2, false, 1, 200},
+		{2, false, 2, 600},
+		{2, false, 3, 1000},
+		{2, false, 4, 1600},
+		{2, true, 1, 400},
+		{2, true, 2, 1000},
+		{2, true, 3, 1600},
+	}
+	for _, tt := range tests {
+		c := Contract{Level: 4, Strain: Spades, Doubled: tt.doubled}
+		below, above, penalty := ContractScore(c, 10 - tt.down, tt.vulnerable)
+		if below != 0 || above != 0 || penalty != tt.want {
+			t.Errorf("%v down %d, vulnerable %v: got %d %d %d, want a penalty of %d", c, tt.down, tt.vulnerable, below, above, penalty, tt.want)
+		}
+	}
+}
+
+func TestContractScoreMade(t *testing.T) {
+	tests := []struct {
+		contract Contract
+		vulnerable bool
+		tricks int
+		below, above int
+	}{
+		{Contract{Level: 2, Strain: Hearts}, false, 9, 60, 30},
+		{Contract{Level: 3, Strain: NoTrump}, false, 9, 100, 0},
+		{Contract{Level: 5, Strain: Clubs}, true, 11, 100, 0},
+		// Doubled and redoubled contracts double the tricks bid, add the insult and score
+		// overtricks by vulnerability
+		{Contract{Level: 4, Strain: Spades, Doubled: 1}, false, 10, 240, 50},
+		{Contract{Level: 2, Strain: Diamonds, Doubled: 1}, false, 10, 80, 250},
+		{Contract{Level: 2, Strain: Diamonds, Doubled: 1}, true, 9, 80, 250},
+		{Contract{Level: 1, Strain: NoTrump, Doubled: 2}, false, 8, 160, 300},
+		{Contract{Level: 1, Strain: NoTrump, Doubled: 2}, true, 8, 160, 500},
+		// Slams
+		{Contract{Level: 6, Strain: Hearts}, false, 12, 180, 500},
+		{Contract{Level: 7, Strain: NoTrump}, true, 13, 220, 1500},
+	}
+	for _, tt := range tests {
+		below, above, penalty := ContractScore(tt.contract, tt.tricks, tt.vulnerable)
+		if below != tt.below || above != tt.above || penalty != 0 {
+			t.Errorf("%v making %d, vulnerable %v: got %d %d %d, want %d %d 0", tt.contract, tt.tricks, tt.vulnerable, below, above, penalty, tt.below, tt.above)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BridgeSnapshot is everything needed to carry on a game of bridge
+type BridgeSnapshot struct {
+	TableSnapshot
+	Options BridgeOptions `json:"options"`
+	Trick int `json:"trick"`
+	Leader string `json:"leader"`
+	Auction []AuctionEntry `json:"auction"`
+	Contract *Contract `json:"contract,omitempty"`
+	Rubbers int `json:"rubbers"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Teams []BridgeTeamSnapshot `json:"teams"`
+	Players map[string]BridgePlayerSnapshot `json:"players"`
+}
+
+type BridgeTeamSnapshot struct {
+	Score int `json:"score"`
+	Below int `json:"below"`
+	Games int `json:"games"`
+}
+
+type BridgePlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Dealt Deck `json:"dealt"`
+	TricksWon int `json:"tricksWon"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *BridgeGame) Snapshot() *BridgeSnapshot {
+	players := map[string]BridgePlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = BridgePlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Dealt: p.Dealt.Copy(),
+			TricksWon: p.TricksWon,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	teams := []BridgeTeamSnapshot{}
+	for _, t := range g.Teams {
+		teams = append(teams, BridgeTeamSnapshot{Score: t.Score, Below: t.Below, Games: t.Games})
+	}
+	var contract *Contract
+	if g.Contract != nil {
+		c := *g.Contract
+		contract = &c
+	}
+
+	return &BridgeSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Trick: g.Trick,
+		Leader: g.Leader,
+		Auction: append([]AuctionEntry{}, g.Auction...),
+		Contract: contract,
+		Rubbers: g.Rubbers,
+		CurrentTrick: g.CurrentTrick.Copy(),
+		Tricks: append([]CompletedTrick{}, g.Tricks...),
+		Teams: teams,
+		Players: players,
+	}
+}
+
+func (g *BridgeGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreBridgeGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names
+func RestoreBridgeGame(s *BridgeSnapshot, deciders []Decider) (*BridgeGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewBridgeGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Trick = s.Trick
+	g.Leader = s.Leader
+	g.Auction = append([]AuctionEntry{}, s.Auction...)
+	if s.Contract != nil {
+		c := *s.Contract
+		g.Contract = &c
+	}
+	g.Rubbers = s.Rubbers
+	g.CurrentTrick = s.CurrentTrick.Copy()
+	g.Tricks = append([]CompletedTrick{}, s.Tricks...)
+	for i, ts := range s.Teams {
+		g.Teams[i].Score = ts.Score
+		g.Teams[i].Below = ts.Below
+		g.Teams[i].Games = ts.Games
+	}
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Dealt = ps.Dealt.Copy()
+		p.TricksWon = ps.TricksWon
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks there are four players split into two partnerships, and that play has a contract with a seated declarer and a real strain, and no more cards on the trick than players
+func (s *BridgeSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(BridgePlayers, BridgePlayers, BridgePhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if len(s.Teams) != BridgePlayers / 2 {
+		return fmt.Errorf("snapshot has %d teams", len(s.Teams))
+	}
+	if s.Phase == PlayingPhase {
+		if s.Contract == nil {
+			return fmt.Errorf("playing without a contract")
+		}
+		if _, ok := s.Players[s.Contract.Declarer]; !ok {
+			return fmt.Errorf("declarer [%v] is not seated", s.Contract.Declarer)
+		}
+		if strainIndex(s.Contract.Strain) == -1 {
+			return fmt.Errorf("[%v] is not a strain", s.Contract.Strain)
+		}
+		if len(s.CurrentTrick) > len(s.PlayerOrder) {
+			return fmt.Errorf("too many cards on the trick")
+		}
+	}
+	return nil
+}
+
+func ParseBridgeSnapshot(data []byte) (*BridgeSnapshot, error) {
+	s := &BridgeSnapshot{}
+	return s, parseSnapshot(data, s)
+}
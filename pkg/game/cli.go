@@ -2,8 +2,11 @@ package game
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type CLIPlayer struct {
@@ -11,27 +14,56 @@ type CLIPlayer struct {
 }
 
 func (p *CLIPlayer) Decide(q Question, g GameState) Answer {
-	hg := g.GetDeciderInfo(p).(*HeartsGameInfo)
-	p.Display(hg)
+	p.Display(g)
+	prompter, ok := g.(Prompter)
+	if !ok {
+		return nil
+	}
+	prompt := prompter.Prompt(p, q)
 
 	reader := bufio.NewReader(os.Stdin)
+	fmt.Println(prompt.Text)
 
-	switch q {
-	case PassCardsQuestion:
-		fmt.Printf("Passing %v. Pass 3 cards by index, separated by spaces: ", hg.PassDirection)
-		indices := make([]int, 3)
+	switch prompt.Kind {
+	case CardsPrompt:
+		fmt.Printf("Hand: %v\n", prompt.Hand.NumberedString())
+		fmt.Printf("Can pick: %v\n", prompt.Allowed)
+		if prompt.Count == 1 {
+			fmt.Print("Pick a card by index: ")
+		} else {
+			fmt.Printf("Pick %d cards by index, separated by spaces: ", prompt.Count)
+		}
 		input, _ := reader.ReadString('\n')
-		fmt.Sscanf(input, "%v %v %v", &indices[0], &indices[1], &indices[2])
-		return indices 
+		indices := []int{}
+		for _, field := range strings.Fields(input) {
+			i, err := strconv.Atoi(field)
+			if err != nil {
+				i = -1
+			}
+			indices = append(indices, i)
+		}
+		return prompter.Answer(q, Response{Cards: indices})
 
-	case PlayOnTrickQuestion:
-		fmt.Println("Play a card by index:")
-		var idx int
+	case OptionPrompt:
+		for i, option := range prompt.Options {
+			fmt.Printf("%d) %v\n", i, option)
+		}
+		fmt.Print("Pick an option by index or name: ")
 		input, _ := reader.ReadString('\n')
-		fmt.Sscanf(input, "%v", &idx)
-		
-		return idx
+		input = strings.TrimSpace(input)
+		if i, err := strconv.Atoi(input); err == nil && i >= 0 && i < len(prompt.Options) {
+			input = prompt.Options[i]
+		}
+		return prompter.Answer(q, Response{Option: input})
 
+	case NumberPrompt:
+		fmt.Printf("Pick a number from %d to %d: ", prompt.Min, prompt.Max)
+		input, _ := reader.ReadString('\n')
+		n, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil {
+			n = prompt.Min - 1
+		}
+		return prompter.Answer(q, Response{Number: n})
 	}
 
 	return nil
@@ -46,21 +78,18 @@ func (p *CLIPlayer) GetName() string {
 }
 
 func (p *CLIPlayer) Notify(g GameState) {
-	hg := g.GetDeciderInfo(p).(*HeartsGameInfo)
-	p.Display(hg)
+	p.Display(g)
 }
 
-
-func (p *CLIPlayer) Display(hg *HeartsGameInfo) {
+// Display prints the player's view of the game, using its String method if it has one
+func (p *CLIPlayer) Display(g GameState) {
 	fmt.Println("-------------")
 
-	fmt.Print("Scores: ")
-	for name, player := range hg.PlayerInfo {
-		fmt.Printf("( %v: %v ) ", name, player.Score)
+	info := g.GetDeciderInfo(p)
+	if s, ok := info.(fmt.Stringer); ok {
+		fmt.Println(s.String())
+		return
 	}
-	fmt.Println()
-
-	fmt.Printf("Current Trick: %v\n", hg.CurrentTrick)
-	
-	fmt.Printf("Hand: %v\n", hg.Hand.NumberedString())
-}
\ No newline at end of file
+	data, _ := json.MarshalIndent(info, "", "  ")
+	fmt.Println(string(data))
+}
@@ -2,39 +2,79 @@ package game
 
 import (
 	"math/rand"
+	"sort"
 )
 
 type RandomCPU struct {
 	ID string
+	Rand *rand.Rand
+	// Decides in place of RandomDecision, if set
+	Strategy Strategy
 }
 
-func RandomDecision(d Decider, q Question, g GameState) Answer {
-	hg := g.GetDeciderInfo(d).(*HeartsGameInfo)
+func NewRandomCPU(id string, seed int64) *RandomCPU {
+	return &RandomCPU{ID: id, Rand: rand.New(rand.NewSource(seed))}
+}
+
+// NewStrategyCPU makes a CPU that plays by the strategy, like a game type's CPU
+func NewStrategyCPU(id string, seed int64, strategy Strategy) *RandomCPU {
+	cpu := NewRandomCPU(id, seed)
+	cpu.Strategy = strategy
+	return cpu
+}
 
-	switch q {
-	case PassCardsQuestion:
-		nums := map[int]interface{}{}
-		for len(nums) < 3 {
-			nums[rand.Int() % 13] = 0
+// RandomDecision picks at random from whatever the game's prompt allows, so it can play
+// any game
+func RandomDecision(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	p, ok := g.(Prompter)
+	if !ok {
+		return nil
+	}
+	return p.Answer(q, RandomResponse(r, p.Prompt(d, q)))
+}
+
+func RandomResponse(r *rand.Rand, p Prompt) Response {
+	switch p.Kind {
+	case CardsPrompt:
+		// Picked by place in the hand, since with more than one deck the same card can be
+		// held twice
+		indices := []int{}
+		for i, c := range p.Hand {
+			if p.Allowed.Contains(c.Value, c.Suit) {
+				indices = append(indices, i)
+			}
+		}
+		if p.Count > len(indices) {
+			return Response{}
 		}
-		numsSlice := []int{}
-		for num := range nums {
-			numsSlice = append(numsSlice, num) 
+		r.Shuffle(len(indices), func(i, j int) {
+			indices[i], indices[j] = indices[j], indices[i]
+		})
+		picked := indices[:p.Count]
+		sort.Ints(picked)
+		return Response{Cards: picked}
+
+	case OptionPrompt:
+		if len(p.Options) == 0 {
+			return Response{}
 		}
-		return numsSlice
+		return Response{Option: p.Options[r.Intn(len(p.Options))]}
 
-	case PlayOnTrickQuestion:
-		if len(hg.Hand) == 13 && hg.Hand.Contains(Two, Clubs) {
-			return 0
+	case NumberPrompt:
+		if p.Max < p.Min {
+			return Response{}
 		}
-		return rand.Int() % len(hg.Hand)
+		return Response{Number: p.Min + r.Intn(p.Max - p.Min + 1)}
 	}
 
-	return nil
+	return Response{}
 }
 
 func (cpu *RandomCPU) Decide(q Question, g GameState) Answer {
-	return RandomDecision(cpu, q, g)
+	if cpu.Strategy != nil {
+		return cpu.Strategy(cpu.Rand, cpu, q, g)
+	}
+	return RandomDecision(cpu.Rand, cpu, q, g)
 }
 
 func (cpu *RandomCPU) ShowInfo(string) {}
@@ -0,0 +1,620 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	CrazyEightsMinPlayers = 2
+	CrazyEightsMaxPlayers = 7
+	// Two players are dealt seven cards each, and more players five
+	TwoPlayerHandSize = 7
+	CrazyEightsHandSize = 5
+	// What an eight left in hand costs its holder
+	EightPoints = 50
+	DrawTwoCards = 2
+)
+
+const (
+	PlayCardQuestion = Question("play_card")
+	PlayDrawnQuestion = Question("play_drawn")
+	DeclareSuitQuestion = Question("declare_suit")
+)
+
+const (
+	PlayOption = "play"
+	KeepOption = "keep"
+)
+
+// A hand ends when a player goes out, or when nobody can play or draw
+var CrazyEightsPhases = PhaseTransitions{
+	DealingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type CrazyEightsPlayer struct {
+	*Seat
+	Hand Deck
+	Score int
+}
+
+type CrazyEightsGame struct {
+	Players map[string]*CrazyEightsPlayer
+	Options CrazyEightsOptions
+	Stock Deck
+	DiscardPile Deck
+	// Reshuffles counts the times the discard pile went back into the stock this hand, so
+	// each reshuffle can be reproduced
+	Reshuffles int
+	Turn string
+	// Play goes to the left while Direction is 1, and to the right when reversed
+	Direction int
+	// The suit called with the last eight, which has to be followed in its place
+	Suit Suit
+	// A card drawn this turn that the player is deciding whether to play, and whether the
+	// player is calling a suit for the eight they just played
+	Drawn *Card
+	Declaring bool
+	// Passes counts players in a row who could neither play nor draw
+	Passes int
+	Table
+}
+
+type CrazyEightsPlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type CrazyEightsGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]CrazyEightsPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Discard *Card `json:"discard,omitempty"`
+	StockSize int `json:"stockSize"`
+	Suit Suit `json:"suit,omitempty"`
+	Reversed bool `json:"reversed"`
+	TargetScore int `json:"targetScore"`
+	Hand Deck `json:"hand"`
+	LegalPlays Deck `json:"legalPlays"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewCrazyEightsGame(deciders []Decider, seed int64, options CrazyEightsOptions) *CrazyEightsGame {
+	g := &CrazyEightsGame{
+		Players: map[string]*CrazyEightsPlayer{},
+		Options: options,
+		Direction: 1,
+	}
+	g.Init(g, seed, CrazyEightsPhases, DealingPhase, RandomDecision)
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &CrazyEightsPlayer{Seat: s}
+	})
+	return g
+}
+
+func (g *CrazyEightsGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]CrazyEightsPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		playerInfo[name] = CrazyEightsPlayerInfo{
+			NumCards: len(p.Hand),
+			Score: p.Score,
+			Lead: name == g.Turn,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+	}
+
+	var discard *Card
+	if len(g.DiscardPile) > 0 {
+		c := g.DiscardPile[len(g.DiscardPile) - 1]
+		discard = &c
+	}
+
+	return &CrazyEightsGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Discard: discard,
+		StockSize: len(g.Stock),
+		Suit: g.Suit,
+		Reversed: g.Direction < 0,
+		TargetScore: g.Options.TargetScore,
+		Hand: g.Players[decider.GetName()].Hand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (cg *CrazyEightsGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range cg.PlayerOrder {
+		info := cg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v, %v cards ) ", name, info.Score, info.NumCards)
+	}
+	fmt.Fprintf(&b, "\nStock: %v Discard: ", cg.StockSize)
+	if cg.Discard != nil {
+		b.WriteString(cg.Discard.String())
+	}
+	if cg.Suit != "" {
+		fmt.Fprintf(&b, " Suit: %v", cg.Suit)
+	}
+	fmt.Fprintf(&b, "\nHand: %v", cg.Hand)
+	return b.String()
+}
+
+// CrazyEightsValue is what a card left in hand scores for the player who went out
+func CrazyEightsValue(c Card) int {
+	if c.Value == Eight {
+		return EightPoints
+	}
+	return c.PipValue()
+}
+
+// Top is the card on top of the discard pile
+func (g *CrazyEightsGame) Top() Card {
+	return g.DiscardPile[len(g.DiscardPile) - 1]
+}
+
+// CanPlay is true for eights, and cards matching the top of the discard pile in value or
+// in suit, or the suit called in its place if it is an eight
+func (g *CrazyEightsGame) CanPlay(c Card) bool {
+	top := g.Top()
+	suit := top.Suit
+	if g.Suit != "" {
+		suit = g.Suit
+	}
+	return c.Value == Eight || c.Value == top.Value || c.Suit == suit
+}
+
+func (g *CrazyEightsGame) LegalPlays(name string) Deck {
+	legal := Deck{}
+	if len(g.DiscardPile) == 0 {
+		return legal
+	}
+	for _, c := range g.Players[name].Hand {
+		if g.CanPlay(c) {
+			legal = append(legal, c)
+		}
+	}
+	return legal
+}
+
+func (g *CrazyEightsGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case PlayCardQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	case PlayDrawnQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Play the %v you drew?", *g.Drawn),
+			Hand: p.Hand.Copy(),
+			Options: []string{PlayOption, KeepOption},
+		}
+	case DeclareSuitQuestion:
+		options := []string{}
+		for _, s := range Suits {
+			options = append(options, string(s))
+		}
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: "Call the suit to follow your eight",
+			Hand: p.Hand.Copy(),
+			Options: options,
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *CrazyEightsGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case PlayCardQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	case PlayDrawnQuestion:
+		return PlayDrawn{Play: r.Option == PlayOption}
+	case DeclareSuitQuestion:
+		return SuitChoice{Suit: Suit(r.Option)}
+	}
+	return nil
+}
+
+func (g *CrazyEightsGame) GetPlayer(i int) *CrazyEightsPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+func (g *CrazyEightsGame) Dealer() int {
+	return g.Round % g.NumPlayers()
+}
+
+// nextPlayer returns the player the given number of places on from the player whose turn
+// it is, in the direction of play
+func (g *CrazyEightsGame) nextPlayer(places int) *CrazyEightsPlayer {
+	n := g.NumPlayers()
+	return g.GetPlayer(((g.GetOrder(g.Turn) + places * g.Direction) % n + n) % n)
+}
+
+func (g *CrazyEightsGame) Winner() *CrazyEightsPlayer {
+	for _, p := range g.Players {
+		if p.Score >= g.Options.TargetScore {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *CrazyEightsGame) GameOver() bool {
+	return g.Cancelled || g.Winner() != nil
+}
+
+func (g *CrazyEightsGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Score
+	}
+	return scores
+}
+
+func (g *CrazyEightsGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+// ShuffleRand returns the random source for the deal, or for a reshuffle of the discard
+// pile, so every shuffle can be reproduced on its own
+func (g *CrazyEightsGame) ShuffleRand() *rand.Rand {
+	return g.SeededRand(int64(g.Round) + int64(g.Reshuffles) << 32)
+}
+
+func (g *CrazyEightsGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	for g.Phase == PlayingPhase {
+		if cancelled := g.Players[g.Turn].TakeTurn(g); cancelled {
+			return true
+		}
+		g.NotifyAll()
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Turn = ""
+	g.Round++
+	if g.Winner() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal gives everyone their cards from the left of the dealer and turns up the top card of
+// the stock to start the discard pile. An eight turned up goes back to the bottom of the
+// stock, so nobody has to follow a suit that was never called.
+func (g *CrazyEightsGame) Deal() {
+	g.Reshuffles, g.Passes = 0, 0
+	g.Direction, g.Suit, g.Drawn, g.Declaring = 1, "", nil, false
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+	}
+	handSize := CrazyEightsHandSize
+	if g.NumPlayers() == 2 {
+		handSize = TwoPlayerHandSize
+	}
+	g.Stock = NewDeck()
+	g.Stock.Shuffle(g.ShuffleRand())
+	for i := 1; i <= handSize * g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		p.Hand = append(p.Hand, g.Stock.Deal())
+	}
+	for g.Stock[0].Value == Eight {
+		g.Stock = append(g.Stock[1:], g.Stock[0])
+	}
+	g.DiscardPile = Deck{g.Stock.Deal()}
+	for _, p := range g.Players {
+		p.Hand.Sort()
+	}
+	g.Turn = g.PlayerOrder[(g.Dealer() + 1) % g.NumPlayers()]
+	g.setPhase(PlayingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// drawCard takes the top card of the stock, first shuffling the discard pile under its top
+// card back into the stock if the stock is empty. It returns false if there is nothing to
+// draw at all.
+func (g *CrazyEightsGame) drawCard(p *CrazyEightsPlayer, events *[]Event) (Card, bool) {
+	if len(g.Stock) == 0 {
+		if len(g.DiscardPile) < 2 {
+			return Card{}, false
+		}
+		g.Reshuffles++
+		g.Stock = g.DiscardPile[:len(g.DiscardPile) - 1].Copy()
+		g.Stock.Shuffle(g.ShuffleRand())
+		g.DiscardPile = Deck{g.Top()}
+		*events = append(*events, PileReshuffled{Round: g.Round, Cards: len(g.Stock)})
+	}
+	c := g.Stock.Deal()
+	p.Hand = append(p.Hand, c)
+	p.Hand.Sort()
+	*events = append(*events, CardDrawn{Round: g.Round, Player: p.GetName()})
+	return c, true
+}
+
+// TakeTurn has the player play a card if they can. If they can't they draw one, and can
+// play it if it fits. It picks up partway through a turn that was already started.
+func (p *CrazyEightsPlayer) TakeTurn(g *CrazyEightsGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+
+	switch {
+	case g.Declaring:
+		return p.DeclareSuit(g)
+	case g.Drawn != nil:
+		return p.PlayDrawn(g)
+	case len(g.LegalPlays(p.GetName())) > 0:
+		return p.PlayCard(g)
+	}
+
+	events := []Event{}
+	c, drew := g.drawCard(p, &events)
+	switch {
+	case !drew:
+		g.Passes++
+		if g.Passes >= g.NumPlayers() {
+			g.setPhase(ScoringPhase)
+		} else {
+			g.Turn = g.nextPlayer(1).GetName()
+		}
+	case g.CanPlay(c):
+		g.Drawn = &c
+	default:
+		g.Passes = 0
+		g.Turn = g.nextPlayer(1).GetName()
+	}
+	for _, e := range events {
+		g.emit(e)
+	}
+	if g.Drawn != nil {
+		g.NotifyAll()
+		return p.PlayDrawn(g)
+	}
+	return false
+}
+
+func (p *CrazyEightsPlayer) PlayCard(g *CrazyEightsGame) bool {
+	for {
+		answer, cancelled := g.Ask(p.Seat, PlayCardQuestion, g)
+		if cancelled {
+			return true
+		}
+		index, violation := ValidateIndex(p.Hand, answer)
+		if violation == nil && !g.CanPlay(p.Hand[index]) {
+			violation = g.mustMatch()
+		}
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		return g.play(p, p.Hand[index])
+	}
+}
+
+// mustMatch explains what can be played on the discard pile
+func (g *CrazyEightsGame) mustMatch() *RuleViolation {
+	top := g.Top()
+	suit := top.Suit
+	if g.Suit != "" {
+		suit = g.Suit
+	}
+	return &RuleViolation{
+		Code: MustMatchViolation,
+		Message: fmt.Sprintf("Play a %v, a %v or an eight", suit, top.Value),
+		Card: &top,
+		Suit: suit,
+	}
+}
+
+// PlayDrawn asks the player whether to play the card they just drew
+func (p *CrazyEightsPlayer) PlayDrawn(g *CrazyEightsGame) bool {
+	answer, cancelled := g.Ask(p.Seat, PlayDrawnQuestion, g)
+	if cancelled {
+		return true
+	}
+	c := *g.Drawn
+	g.Drawn = nil
+	if choice, _ := answer.(PlayDrawn); choice.Play {
+		return g.play(p, c)
+	}
+	g.Passes = 0
+	g.Turn = g.nextPlayer(1).GetName()
+	return false
+}
+
+// play puts the card on the discard pile. An eight has its holder call a suit, unless it
+// was their last card, and any other card takes effect straight away.
+func (g *CrazyEightsGame) play(p *CrazyEightsPlayer, c Card) bool {
+	p.Hand = p.Hand.Without(Deck{c})
+	g.DiscardPile = append(g.DiscardPile, c)
+	g.Suit, g.Passes = "", 0
+	discarded := CardDiscarded{Round: g.Round, Player: p.GetName(), Card: c}
+	if c.Value == Eight && len(p.Hand) > 0 {
+		g.Declaring = true
+		g.emit(discarded)
+		g.NotifyAll()
+		return p.DeclareSuit(g)
+	}
+	events := g.takeEffect(p, c)
+	g.emit(discarded)
+	for _, e := range events {
+		g.emit(e)
+	}
+	return false
+}
+
+func (p *CrazyEightsPlayer) DeclareSuit(g *CrazyEightsGame) bool {
+	for {
+		answer, cancelled := g.Ask(p.Seat, DeclareSuitQuestion, g)
+		if cancelled {
+			return true
+		}
+		choice, ok := answer.(SuitChoice)
+		if !ok || (Card{Suit: choice.Suit}).SuitIndex() == -1 {
+			ShowViolation(p.Decider, &RuleViolation{Code: InvalidSuitViolation, Message: "Call one of the four suits"})
+			continue
+		}
+		g.Suit = choice.Suit
+		g.Declaring = false
+		events := g.takeEffect(p, g.Top())
+		g.emit(SuitDeclared{Round: g.Round, Player: p.GetName(), Suit: choice.Suit})
+		for _, e := range events {
+			g.emit(e)
+		}
+		return false
+	}
+}
+
+// takeEffect ends the hand if the player has gone out, or else passes the turn on, first
+// carrying out whichever special card rules are in play. It returns the events to announce.
+func (g *CrazyEightsGame) takeEffect(p *CrazyEightsPlayer, c Card) []Event {
+	events := []Event{}
+	if len(p.Hand) == 0 {
+		g.setPhase(ScoringPhase)
+		return events
+	}
+
+	places := 1
+	switch {
+	case c.Value == g.Options.SkipCard():
+		places = 2
+	case c.Value == g.Options.ReverseCard():
+		g.Direction = -g.Direction
+		// With two players a reverse comes straight back round, the same as a skip
+		if g.NumPlayers() == 2 {
+			places = 2
+		}
+	case c.Value == g.Options.DrawTwoCard():
+		victim := g.nextPlayer(1)
+		for i := 0; i < DrawTwoCards; i++ {
+			if _, drew := g.drawCard(victim, &events); !drew {
+				break
+			}
+		}
+		places = 2
+	}
+	g.Turn = g.nextPlayer(places).GetName()
+	return events
+}
+
+// ScoreRound gives the player who went out the value of every card left in the other
+// hands. If nobody could go out, the player left holding the least wins the hand.
+func (g *CrazyEightsGame) ScoreRound() map[string]int {
+	var winner *CrazyEightsPlayer
+	for i := 1; i <= g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		if winner == nil || handValue(p.Hand) < handValue(winner.Hand) {
+			winner = p
+		}
+	}
+	points := 0
+	roundPoints := map[string]int{}
+	for name, p := range g.Players {
+		roundPoints[name] = 0
+		if p != winner {
+			points += handValue(p.Hand)
+		}
+	}
+	roundPoints[winner.GetName()] = points
+	winner.Score += points
+	return roundPoints
+}
+
+func handValue(hand Deck) int {
+	value := 0
+	for _, c := range hand {
+		value += CrazyEightsValue(c)
+	}
+	return value
+}
+
+// CrazyEightsCPU saves its eights for when it has nothing else, plays special cards when
+// it can, and otherwise sheds the suit it holds most of. It calls the suit it holds most
+// of, and always plays a card it draws.
+func CrazyEightsCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	cg, ok := g.(*CrazyEightsGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	hand := cg.Players[d.GetName()].Hand
+	suits := map[Suit]int{}
+	for _, c := range hand {
+		if c.Value != Eight {
+			suits[c.Suit]++
+		}
+	}
+
+	switch q {
+	case PlayCardQuestion:
+		best, bestValue := -1, 0
+		for i, c := range hand {
+			if !cg.CanPlay(c) {
+				continue
+			}
+			value := suits[c.Suit]
+			switch c.Value {
+			case Eight:
+				value = -1
+			case cg.Options.SkipCard(), cg.Options.ReverseCard(), cg.Options.DrawTwoCard():
+				value += 10
+			}
+			if best == -1 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		return CardPlay{best}
+	case PlayDrawnQuestion:
+		return PlayDrawn{Play: true}
+	case DeclareSuitQuestion:
+		best := Suits[0]
+		for _, s := range Suits {
+			if suits[s] > suits[best] {
+				best = s
+			}
+		}
+		return SuitChoice{Suit: best}
+	}
+	return RandomDecision(r, d, q, g)
+}
@@ -0,0 +1,176 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// newTestCrazyEightsGame seats random CPUs named a, b, c and on, with a 5 of hearts turned
+// up and a few cards in the stock
+func newTestCrazyEightsGame(players int, options CrazyEightsOptions) *CrazyEightsGame {
+	deciders := []Decider{}
+	for i := 0; i < players; i++ {
+		deciders = append(deciders, NewRandomCPU(string(rune('a' + i)), int64(i)))
+	}
+	options.TargetScore = 100
+	g := NewCrazyEightsGame(deciders, 1, options)
+	g.Stock, g.DiscardPile = cards("3C 4C 5C"), cards("5H")
+	g.Turn = "a"
+	g.Phase = PlayingPhase
+	for _, p := range g.Players {
+		p.Hand = cards("KS")
+	}
+	return g
+}
+
+func TestCrazyEightsEightTurnedUp(t *testing.T) {
+	turnedUp := 0
+	for seed := int64(0); seed < 200; seed++ {
+		g := NewCrazyEightsGame([]Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2)}, seed, CrazyEightsOptions{TargetScore: 100})
+		deck := NewDeck()
+		deck.Shuffle(g.ShuffleRand())
+		g.Deal()
+
+		if g.Top().Value == Eight {
+			t.Errorf("seed %d turned up the %v", seed, g.Top())
+		}
+		if len(g.Stock) + len(g.DiscardPile) + 2 * TwoPlayerHandSize != len(NewDeck()) {
+			t.Errorf("seed %d lost cards in the deal", seed)
+		}
+		// The eight that would have been turned up is at the bottom of the stock
+		if up := deck[2 * TwoPlayerHandSize]; up.Value == Eight {
+			turnedUp++
+			if bottom := g.Stock[len(g.Stock) - 1]; bottom.Value != Eight {
+				t.Errorf("seed %d turned up the %v, but has the %v at the bottom of the stock", seed, up, bottom)
+			}
+		}
+	}
+	if turnedUp == 0 {
+		t.Errorf("no seed turned up an eight")
+	}
+}
+
+func TestCrazyEightsSpecialCards(t *testing.T) {
+	tests := []struct {
+		name string
+		players int
+		options CrazyEightsOptions
+		card string
+		turn string
+		direction int
+		drew int
+	}{
+		{"plain card", 3, CrazyEightsOptions{Skip: true, Reverse: true, DrawTwo: true}, "5D", "b", 1, 0},
+		{"skip", 3, CrazyEightsOptions{Skip: true}, "QH", "c", 1, 0},
+		{"queen without skips", 3, CrazyEightsOptions{}, "QH", "b", 1, 0},
+		{"reverse", 3, CrazyEightsOptions{Reverse: true}, "AH", "c", -1, 0},
+		// With two players a reverse comes straight back round
+		{"reverse with two players", 2, CrazyEightsOptions{Reverse: true}, "AH", "a", -1, 0},
+		{"draw two", 3, CrazyEightsOptions{DrawTwo: true}, "2H", "c", 1, 2},
+		{"two without draw two", 3, CrazyEightsOptions{}, "2H", "b", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestCrazyEightsGame(tt.players, tt.options)
+			events := g.takeEffect(g.Players["a"], cards(tt.card)[0])
+			if g.Turn != tt.turn || g.Direction != tt.direction {
+				t.Errorf("got %s to play going %d, want %s going %d", g.Turn, g.Direction, tt.turn, tt.direction)
+			}
+			if drew := len(g.Players["b"].Hand) - 1; drew != tt.drew || len(events) != tt.drew {
+				t.Errorf("b drew %d cards with %d events, want %d", drew, len(events), tt.drew)
+			}
+		})
+	}
+}
+
+func TestCrazyEightsReshuffle(t *testing.T) {
+	g := newTestCrazyEightsGame(2, CrazyEightsOptions{})
+	g.Stock, g.DiscardPile = Deck{}, cards("3C 4C 5C 6C")
+	events := []Event{}
+	if _, drew := g.drawCard(g.Players["a"], &events); !drew {
+		t.Fatalf("nothing was drawn")
+	}
+	// The top of the discard pile stays, and the rest are shuffled into the stock
+	if g.DiscardPile.String() != cards("6C").String() || len(g.Stock) != 2 || g.Reshuffles != 1 {
+		t.Errorf("got a stock of %v and a discard pile of %v after %d reshuffles", g.Stock, g.DiscardPile, g.Reshuffles)
+	}
+	if len(events) != 2 || events[0].Type() != PileReshuffledEvent || events[1].Type() != CardDrawnEvent {
+		t.Errorf("got events %v, want the pile reshuffled then a card drawn", events)
+	}
+
+	// With only the top card left there is nothing to draw
+	g.Stock, g.DiscardPile = Deck{}, cards("6C")
+	if _, drew := g.drawCard(g.Players["a"], &events); drew {
+		t.Errorf("drew from an empty stock with nothing to reshuffle")
+	}
+}
+
+func TestCrazyEightsBlocked(t *testing.T) {
+	g := newTestCrazyEightsGame(3, CrazyEightsOptions{})
+	g.Stock = Deck{}
+	for name, hand := range map[string]string{"a": "3C", "b": "KC 2D", "c": "9S 4C"} {
+		g.Players[name].Hand = cards(hand)
+	}
+	// Nobody can play on the 5 of hearts or draw, so the hand ends once everyone has passed
+	for i := 0; g.Phase == PlayingPhase; i++ {
+		if i == 3 {
+			t.Fatalf("still playing after everyone passed")
+		}
+		if cancelled := g.Players[g.Turn].TakeTurn(g); cancelled {
+			t.Fatalf("the turn was cancelled")
+		}
+	}
+	if g.Phase != ScoringPhase || g.Passes != 3 {
+		t.Fatalf("got %v after %d passes, want scoring after 3", g.Phase, g.Passes)
+	}
+	// The player holding the least scores everyone else's cards
+	points := g.ScoreRound()
+	if points["a"] != 25 || points["b"] != 0 || points["c"] != 0 || g.Players["a"].Score != 25 {
+		t.Errorf("got %v, want a to score 25", points)
+	}
+}
+
+func TestCrazyEightsDrawAndDeclare(t *testing.T) {
+	tests := []struct {
+		name string
+		answers []Answer
+		hand string
+		suit Suit
+	}{
+		{"plays the drawn eight", []Answer{PlayDrawn{true}, SuitChoice{"stars"}, SuitChoice{Clubs}}, "3D KC", Clubs},
+		{"keeps the drawn eight", []Answer{PlayDrawn{false}}, "3D 8S KC", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := tt.answers
+			asked := []Question{}
+			scripted := func(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+				asked = append(asked, q)
+				if len(answers) == 0 {
+					t.Errorf("asked %s with nothing left to answer", q)
+					return nil
+				}
+				answer := answers[0]
+				answers = answers[1:]
+				return answer
+			}
+			g := NewCrazyEightsGame([]Decider{NewStrategyCPU("a", 1, scripted), NewRandomCPU("b", 2)}, 1, CrazyEightsOptions{TargetScore: 100})
+			g.Stock, g.DiscardPile, g.Turn, g.Phase = cards("8S 4C"), cards("5H"), "a", PlayingPhase
+			g.Players["a"].Hand, g.Players["b"].Hand = cards("KC 3D"), cards("KS")
+
+			if cancelled := g.Players["a"].TakeTurn(g); cancelled {
+				t.Fatalf("the turn was cancelled")
+			}
+			if len(answers) != 0 {
+				t.Errorf("got %d answers left after being asked %v", len(answers), asked)
+			}
+			hand := g.Players["a"].Hand
+			hand.Sort()
+			want := cards(tt.hand)
+			want.Sort()
+			if hand.String() != want.String() || g.Suit != tt.suit || g.Turn != "b" || g.Drawn != nil || g.Declaring {
+				t.Errorf("a holds %v with %q called and %s to play, want %v with %q called and b to play", hand, g.Suit, g.Turn, want, tt.suit)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+)
+
+type CrazyEightsOptions struct {
+	// The score that wins the game, going to whoever wins a hand that takes them past it
+	TargetScore int `json:"target_score"`
+	// Queens skip the next player
+	Skip bool `json:"skip"`
+	// Aces reverse the direction of play
+	Reverse bool `json:"reverse"`
+	// Twos make the next player draw two cards and miss their turn
+	DrawTwo bool `json:"draw_two"`
+}
+
+func (o CrazyEightsOptions) Validate() error {
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	return nil
+}
+
+// SkipCard is the value that skips the next player, or empty if nothing does
+func (o CrazyEightsOptions) SkipCard() CardValue {
+	if o.Skip {
+		return Queen
+	}
+	return ""
+}
+
+func (o CrazyEightsOptions) ReverseCard() CardValue {
+	if o.Reverse {
+		return Ace
+	}
+	return ""
+}
+
+func (o CrazyEightsOptions) DrawTwoCard() CardValue {
+	if o.DrawTwo {
+		return Two
+	}
+	return ""
+}
+
+var CrazyEightsType = &GameType{
+	Name: "crazyeights",
+	Title: "Crazy Eights",
+	MinPlayers: CrazyEightsMinPlayers,
+	MaxPlayers: CrazyEightsMaxPlayers,
+	Options: []Option{
+		{Key: "target_score", Label: "Target score", Type: NumberOption, Default: 100, Min: 1},
+		{Key: "skip", Label: "Queens skip the next player", Type: BoolOption, Default: false},
+		{Key: "reverse", Label: "Aces reverse the direction of play", Type: BoolOption, Default: false},
+		{Key: "draw_two", Label: "Twos make the next player draw two", Type: BoolOption, Default: false},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o CrazyEightsOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewCrazyEightsGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseCrazyEightsSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreCrazyEightsGame(s, deciders)
+	},
+	CPU: CrazyEightsCPU,
+	Validate: func(options Options) error {
+		var o CrazyEightsOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(CrazyEightsType)
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CrazyEightsSnapshot is everything needed to carry on a game of crazy eights
+type CrazyEightsSnapshot struct {
+	TableSnapshot
+	Options CrazyEightsOptions `json:"options"`
+	Stock Deck `json:"stock"`
+	DiscardPile Deck `json:"discardPile"`
+	Reshuffles int `json:"reshuffles"`
+	Turn string `json:"turn"`
+	Direction int `json:"direction"`
+	Suit Suit `json:"suit,omitempty"`
+	Drawn *Card `json:"drawn,omitempty"`
+	Declaring bool `json:"declaring"`
+	Passes int `json:"passes"`
+	Players map[string]CrazyEightsPlayerSnapshot `json:"players"`
+}
+
+type CrazyEightsPlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Score int `json:"score"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *CrazyEightsGame) Snapshot() *CrazyEightsSnapshot {
+	players := map[string]CrazyEightsPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = CrazyEightsPlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Score: p.Score,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	var drawn *Card
+	if g.Drawn != nil {
+		c := *g.Drawn
+		drawn = &c
+	}
+
+	return &CrazyEightsSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Stock: g.Stock.Copy(),
+		DiscardPile: g.DiscardPile.Copy(),
+		Reshuffles: g.Reshuffles,
+		Turn: g.Turn,
+		Direction: g.Direction,
+		Suit: g.Suit,
+		Drawn: drawn,
+		Declaring: g.Declaring,
+		Passes: g.Passes,
+		Players: players,
+	}
+}
+
+func (g *CrazyEightsGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreCrazyEightsGame sets up a game as it was in the snapshot, with the deciders taking
+// the seats with their names
+func RestoreCrazyEightsGame(s *CrazyEightsSnapshot, deciders []Decider) (*CrazyEightsGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewCrazyEightsGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Stock = s.Stock.Copy()
+	g.DiscardPile = s.DiscardPile.Copy()
+	g.Reshuffles = s.Reshuffles
+	g.Turn = s.Turn
+	g.Direction = s.Direction
+	g.Suit = s.Suit
+	if s.Drawn != nil {
+		c := *s.Drawn
+		g.Drawn = &c
+	}
+	g.Declaring = s.Declaring
+	g.Passes = s.Passes
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Score = ps.Score
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks play goes one way or the other round the table, that a turn being played has a seated player, a card turned up and any drawn card still in hand, and that a suit called on an eight is a real suit
+func (s *CrazyEightsSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(CrazyEightsMinPlayers, CrazyEightsMaxPlayers, CrazyEightsPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if s.Direction != 1 && s.Direction != -1 {
+		return fmt.Errorf("direction of %d is not 1 or -1", s.Direction)
+	}
+	if s.Phase == PlayingPhase {
+		if _, ok := s.Players[s.Turn]; !ok {
+			return fmt.Errorf("[%v] is not seated to play", s.Turn)
+		}
+		if len(s.DiscardPile) == 0 {
+			return fmt.Errorf("no card has been turned up to play on")
+		}
+		if s.Drawn != nil && s.Players[s.Turn].Hand.Index(*s.Drawn) == -1 {
+			return fmt.Errorf("[%v] does not hold the card they drew", s.Turn)
+		}
+	}
+	if s.Suit != "" && (Card{Suit: s.Suit}).SuitIndex() == -1 {
+		return fmt.Errorf("[%v] is not a suit", s.Suit)
+	}
+	return nil
+}
+
+func ParseCrazyEightsSnapshot(data []byte) (*CrazyEightsSnapshot, error) {
+	s := &CrazyEightsSnapshot{}
+	return s, parseSnapshot(data, s)
+}
@@ -0,0 +1,609 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	CribbageMinPlayers = 2
+	CribbageMaxPlayers = 3
+	// Every player keeps four cards, and the crib is made up to four too
+	CribbageHandSize = 4
+	// The count in pegging can not go over this
+	MaxPegCount = 31
+	HeelsPoints = 2
+	GoPoints = 1
+)
+
+const (
+	CribQuestion = Question("crib")
+	PegQuestion = Question("peg")
+)
+
+// A hand can be won at any point a player pegs out, even before it is played out
+var CribbagePhases = PhaseTransitions{
+	DealingPhase: {DiscardingPhase},
+	DiscardingPhase: {PlayingPhase, FinishedPhase},
+	PlayingPhase: {ScoringPhase, FinishedPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type CribbagePlayer struct {
+	*Seat
+	// Hand is kept whole for the show, with Played the cards pegged from it so far
+	Hand Deck
+	Played Deck
+	Score int
+	StartScore int
+}
+
+type CribbageGame struct {
+	Players map[string]*CribbagePlayer
+	Options CribbageOptions
+	Stock Deck
+	Crib Deck
+	Starter *Card
+	// The running count in pegging, the cards played towards it, the last player to play
+	// one, and whose turn it is
+	Count int
+	PegCards Deck
+	LastPegger string
+	Turn string
+	// Hands are shown from the show until the next deal
+	Shown bool
+	Table
+}
+
+type CribbagePlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	// Hands are only shown once they have been counted
+	Hand Deck `json:"hand,omitempty"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type CribbageGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]CribbagePlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Starter *Card `json:"starter,omitempty"`
+	Count int `json:"count"`
+	PegCards Deck `json:"pegCards"`
+	Crib Deck `json:"crib,omitempty"`
+	TargetScore int `json:"targetScore"`
+	Hand Deck `json:"hand"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewCribbageGame(deciders []Decider, seed int64, options CribbageOptions) *CribbageGame {
+	g := &CribbageGame{
+		Players: map[string]*CribbagePlayer{},
+		Options: options,
+	}
+	g.Init(g, seed, CribbagePhases, DealingPhase, RandomDecision)
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &CribbagePlayer{Seat: s}
+	})
+	return g
+}
+
+func (g *CribbageGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]CribbagePlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		info := CribbagePlayerInfo{
+			NumCards: len(p.Left()),
+			Score: p.Score,
+			Lead: name == g.Turn,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+		if g.Shown {
+			info.Hand = p.Hand
+		}
+		playerInfo[name] = info
+	}
+
+	var crib Deck
+	if g.Shown {
+		crib = g.Crib
+	}
+
+	return &CribbageGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Starter: g.Starter,
+		Count: g.Count,
+		PegCards: g.PegCards,
+		Crib: crib,
+		TargetScore: g.Options.TargetScore,
+		Hand: g.Players[decider.GetName()].Left(),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (cg *CribbageGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range cg.PlayerOrder {
+		info := cg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v", name, info.Score)
+		if info.Dealer {
+			b.WriteString(", dealer")
+		}
+		if info.Hand != nil {
+			fmt.Fprintf(&b, ", %v", info.Hand)
+		}
+		b.WriteString(" ) ")
+	}
+	b.WriteString("\nStarter: ")
+	if cg.Starter != nil {
+		b.WriteString(cg.Starter.String())
+	}
+	if cg.Crib != nil {
+		fmt.Fprintf(&b, " Crib: %v", cg.Crib)
+	}
+	fmt.Fprintf(&b, "\nCount: %v %v\n", cg.Count, cg.PegCards)
+	fmt.Fprintf(&b, "Hand: %v", cg.Hand)
+	return b.String()
+}
+
+// Left returns the cards the player has not pegged yet
+func (p *CribbagePlayer) Left() Deck {
+	return p.Hand.Without(p.Played)
+}
+
+// CribCards is how many cards each player puts in the crib
+func (g *CribbageGame) CribCards() int {
+	if g.NumPlayers() == 2 {
+		return 2
+	}
+	return 1
+}
+
+// Pegs returns the cards the player can peg without going over 31
+func (g *CribbageGame) Pegs(name string) Deck {
+	pegs := Deck{}
+	for _, c := range g.Players[name].Left() {
+		if g.Count + c.PipValue() <= MaxPegCount {
+			pegs = append(pegs, c)
+		}
+	}
+	return pegs
+}
+
+func (g *CribbageGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case CribQuestion:
+		owner := "your"
+		if dealer := g.PlayerOrder[g.Dealer()]; dealer != name {
+			owner = dealer + "'s"
+		}
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: fmt.Sprintf("Put %d cards in %v crib", g.CribCards(), owner),
+			Hand: p.Hand.Copy(),
+			Count: g.CribCards(),
+			Allowed: p.Hand.Copy(),
+		}
+	case PegQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: fmt.Sprintf("Peg a card, the count is %d", g.Count),
+			Hand: p.Left(),
+			Count: 1,
+			Allowed: g.Pegs(name),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *CribbageGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case CribQuestion:
+		return PassSelection{Cards: r.Cards}
+	case PegQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	}
+	return nil
+}
+
+func (g *CribbageGame) GetPlayer(i int) *CribbagePlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// Dealer is the seat of the player dealing this hand, and owning the crib
+func (g *CribbageGame) Dealer() int {
+	return g.Round % g.NumPlayers()
+}
+
+// Winner returns the player who pegged out, or nil while nobody has
+func (g *CribbageGame) Winner() *CribbagePlayer {
+	for _, p := range g.Players {
+		if p.Score >= g.Options.TargetScore {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *CribbageGame) GameOver() bool {
+	return g.Cancelled || g.Winner() != nil
+}
+
+func (g *CribbageGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Score
+	}
+	return scores
+}
+
+func (g *CribbageGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+// PlayRound stops the hand as soon as someone pegs out
+func (g *CribbageGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	if g.Phase == DiscardingPhase {
+		if cancelled := g.DiscardToCrib(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == PlayingPhase {
+		if cancelled := g.Peg(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == ScoringPhase {
+		counts := g.Show()
+		scored := g.endRound()
+		for _, count := range counts {
+			g.emit(count)
+		}
+		g.emit(scored)
+		g.NotifyAll()
+	}
+	return false
+}
+
+// endRound records what everyone scored in the hand and moves on to the next deal, or
+// ends the game if someone has reached the target. Everything is settled before any of it
+// is announced, so a game saved on hearing about it carries on from the next hand.
+func (g *CribbageGame) endRound() RoundScored {
+	round := g.Round
+	roundPoints := map[string]int{}
+	for name, p := range g.Players {
+		roundPoints[name] = p.Score - p.StartScore
+	}
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Turn = ""
+	g.Round++
+	if g.Winner() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	return RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()}
+}
+
+// Deal gives each player six cards with two players, or five with three, with one more
+// going straight into the crib
+func (g *CribbageGame) Deal() {
+	g.Crib = Deck{}
+	g.Starter = nil
+	g.Count, g.PegCards, g.LastPegger = 0, Deck{}, ""
+	g.Shown = false
+	for _, p := range g.Players {
+		p.Hand, p.Played = Deck{}, Deck{}
+		p.StartScore = p.Score
+	}
+	g.Stock = NewDeck()
+	g.Stock.Shuffle(g.RoundRand())
+	for i := 0; i < (CribbageHandSize + g.CribCards()) * g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + 1 + i) % g.NumPlayers())
+		p.Hand = append(p.Hand, g.Stock.Deal())
+	}
+	for len(g.Crib) + g.CribCards() * g.NumPlayers() < CribbageHandSize {
+		g.Crib = append(g.Crib, g.Stock.Deal())
+	}
+	for _, p := range g.Players {
+		p.Hand.Sort()
+	}
+	g.setPhase(DiscardingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// DiscardToCrib has everyone put their cards in the crib at once, then cuts the starter.
+// A jack scores his heels for the dealer.
+func (g *CribbageGame) DiscardToCrib() bool {
+	results := make([]chan Deck, g.NumPlayers())
+	for i := range results {
+		results[i] = make(chan Deck, 1)
+		go func(i int) {
+			cards, cancelled := g.GetPlayer(i).ChooseCrib(g)
+			if cancelled {
+				cards = nil
+			}
+			results[i] <- cards
+		}(i)
+	}
+	discards := make([]Deck, g.NumPlayers())
+	cancelled := false
+	for i := range results {
+		discards[i] = <-results[i]
+		cancelled = cancelled || discards[i] == nil
+	}
+	if cancelled {
+		return true
+	}
+
+	for i, cards := range discards {
+		p := g.GetPlayer(i)
+		p.Hand = p.Hand.Without(cards)
+		g.Crib = append(g.Crib, cards...)
+	}
+	g.Crib.Sort()
+	starter := g.Stock.Deal()
+	g.Starter = &starter
+	dealer := g.GetPlayer(g.Dealer())
+	heels := starter.Value == Jack
+	if heels {
+		dealer.Score += HeelsPoints
+	}
+	cut := StarterCut{Round: g.Round, Card: starter, Heels: heels}
+	if g.Winner() != nil {
+		scored := g.endRound()
+		g.emit(cut)
+		g.emit(scored)
+		g.NotifyAll()
+		return false
+	}
+	g.Turn = g.PlayerOrder[(g.Dealer() + 1) % g.NumPlayers()]
+	g.setPhase(PlayingPhase)
+	g.emit(cut)
+	g.NotifyAll()
+	return false
+}
+
+// ChooseCrib asks which cards to put in the crib, but leaves them in the hand until
+// everyone has chosen
+func (p *CribbagePlayer) ChooseCrib(g *CribbageGame) (Deck, bool) {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, CribQuestion, g)
+		if cancelled {
+			return nil, true
+		}
+		indices, violation := ValidatePass(p.Hand, answer, g.CribCards())
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		cards := Deck{}
+		for _, i := range indices {
+			cards = append(cards, p.Hand[i])
+		}
+		return cards, false
+	}
+}
+
+// Peg plays out the pegging, picking up with whoever's turn it is
+func (g *CribbageGame) Peg() bool {
+	for g.Phase == PlayingPhase {
+		if cancelled := g.Players[g.Turn].PegCard(g); cancelled {
+			return true
+		}
+		g.NotifyAll()
+	}
+	return false
+}
+
+// PegCard has the player play a card towards the count and scores it, then passes the turn
+// on. Players who can not play say go without being asked.
+func (p *CribbagePlayer) PegCard(g *CribbageGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	name := p.GetName()
+	for {
+		answer, cancelled := g.Ask(p.Seat, PegQuestion, g)
+		if cancelled {
+			return true
+		}
+		left := p.Left()
+		index, violation := ValidateIndex(left, answer)
+		if violation == nil && g.Count + left[index].PipValue() > MaxPegCount {
+			violation = &RuleViolation{
+				Code: OverThirtyOneViolation,
+				Message: fmt.Sprintf("The count can not go over %d", MaxPegCount),
+				Card: &left[index],
+			}
+		}
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+
+		c := left[index]
+		p.Played = append(p.Played, c)
+		g.PegCards = append(g.PegCards, c)
+		g.Count += c.PipValue()
+		g.LastPegger = name
+		points := PeggingScore(g.PegCards)
+		p.Score += points
+		pegged := CardPegged{Round: g.Round, Player: name, Card: c, Count: g.Count, Points: points}
+		var gone *PeggedGo
+		if g.Winner() == nil {
+			gone = g.nextPegger()
+		}
+		var scored *RoundScored
+		if g.Winner() != nil {
+			s := g.endRound()
+			scored = &s
+		}
+		g.emit(pegged)
+		if gone != nil {
+			g.emit(*gone)
+		}
+		if scored != nil {
+			g.emit(*scored)
+		}
+		return false
+	}
+}
+
+// nextPegger passes the turn to the next player who can play. When nobody can, the last
+// player to peg scores one for the go, unless they made 31, and the count starts again
+// with the player after them. Once every card is played the hand moves on to the show.
+func (g *CribbageGame) nextPegger() *PeggedGo {
+	var gone *PeggedGo
+	from := g.GetOrder(g.Turn)
+	if g.Count == MaxPegCount || !g.canPeg() {
+		if g.Count != MaxPegCount {
+			g.Players[g.LastPegger].Score += GoPoints
+			gone = &PeggedGo{Round: g.Round, Player: g.LastPegger, Count: g.Count, Points: GoPoints}
+		}
+		g.Count, g.PegCards = 0, Deck{}
+		from = g.GetOrder(g.LastPegger)
+	}
+
+	for i := 1; i <= g.NumPlayers() && g.Winner() == nil; i++ {
+		next := g.GetPlayer((from + i) % g.NumPlayers())
+		if len(g.Pegs(next.GetName())) > 0 {
+			g.Turn = next.GetName()
+			return gone
+		}
+	}
+	if g.Winner() == nil {
+		g.setPhase(ScoringPhase)
+	}
+	return gone
+}
+
+// canPeg is true while anyone can play without going over 31
+func (g *CribbageGame) canPeg() bool {
+	for name := range g.Players {
+		if len(g.Pegs(name)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Show counts every hand with the starter, from the left of the dealer round to the
+// dealer, and then the dealer's crib, stopping as soon as someone reaches the target. It
+// returns the counts made, to be announced once the hand is settled.
+func (g *CribbageGame) Show() []HandCounted {
+	counts := []HandCounted{}
+	g.Shown = true
+	for i := 1; i <= g.NumPlayers() + 1 && g.Winner() == nil; i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		hand, crib := p.Hand, i > g.NumPlayers()
+		if crib {
+			hand = g.Crib
+		}
+		count := CountHand(hand, *g.Starter, crib)
+		p.Score += count.Total()
+		counts = append(counts, HandCounted{
+			Round: g.Round,
+			Player: p.GetName(),
+			Hand: hand.Copy(),
+			Starter: *g.Starter,
+			Crib: crib,
+			Count: count,
+			Points: count.Total(),
+		})
+	}
+	return counts
+}
+
+// CribbageCPU keeps the four cards that score best on average over every starter, and
+// pegs whatever scores most, steering clear of leaving a count of five or twenty-one
+func CribbageCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	cg, ok := g.(*CribbageGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	p := cg.Players[d.GetName()]
+
+	switch q {
+	case CribQuestion:
+		return PassSelection{Cards: bestCribDiscard(p.Hand, cg.CribCards())}
+	case PegQuestion:
+		left := p.Left()
+		best, bestValue := -1, 0
+		for i, c := range left {
+			if cg.Count + c.PipValue() > MaxPegCount {
+				continue
+			}
+			value := PeggingScore(append(cg.PegCards.Copy(), c)) * 10 + c.PipValue()
+			if count := cg.Count + c.PipValue(); count == 5 || count == 21 {
+				value -= 10
+			}
+			if best == -1 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		return CardPlay{best}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// bestCribDiscard returns the indices of the cards to put in the crib to keep the hand
+// with the best total over every starter that could be cut
+func bestCribDiscard(hand Deck, count int) []int {
+	var best []int
+	bestTotal := -1
+	starters := NewDeck().Without(hand)
+	var pick func(from int, chosen []int)
+	pick = func(from int, chosen []int) {
+		if len(chosen) == count {
+			discard := Deck{}
+			for _, i := range chosen {
+				discard = append(discard, hand[i])
+			}
+			kept := hand.Without(discard)
+			total := 0
+			for _, starter := range starters {
+				total += CountHand(kept, starter, false).Total()
+			}
+			if total > bestTotal {
+				best, bestTotal = append([]int{}, chosen...), total
+			}
+			return
+		}
+		for i := from; i < len(hand); i++ {
+			pick(i + 1, append(chosen, i))
+		}
+	}
+	pick(0, []int{})
+	return best
+}
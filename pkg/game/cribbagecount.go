@@ -0,0 +1,115 @@
+package game
+
+// HandCount breaks down what a cribbage hand scores with the starter
+type HandCount struct {
+	Fifteens int `json:"fifteens"`
+	Pairs int `json:"pairs"`
+	Runs int `json:"runs"`
+	Flush int `json:"flush"`
+	Nobs int `json:"nobs"`
+}
+
+func (c HandCount) Total() int {
+	return c.Fifteens + c.Pairs + c.Runs + c.Flush + c.Nobs
+}
+
+// CountHand scores a hand with the starter: two for each combination of cards adding up
+// to fifteen and for each pair, one for each card in each run, a flush and one for nobs,
+// the jack of the starter's suit. A flush in the hand counts four, and five with the
+// starter, but a crib only counts a flush of all five.
+func CountHand(hand Deck, starter Card, crib bool) HandCount {
+	cards := append(hand.Copy(), starter)
+	count := HandCount{}
+
+	for subset := 1; subset < 1 << uint(len(cards)); subset++ {
+		sum := 0
+		for i, c := range cards {
+			if subset & (1 << uint(i)) != 0 {
+				sum += c.PipValue()
+			}
+		}
+		if sum == 15 {
+			count.Fifteens += 2
+		}
+	}
+
+	ranks := make([]int, len(CardValues))
+	for _, c := range cards {
+		ranks[c.AceLowIndex()]++
+	}
+	for _, n := range ranks {
+		count.Pairs += n * (n - 1)
+	}
+
+	// Every way of making the longest run counts, each card of the same rank making
+	// another way
+	for low := 0; low < len(ranks); {
+		high, ways := low, 1
+		for high < len(ranks) && ranks[high] > 0 {
+			ways *= ranks[high]
+			high++
+		}
+		if high - low >= 3 {
+			count.Runs += (high - low) * ways
+		}
+		low = high + 1
+	}
+
+	flush := len(hand) > 0
+	for _, c := range hand {
+		flush = flush && c.Suit == hand[0].Suit
+	}
+	switch {
+	case flush && starter.Suit == hand[0].Suit:
+		count.Flush = len(cards)
+	case flush && !crib:
+		count.Flush = len(hand)
+	}
+
+	for _, c := range hand {
+		if c.Value == Jack && c.Suit == starter.Suit {
+			count.Nobs = 1
+		}
+	}
+	return count
+}
+
+// PeggingScore is what the last card played scores in pegging, given every card played
+// since the count was last reset: two for making fifteen or thirty-one, two, six or twelve
+// for making a pair, three or four of a kind with the cards before it, and one for each
+// card in a run it finishes, in any order.
+func PeggingScore(played Deck) int {
+	if len(played) == 0 {
+		return 0
+	}
+	score := 0
+	sum := 0
+	for _, c := range played {
+		sum += c.PipValue()
+	}
+	if sum == 15 || sum == 31 {
+		score += 2
+	}
+
+	last := played[len(played) - 1]
+	same := 1
+	for i := len(played) - 2; i >= 0 && played[i].Value == last.Value; i-- {
+		same++
+	}
+	score += same * (same - 1)
+
+	// The longest run made by the last few cards
+	for n := len(played); n >= 3; n-- {
+		seen := map[int]bool{}
+		low, high := last.AceLowIndex(), last.AceLowIndex()
+		for _, c := range played[len(played) - n:] {
+			seen[c.AceLowIndex()] = true
+			low, high = min(low, c.AceLowIndex()), max(high, c.AceLowIndex())
+		}
+		if len(seen) == n && high - low == n - 1 {
+			score += n
+			break
+		}
+	}
+	return score
+}
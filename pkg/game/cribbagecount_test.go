@@ -0,0 +1,117 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestCountHand(t *testing.T) {
+	tests := []struct {
+		name string
+		hand string
+		starter string
+		crib bool
+		want HandCount
+	}{
+		{
+			name: "twenty nine",
+			hand: "5C 5D 5H JS",
+			starter: "5S",
+			want: HandCount{Fifteens: 16, Pairs: 12, Nobs: 1},
+		},
+		{
+			name: "double double run",
+			hand: "7C 7D 8H 8S",
+			starter: "9C",
+			want: HandCount{Fifteens: 8, Pairs: 4, Runs: 12},
+		},
+		{
+			name: "four card flush in the hand",
+			hand: "2H 4H 6H 8H",
+			starter: "KS",
+			want: HandCount{Flush: 4},
+		},
+		{
+			name: "five card flush in the hand",
+			hand: "2H 4H 6H 8H",
+			starter: "KH",
+			want: HandCount{Flush: 5},
+		},
+		{
+			name: "four card flush in the crib",
+			hand: "2H 4H 6H 8H",
+			starter: "KS",
+			crib: true,
+			want: HandCount{},
+		},
+		{
+			name: "five card flush in the crib",
+			hand: "2H 4H 6H 8H",
+			starter: "KH",
+			crib: true,
+			want: HandCount{Flush: 5},
+		},
+		{
+			name: "nobs",
+			hand: "JD 2C 4S 9H",
+			starter: "KD",
+			want: HandCount{Fifteens: 2, Nobs: 1},
+		},
+		{
+			name: "jack of the wrong suit",
+			hand: "JC 2C 4S 9H",
+			starter: "KD",
+			want: HandCount{Fifteens: 2},
+		},
+		{
+			name: "jack as the starter is not nobs",
+			hand: "2C 4S 9H KD",
+			starter: "JD",
+			want: HandCount{Fifteens: 2},
+		},
+		{
+			name: "nineteen",
+			hand: "2C 4S 10H KD",
+			starter: "QH",
+			want: HandCount{},
+		},
+	}
+	for _, test := range tests {
+		starter := cards(test.starter)[0]
+		if got := CountHand(cards(test.hand), starter, test.crib); got != test.want {
+			t.Errorf("%v: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPeggingScore(t *testing.T) {
+	tests := []struct {
+		name string
+		played string
+		want int
+	}{
+		{"nothing", "KS 2D", 0},
+		{"fifteen", "10S 5D", 2},
+		{"pair", "9S 9D", 2},
+		{"pair royal", "9S 9D 9C", 6},
+		{"double pair royal", "4S 4D 4C 4H", 12},
+		{"run in order", "3S 4D 5C", 3},
+		{"run out of order", "5S 3D 4C", 3},
+		{"longer run out of order", "6S 3D 5C 4H", 4},
+		{"run made longer out of order", "2S 4D 3C 6H 5S", 5},
+		{"run after a pair", "KS 3S 3D 4C 5H", 3},
+		{"run after a repeated card", "KS 3S 4D 3C 5H", 3},
+		{"no run through a repeated card", "KS 4S 3D 4C 6H", 0},
+		{"pair broken by a card between", "7S 8D 7C", 0},
+		{"pair royal broken by a card between", "7S 7D 8C 7H", 0},
+		{"fifteen and a pair", "5S 5D 5C", 6 + 2},
+		{"thirty one", "10S 10D 4C 7H", 2},
+		{"thirty one and a pair", "KS 9D 6C 6H", 2 + 2},
+		{"thirty one and a pair royal", "10S 7D 7C 7H", 6 + 2},
+		{"thirty one and a run", "10S 9D 3C 4H 5S", 3 + 2},
+	}
+	for _, test := range tests {
+		if got := PeggingScore(cards(test.played)); got != test.want {
+			t.Errorf("%v: %v scores %d, want %d", test.name, test.played, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"fmt"
+)
+
+type CribbageOptions struct {
+	// The score a player has to peg out at, 121 for the full board or 61 for a short game
+	TargetScore int `json:"target_score"`
+}
+
+func (o CribbageOptions) Validate() error {
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	return nil
+}
+
+var CribbageType = &GameType{
+	Name: "cribbage",
+	Title: "Cribbage",
+	MinPlayers: CribbageMinPlayers,
+	MaxPlayers: CribbageMaxPlayers,
+	Options: []Option{
+		{Key: "target_score", Label: "Target score", Type: NumberOption, Default: 121, Min: 1},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o CribbageOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewCribbageGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseCribbageSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreCribbageGame(s, deciders)
+	},
+	CPU: CribbageCPU,
+	Validate: func(options Options) error {
+		var o CribbageOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(CribbageType)
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CribbageSnapshot is everything needed to carry on a game of cribbage
+type CribbageSnapshot struct {
+	TableSnapshot
+	Options CribbageOptions `json:"options"`
+	Stock Deck `json:"stock"`
+	Crib Deck `json:"crib"`
+	Starter *Card `json:"starter,omitempty"`
+	Count int `json:"count"`
+	PegCards Deck `json:"pegCards"`
+	LastPegger string `json:"lastPegger,omitempty"`
+	Turn string `json:"turn"`
+	Shown bool `json:"shown"`
+	Players map[string]CribbagePlayerSnapshot `json:"players"`
+}
+
+type CribbagePlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Played Deck `json:"played"`
+	Score int `json:"score"`
+	StartScore int `json:"startScore"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *CribbageGame) Snapshot() *CribbageSnapshot {
+	players := map[string]CribbagePlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = CribbagePlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Played: p.Played.Copy(),
+			Score: p.Score,
+			StartScore: p.StartScore,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	var starter *Card
+	if g.Starter != nil {
+		c := *g.Starter
+		starter = &c
+	}
+
+	return &CribbageSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Stock: g.Stock.Copy(),
+		Crib: g.Crib.Copy(),
+		Starter: starter,
+		Count: g.Count,
+		PegCards: g.PegCards.Copy(),
+		LastPegger: g.LastPegger,
+		Turn: g.Turn,
+		Shown: g.Shown,
+		Players: players,
+	}
+}
+
+func (g *CribbageGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreCribbageGame sets up a game as it was in the snapshot, with the deciders taking
+// the seats with their names
+func RestoreCribbageGame(s *CribbageSnapshot, deciders []Decider) (*CribbageGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewCribbageGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Stock = s.Stock.Copy()
+	g.Crib = s.Crib.Copy()
+	if s.Starter != nil {
+		c := *s.Starter
+		g.Starter = &c
+	}
+	g.Count = s.Count
+	g.PegCards = s.PegCards.Copy()
+	g.LastPegger = s.LastPegger
+	g.Turn = s.Turn
+	g.Shown = s.Shown
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Played = ps.Played.Copy()
+		p.Score = ps.Score
+		p.StartScore = ps.StartScore
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks there are cards to cut the starter from while discarding, that pegging has a seated player and a count no higher than 31, and that a starter has been cut once pegging starts
+func (s *CribbageSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(CribbageMinPlayers, CribbageMaxPlayers, CribbagePhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	switch s.Phase {
+	case DiscardingPhase:
+		if len(s.Stock) == 0 {
+			return fmt.Errorf("no cards left to cut the starter from")
+		}
+	case PlayingPhase:
+		if _, ok := s.Players[s.Turn]; !ok {
+			return fmt.Errorf("[%v] is not seated to peg", s.Turn)
+		}
+		if s.Count < 0 || s.Count > MaxPegCount {
+			return fmt.Errorf("count of %d is out of range", s.Count)
+		}
+		fallthrough
+	case ScoringPhase:
+		if s.Starter == nil {
+			return fmt.Errorf("no starter has been cut")
+		}
+	}
+	if s.Phase == PlayingPhase && s.LastPegger != "" {
+		if _, ok := s.Players[s.LastPegger]; !ok {
+			return fmt.Errorf("[%v] is not seated", s.LastPegger)
+		}
+	}
+	return nil
+}
+
+func ParseCribbageSnapshot(data []byte) (*CribbageSnapshot, error) {
+	s := &CribbageSnapshot{}
+	return s, parseSnapshot(data, s)
+}
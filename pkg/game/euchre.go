@@ -0,0 +1,777 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	EuchrePlayers = 4
+	EuchreHandSize = 5
+	// Points for the makers taking three or four tricks, all five, and for the defenders
+	// when the makers are euchred
+	MakersPoints = 1
+	MarchPoints = 2
+	EuchredPoints = 2
+)
+
+const (
+	OrderUpQuestion = Question("order_up")
+	CallTrumpQuestion = Question("call_trump")
+	DiscardQuestion = Question("discard")
+)
+
+// Options for passing when asked to call trump, and going alone after a call
+const (
+	PassOption = "pass"
+	AloneOption = " alone"
+)
+
+var EuchreValues = []CardValue{Nine, Ten, Jack, Queen, King, Ace}
+
+// The dealer picks up the turned up card and discards if it is ordered up, and the hand is
+// thrown in and dealt again if everyone passes twice
+var EuchrePhases = PhaseTransitions{
+	DealingPhase: {BiddingPhase},
+	BiddingPhase: {DiscardingPhase, PlayingPhase, DealingPhase},
+	DiscardingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type EuchrePlayer struct {
+	*Seat
+	Hand Deck
+	TricksWon int
+	// The partner of a player going alone sits the hand out
+	SittingOut bool
+}
+
+type EuchreTeam struct {
+	Name string
+	Players []string
+	Score int
+}
+
+type EuchreGame struct {
+	Players map[string]*EuchrePlayer
+	Teams []*EuchreTeam
+	Options EuchreOptions
+	// Dealt counts every hand dealt, including the ones thrown in when nobody called trump,
+	// where Round only counts the hands that were played out
+	Dealt int
+	TurnedUp Card
+	// Passes counts the players who passed on calling trump, the second time round the
+	// table starting after all of them have passed once
+	Passes int
+	Trump Suit
+	Maker string
+	Alone bool
+	Table
+	TrickTaking
+}
+
+type EuchrePlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Team string `json:"team"`
+	Bid string `json:"bid"`
+	TricksWon int `json:"tricksWon"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	SittingOut bool `json:"sittingOut"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type EuchreTeamInfo struct {
+	Name string `json:"name"`
+	Players []string `json:"players"`
+	Score int `json:"score"`
+}
+
+type EuchreGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]EuchrePlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Teams []EuchreTeamInfo `json:"teams"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Trick int `json:"trick"`
+	TargetScore int `json:"targetScore"`
+	TurnedUp *Card `json:"turnedUp,omitempty"`
+	Trump Suit `json:"trump,omitempty"`
+	Maker string `json:"maker,omitempty"`
+	Alone bool `json:"alone,omitempty"`
+	Hand Deck `json:"hand"`
+	LegalPlays Deck `json:"legalPlays"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+// NewEuchreDeck returns the 24 cards euchre is played with, nine up to ace in each suit
+func NewEuchreDeck() Deck {
+	d := Deck{}
+	for _, c := range NewDeck() {
+		for _, v := range EuchreValues {
+			if c.Value == v {
+				d = append(d, c)
+			}
+		}
+	}
+	return d
+}
+
+// SameColour returns the other suit of the same colour
+func SameColour(s Suit) Suit {
+	switch s {
+	case Hearts:
+		return Diamonds
+	case Diamonds:
+		return Hearts
+	case Clubs:
+		return Spades
+	}
+	return Clubs
+}
+
+// EuchreSuit is the suit a card counts as, the left bower, the jack of the same colour as
+// trump, being a trump
+func EuchreSuit(c Card, trump Suit) Suit {
+	if c.Value == Jack && c.Suit == SameColour(trump) {
+		return trump
+	}
+	return c.Suit
+}
+
+// euchreRank orders cards of the same suit, with the right bower, the jack of trumps, and
+// then the left bower above the ace
+func euchreRank(c Card, trump Suit) int {
+	switch {
+	case c.Value == Jack && c.Suit == trump:
+		return len(CardValues) + 1
+	case c.Value == Jack && c.Suit == SameColour(trump):
+		return len(CardValues)
+	}
+	return c.ValueIndex()
+}
+
+// euchreTricks are the rules of a euchre trick: the called suit is trumps, with the left
+// bower following and winning as one of them and both bowers above the ace
+type euchreTricks struct {
+	PlainTricks
+	g *EuchreGame
+}
+
+func (r euchreTricks) TrumpSuit() Suit {
+	return r.g.Trump
+}
+
+func (r euchreTricks) EffectiveSuit(c Card) Suit {
+	return EuchreSuit(c, r.g.Trump)
+}
+
+func (r euchreTricks) CardRank(c Card) int {
+	return euchreRank(c, r.g.Trump)
+}
+
+func NewEuchreGame(deciders []Decider, seed int64, options EuchreOptions) *EuchreGame {
+	g := &EuchreGame{
+		Players: map[string]*EuchrePlayer{},
+		Teams: []*EuchreTeam{},
+		Options: options,
+	}
+	g.Init(g, seed, EuchrePhases, DealingPhase, EuchreTimeout)
+	g.InitTricks(euchreTricks{g: g})
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &EuchrePlayer{Seat: s}
+	})
+	for i := 0; i < len(g.PlayerOrder) / 2; i++ {
+		partners := []string{g.PlayerOrder[i], g.PlayerOrder[i + len(g.PlayerOrder) / 2]}
+		g.Teams = append(g.Teams, &EuchreTeam{Name: strings.Join(partners, " & "), Players: partners})
+	}
+	return g
+}
+
+func (g *EuchreGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]EuchrePlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		playerInfo[name] = EuchrePlayerInfo{
+			NumCards: len(p.Hand),
+			Score: g.Team(name).Score,
+			Team: g.Team(name).Name,
+			Bid: g.BidString(name),
+			TricksWon: p.TricksWon,
+			Lead: name == g.Leader,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			SittingOut: p.SittingOut,
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+	}
+	teams := []EuchreTeamInfo{}
+	for _, t := range g.Teams {
+		teams = append(teams, EuchreTeamInfo{Name: t.Name, Players: t.Players, Score: t.Score})
+	}
+
+	// The turned up card is only on show until everyone has passed on it once
+	var turnedUp *Card
+	if g.Phase == BiddingPhase && g.Passes < g.NumPlayers() {
+		card := g.TurnedUp
+		turnedUp = &card
+	}
+
+	return &EuchreGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Teams: teams,
+		CurrentTrick: g.CurrentTrick,
+		Tricks: g.Tricks,
+		Trick: g.Trick,
+		TargetScore: g.Options.TargetScore,
+		TurnedUp: turnedUp,
+		Trump: g.Trump,
+		Maker: g.Maker,
+		Alone: g.Alone,
+		Hand: g.Players[decider.GetName()].Hand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (eg *EuchreGameInfo) String() string {
+	var b strings.Builder
+	for _, t := range eg.Teams {
+		fmt.Fprintf(&b, "( %v: %v ) ", t.Name, t.Score)
+	}
+	if eg.TurnedUp != nil {
+		fmt.Fprintf(&b, "\nTurned up: %v", eg.TurnedUp)
+	}
+	if eg.Trump != "" {
+		fmt.Fprintf(&b, "\nTrump: %v, called by %v", eg.Trump, eg.Maker)
+		if eg.Alone {
+			b.WriteString(" alone")
+		}
+	}
+	b.WriteString("\nCalls: ")
+	for _, name := range eg.PlayerOrder {
+		info := eg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v, took %v ) ", name, info.Bid, info.TricksWon)
+	}
+	fmt.Fprintf(&b, "\nCurrent Trick: %v\n", eg.CurrentTrick)
+	fmt.Fprintf(&b, "Hand: %v", eg.Hand.NumberedString())
+	return b.String()
+}
+
+// BidString is what the player said when asked to call trump, empty if they have not been
+// asked yet
+func (g *EuchreGame) BidString(name string) string {
+	if g.Maker == name {
+		if g.Alone {
+			return string(g.Trump) + AloneOption
+		}
+		return string(g.Trump)
+	}
+	// Calls go round from the left of the dealer, so everyone that far round has passed
+	offset := (g.GetOrder(name) - g.Dealer() - 1 + g.NumPlayers()) % g.NumPlayers()
+	if offset < g.Passes {
+		return PassOption
+	}
+	return ""
+}
+
+// callOptions lists what the player can say when asked to call trump
+func (g *EuchreGame) callOptions(name string) []string {
+	dealer := name == g.PlayerOrder[g.Dealer()]
+	if g.Passes < g.NumPlayers() {
+		order := "order up"
+		if dealer {
+			order = "pick up"
+		}
+		return []string{PassOption, order, order + AloneOption}
+	}
+
+	options := []string{}
+	if !dealer || !g.Options.StickTheDealer {
+		options = append(options, PassOption)
+	}
+	for _, s := range Suits {
+		if s != g.TurnedUp.Suit {
+			options = append(options, string(s), string(s) + AloneOption)
+		}
+	}
+	return options
+}
+
+func (g *EuchreGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case OrderUpQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Make %v trump by ordering up the %v?", g.TurnedUp.Suit, g.TurnedUp),
+			Hand: p.Hand.Copy(),
+			Options: g.callOptions(name),
+		}
+	case CallTrumpQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Call a trump suit other than %v?", g.TurnedUp.Suit),
+			Hand: p.Hand.Copy(),
+			Options: g.callOptions(name),
+		}
+	case DiscardQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Discard a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: p.Hand.Copy(),
+		}
+	case PlayOnTrickQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *EuchreGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case OrderUpQuestion, CallTrumpQuestion:
+		option := strings.TrimSuffix(r.Option, AloneOption)
+		alone := option != r.Option
+		switch option {
+		case PassOption:
+			return TrumpCall{}
+		case "order up", "pick up":
+			return TrumpCall{Suit: g.TurnedUp.Suit, Alone: alone}
+		}
+		return TrumpCall{Suit: Suit(option), Alone: alone}
+	case DiscardQuestion, PlayOnTrickQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	}
+	return nil
+}
+
+func (g *EuchreGame) GetPlayer(i int) *EuchrePlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+func (g *EuchreGame) Team(name string) *EuchreTeam {
+	return g.Teams[g.GetOrder(name) % len(g.Teams)]
+}
+
+// Dealer is the seat of the player dealing this hand, which moves to the left every deal
+func (g *EuchreGame) Dealer() int {
+	return g.Dealt % g.NumPlayers()
+}
+
+// Winner returns the first team to reach the target score, or nil while the game goes on.
+// Only one team scores each hand, so there are no ties.
+func (g *EuchreGame) Winner() *EuchreTeam {
+	for _, t := range g.Teams {
+		if t.Score >= g.Options.TargetScore {
+			return t
+		}
+	}
+	return nil
+}
+
+func (g *EuchreGame) GameOver() bool {
+	return g.Cancelled || g.Winner() != nil
+}
+
+func (g *EuchreGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name := range g.Players {
+		scores[name] = g.Team(name).Score
+	}
+	return scores
+}
+
+func (g *EuchreGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+// DealRand returns the random source for the current deal, so any deal can be reproduced
+// on its own
+func (g *EuchreGame) DealRand() *rand.Rand {
+	return g.SeededRand(int64(g.Dealt))
+}
+
+// PlayRound throws in a hand nobody calls trump on without a score
+func (g *EuchreGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	if g.Phase == BiddingPhase {
+		if cancelled := g.CallTrump(); cancelled {
+			return true
+		}
+		if g.Phase == DealingPhase {
+			return false
+		}
+	}
+
+	if g.Phase == DiscardingPhase {
+		if cancelled := g.Discard(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == PlayingPhase {
+		for g.Trick < EuchreHandSize {
+			if cancelled := g.PlayTrick(); cancelled {
+				return true
+			}
+		}
+		g.setPhase(ScoringPhase)
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Leader = ""
+	g.Round++
+	g.Dealt++
+	if g.Winner() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal hands out five cards each from the left of the dealer, and turns up the top card of
+// the rest
+func (g *EuchreGame) Deal() {
+	g.StartTricks()
+	g.Passes = 0
+	g.Trump, g.Maker, g.Alone = "", "", false
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.TricksWon = 0
+		p.SittingOut = false
+	}
+	d := NewEuchreDeck()
+	d.Shuffle(g.DealRand())
+	pi := (g.Dealer() + 1) % g.NumPlayers()
+	for i := 0; i < EuchreHandSize * g.NumPlayers(); i++ {
+		g.GetPlayer(pi).Hand = append(g.GetPlayer(pi).Hand, d.Deal())
+		pi = (pi + 1) % g.NumPlayers()
+	}
+	g.TurnedUp = d.Deal()
+	for _, p := range g.Players {
+		p.Hand.Sort()
+	}
+	g.setPhase(BiddingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// CallTrump goes round the table from the left of the dealer, asking each player to order
+// up the turned up card, then round again asking for any other suit. It picks up after
+// any passes already made.
+func (g *EuchreGame) CallTrump() bool {
+	for g.Passes < 2 * g.NumPlayers() {
+		p := g.GetPlayer((g.Dealer() + 1 + g.Passes) % g.NumPlayers())
+		call, cancelled := p.CallTrump(g)
+		if cancelled {
+			return true
+		}
+		g.emit(TrumpCalled{Round: g.Round, Player: p.GetName(), Suit: call.Suit, Alone: call.Alone})
+		if call.Suit == "" {
+			g.Passes++
+			g.NotifyAll()
+			continue
+		}
+
+		g.Trump, g.Maker, g.Alone = call.Suit, p.GetName(), call.Alone
+		if g.Alone {
+			g.GetPlayer((g.GetOrder(g.Maker) + g.NumPlayers() / 2) % g.NumPlayers()).SittingOut = true
+		}
+		// The dealer picks up the card that was ordered up, unless they are sitting out
+		if g.Passes < g.NumPlayers() && !g.GetPlayer(g.Dealer()).SittingOut {
+			dealer := g.GetPlayer(g.Dealer())
+			dealer.Hand = append(dealer.Hand, g.TurnedUp)
+			dealer.Hand.Sort()
+			g.setPhase(DiscardingPhase)
+		} else {
+			g.startPlaying()
+		}
+		g.NotifyAll()
+		return false
+	}
+
+	// Nobody wanted to call trump, so the deal moves on
+	g.Dealt++
+	g.setPhase(DealingPhase)
+	g.NotifyAll()
+	return false
+}
+
+func (p *EuchrePlayer) CallTrump(g *EuchreGame) (TrumpCall, bool) {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+
+	q := OrderUpQuestion
+	if g.Passes >= g.NumPlayers() {
+		q = CallTrumpQuestion
+	}
+	for {
+		answer, cancelled := g.Ask(p.Seat, q, g)
+		if cancelled {
+			return TrumpCall{}, true
+		}
+		call, violation := g.CheckCall(p.GetName(), answer)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		return call, false
+	}
+}
+
+// CheckCall returns the rule broken by the player's answer to being asked for trump. The
+// turned up suit can only be called the first time round, and any other suit the second.
+func (g *EuchreGame) CheckCall(name string, answer Answer) (TrumpCall, *RuleViolation) {
+	call, ok := answer.(TrumpCall)
+	if !ok {
+		return call, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	if call.Suit == "" {
+		if g.Passes == 2 * g.NumPlayers() - 1 && g.Options.StickTheDealer {
+			return call, &RuleViolation{Code: DealerMustCallViolation, Message: "The dealer must call trump"}
+		}
+		return call, nil
+	}
+
+	firstRound := g.Passes < g.NumPlayers()
+	valid := false
+	for _, s := range Suits {
+		valid = valid || s == call.Suit
+	}
+	if !valid || firstRound != (call.Suit == g.TurnedUp.Suit) {
+		message := "Only " + string(g.TurnedUp.Suit) + " can be ordered up"
+		if !firstRound {
+			message = string(g.TurnedUp.Suit) + " was turned down, call another suit"
+		}
+		return call, &RuleViolation{Code: InvalidTrumpViolation, Message: message, Suit: g.TurnedUp.Suit}
+	}
+	return call, nil
+}
+
+// Discard has the dealer put back a card after picking up the turned up one
+func (g *EuchreGame) Discard() bool {
+	p := g.GetPlayer(g.Dealer())
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, DiscardQuestion, g)
+		if cancelled {
+			return true
+		}
+		index, violation := ValidateIndex(p.Hand, answer)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
+		break
+	}
+	g.startPlaying()
+	g.NotifyAll()
+	return false
+}
+
+// startPlaying has the first player to the left of the dealer who is not sitting out lead
+func (g *EuchreGame) startPlaying() {
+	g.Leader = g.trickOrder(g.PlayerOrder[(g.Dealer() + 1) % g.NumPlayers()])[0]
+	g.setPhase(PlayingPhase)
+}
+
+// trickOrder lists the players in the hand, starting with the given one if they are in it
+// or else the next one to their left
+func (g *EuchreGame) trickOrder(from string) []string {
+	players := []string{}
+	start := g.GetOrder(from)
+	for i := 0; i < g.NumPlayers(); i++ {
+		p := g.GetPlayer((start + i) % g.NumPlayers())
+		if !p.SittingOut {
+			players = append(players, p.GetName())
+		}
+	}
+	return players
+}
+
+func (g *EuchreGame) PlayTrick() bool {
+	play := func(name string) (Card, bool) {
+		p := g.Players[name]
+		return g.PlayFromHand(&g.Table, p.Seat, &p.Hand, g)
+	}
+	played := func(name string, card Card) {
+		g.emit(CardPlayed{Round: g.Round, Trick: g.Trick, Player: name, Card: card})
+		g.NotifyAll()
+	}
+	trick := g.Trick
+	won, cancelled := g.PlayCards(g.trickOrder(g.Leader), play, played)
+	if cancelled {
+		return true
+	}
+
+	g.Players[won.Winner].TricksWon++
+	g.emit(TrickWon{Round: g.Round, Trick: trick, Leader: won.Leader, Winner: won.Winner, Cards: won.Cards})
+	g.NotifyAll()
+
+	return false
+}
+
+func (g *EuchreGame) LegalPlays(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok || p.Asking() != PlayOnTrickQuestion {
+		return Deck{}
+	}
+	return g.LegalCards(p.Hand)
+}
+
+// ScoreRound adds the hand's points to the team that won them, and returns how much each
+// player's team score changed. The makers score for taking three tricks or more, more for
+// a march of all five, and the defenders score if the makers are euchred.
+func (g *EuchreGame) ScoreRound() map[string]int {
+	makers := g.Team(g.Maker)
+	tricks := 0
+	for _, name := range makers.Players {
+		tricks += g.Players[name].TricksWon
+	}
+
+	scorers, points := makers, MakersPoints
+	switch {
+	case tricks == EuchreHandSize && g.Alone:
+		points = g.Options.AloneMarchPoints
+	case tricks == EuchreHandSize:
+		points = MarchPoints
+	case tricks < 3:
+		scorers, points = g.Teams[(g.GetOrder(g.Maker) + 1) % len(g.Teams)], EuchredPoints
+	}
+	scorers.Score += points
+
+	roundPoints := map[string]int{}
+	for _, name := range g.PlayerOrder {
+		if g.Team(name) == scorers {
+			roundPoints[name] = points
+		} else {
+			roundPoints[name] = 0
+		}
+	}
+	return roundPoints
+}
+
+// EuchreTimeout passes on trump unless the dealer is stuck with calling it, never goes
+// alone, and otherwise plays like EuchreCPU
+func EuchreTimeout(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	eg, ok := g.(*EuchreGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	switch q {
+	case OrderUpQuestion:
+		return TrumpCall{}
+	case CallTrumpQuestion:
+		if !eg.Options.StickTheDealer || d.GetName() != eg.PlayerOrder[eg.Dealer()] {
+			return TrumpCall{}
+		}
+		call, _ := EuchreCPU(r, d, q, g).(TrumpCall)
+		call.Alone = false
+		return call
+	}
+	return EuchreCPU(r, d, q, g)
+}
+
+// EuchreCPU calls trump when it holds enough trumps and aces, discards its lowest card
+// outside trumps, then plays at random
+func EuchreCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	eg, ok := g.(*EuchreGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	name := d.GetName()
+	hand := eg.Players[name].Hand
+	switch q {
+	case OrderUpQuestion:
+		trump := eg.TurnedUp.Suit
+		if name == eg.PlayerOrder[eg.Dealer()] {
+			hand = append(hand.Copy(), eg.TurnedUp)
+		}
+		strength := EuchreHandStrength(hand, trump)
+		if strength < 3 {
+			return TrumpCall{}
+		}
+		return TrumpCall{Suit: trump, Alone: strength >= 5}
+
+	case CallTrumpQuestion:
+		best, bestStrength := Suit(""), 0
+		for _, s := range Suits {
+			if strength := EuchreHandStrength(hand, s); s != eg.TurnedUp.Suit && strength > bestStrength {
+				best, bestStrength = s, strength
+			}
+		}
+		stuck := eg.Options.StickTheDealer && name == eg.PlayerOrder[eg.Dealer()]
+		if bestStrength < 3 && !stuck {
+			return TrumpCall{}
+		}
+		if best == "" {
+			best = SameColour(eg.TurnedUp.Suit)
+		}
+		return TrumpCall{Suit: best, Alone: bestStrength >= 5}
+
+	case DiscardQuestion:
+		discard := 0
+		for i, c := range hand {
+			low := hand[discard]
+			lowTrump, trump := EuchreSuit(low, eg.Trump) == eg.Trump, EuchreSuit(c, eg.Trump) == eg.Trump
+			if lowTrump && !trump || lowTrump == trump && euchreRank(c, eg.Trump) < euchreRank(low, eg.Trump) {
+				discard = i
+			}
+		}
+		return CardPlay{discard}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// EuchreHandStrength counts the trumps in the hand, counting both bowers, and the aces of
+// the other suits
+func EuchreHandStrength(hand Deck, trump Suit) int {
+	strength := 0
+	for _, c := range hand {
+		if EuchreSuit(c, trump) == trump || c.Value == Ace {
+			strength++
+		}
+	}
+	return strength
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newTestEuchreGame() *EuchreGame {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+	return NewEuchreGame(deciders, 1, EuchreOptions{TargetScore: 10})
+}
+
+func TestEuchreTimeout(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := newTestEuchreGame()
+	g.TurnedUp = cards("9H")[0]
+	for _, p := range g.Players {
+		p.Hand = cards("JH JD AH KH QH")
+	}
+	// Even with every trump, running out of time never orders up or calls
+	dealer := g.Players[g.PlayerOrder[g.Dealer()]].Decider
+	other := g.Players[g.PlayerOrder[(g.Dealer() + 1) % 4]].Decider
+	for _, d := range []Decider{dealer, other} {
+		for _, q := range []Question{OrderUpQuestion, CallTrumpQuestion} {
+			if call := g.Fallback(r, d, q, g); call != (TrumpCall{}) {
+				t.Errorf("%s timed out and answered %+v to %s", d.GetName(), call, q)
+			}
+		}
+	}
+
+	// A stuck dealer has to call, but does not go alone
+	g.Options.StickTheDealer = true
+	g.Players[dealer.GetName()].Hand = cards("JS JC AS KS QS")
+	call, ok := g.Fallback(r, dealer, CallTrumpQuestion, g).(TrumpCall)
+	if !ok || call.Suit != Spades || call.Alone {
+		t.Errorf("the stuck dealer timed out and called %+v, want spades not alone", call)
+	}
+	if call := g.Fallback(r, other, CallTrumpQuestion, g); call != (TrumpCall{}) {
+		t.Errorf("%s timed out and answered %+v, want a pass", other.GetName(), call)
+	}
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"fmt"
+)
+
+type EuchreOptions struct {
+	// The score a team has to reach to win
+	TargetScore int `json:"target_score"`
+	// Points for taking all five tricks alone
+	AloneMarchPoints int `json:"alone_march_points"`
+	// The dealer has to call trump if everyone else passes twice, instead of the hand being
+	// thrown in
+	StickTheDealer bool `json:"stick_the_dealer"`
+}
+
+func (o EuchreOptions) Validate() error {
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	if o.AloneMarchPoints < 0 {
+		return fmt.Errorf("Points for going alone can not be negative")
+	}
+	return nil
+}
+
+var EuchreType = &GameType{
+	Name: "euchre",
+	Title: "Euchre",
+	MinPlayers: EuchrePlayers,
+	MaxPlayers: EuchrePlayers,
+	Options: []Option{
+		{Key: "target_score", Label: "Target score", Type: NumberOption, Default: 10, Min: 1},
+		{Key: "alone_march_points", Label: "Points for a march alone", Type: NumberOption, Default: 4},
+		{Key: "stick_the_dealer", Label: "Stick the dealer", Type: BoolOption, Default: false},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o EuchreOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewEuchreGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseEuchreSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreEuchreGame(s, deciders)
+	},
+	CPU: EuchreCPU,
+	Validate: func(options Options) error {
+		var o EuchreOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(EuchreType)
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EuchreSnapshot is everything needed to carry on a game of euchre
+type EuchreSnapshot struct {
+	TableSnapshot
+	Options EuchreOptions `json:"options"`
+	Dealt int `json:"dealt"`
+	Trick int `json:"trick"`
+	Leader string `json:"leader"`
+	TurnedUp Card `json:"turnedUp"`
+	Passes int `json:"passes"`
+	Trump Suit `json:"trump"`
+	Maker string `json:"maker"`
+	Alone bool `json:"alone"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Scores []int `json:"scores"`
+	Players map[string]EuchrePlayerSnapshot `json:"players"`
+}
+
+type EuchrePlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	TricksWon int `json:"tricksWon"`
+	SittingOut bool `json:"sittingOut"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *EuchreGame) Snapshot() *EuchreSnapshot {
+	players := map[string]EuchrePlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = EuchrePlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			TricksWon: p.TricksWon,
+			SittingOut: p.SittingOut,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	scores := []int{}
+	for _, t := range g.Teams {
+		scores = append(scores, t.Score)
+	}
+
+	return &EuchreSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Dealt: g.Dealt,
+		Trick: g.Trick,
+		Leader: g.Leader,
+		TurnedUp: g.TurnedUp,
+		Passes: g.Passes,
+		Trump: g.Trump,
+		Maker: g.Maker,
+		Alone: g.Alone,
+		CurrentTrick: g.CurrentTrick.Copy(),
+		Tricks: append([]CompletedTrick{}, g.Tricks...),
+		Scores: scores,
+		Players: players,
+	}
+}
+
+func (g *EuchreGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreEuchreGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names
+func RestoreEuchreGame(s *EuchreSnapshot, deciders []Decider) (*EuchreGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewEuchreGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Dealt = s.Dealt
+	g.Trick = s.Trick
+	g.Leader = s.Leader
+	g.TurnedUp = s.TurnedUp
+	g.Passes = s.Passes
+	g.Trump = s.Trump
+	g.Maker = s.Maker
+	g.Alone = s.Alone
+	g.CurrentTrick = s.CurrentTrick.Copy()
+	g.Tricks = append([]CompletedTrick{}, s.Tricks...)
+	for i, score := range s.Scores {
+		g.Teams[i].Score = score
+	}
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.TricksWon = ps.TricksWon
+		p.SittingOut = ps.SittingOut
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks there are four players with scores for two teams, that bidding has not gone round more than twice, that somebody called trump once bidding is over, and that a trick being played has no more cards than players
+func (s *EuchreSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(EuchrePlayers, EuchrePlayers, EuchrePhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if len(s.Scores) != EuchrePlayers / 2 {
+		return fmt.Errorf("snapshot has scores for %d teams", len(s.Scores))
+	}
+	if s.Passes < 0 || s.Passes > 2 * EuchrePlayers {
+		return fmt.Errorf("snapshot has %d passes", s.Passes)
+	}
+	if _, ok := s.Players[s.Maker]; !ok && (s.Phase == DiscardingPhase || s.Phase == PlayingPhase || s.Phase == ScoringPhase) {
+		return fmt.Errorf("nobody called trump")
+	}
+	if s.Phase == PlayingPhase && len(s.CurrentTrick) > len(s.PlayerOrder) {
+		return fmt.Errorf("too many cards on the trick")
+	}
+	return nil
+}
+
+func ParseEuchreSnapshot(data []byte) (*EuchreSnapshot, error) {
+	s := &EuchreSnapshot{}
+	return s, parseSnapshot(data, s)
+}
@@ -0,0 +1,369 @@
+package game
+
+import (
+	"sync"
+)
+
+type EventType string
+const (
+	HandDealtEvent = EventType("hand_dealt")
+	CardsPassedEvent = EventType("cards_passed")
+	CardPlayedEvent = EventType("card_played")
+	HeartsBrokenEvent = EventType("hearts_broken")
+	TrickWonEvent = EventType("trick_won")
+	RoundScoredEvent = EventType("round_scored")
+	MoonShotEvent = EventType("moon_shot")
+	GameOverEvent = EventType("game_over")
+	TurnTimedOutEvent = EventType("turn_timed_out")
+	PhaseChangedEvent = EventType("phase_changed")
+	BidMadeEvent = EventType("bid_made")
+	TrumpCalledEvent = EventType("trump_called")
+	CallMadeEvent = EventType("call_made")
+	BetPlacedEvent = EventType("bet_placed")
+	BoardDealtEvent = EventType("board_dealt")
+	PotWonEvent = EventType("pot_won")
+	CardDrawnEvent = EventType("card_drawn")
+	CardDiscardedEvent = EventType("card_discarded")
+	KnockedEvent = EventType("knocked")
+	MeldsShownEvent = EventType("melds_shown")
+	StarterCutEvent = EventType("starter_cut")
+	CardPeggedEvent = EventType("card_pegged")
+	PeggedGoEvent = EventType("pegged_go")
+	HandCountedEvent = EventType("hand_counted")
+	SuitDeclaredEvent = EventType("suit_declared")
+	PileReshuffledEvent = EventType("pile_reshuffled")
+	UpcardDealtEvent = EventType("upcard_dealt")
+	HandActedEvent = EventType("hand_acted")
+	DealerPlayedEvent = EventType("dealer_played")
+	HandSettledEvent = EventType("hand_settled")
+	TrumpTurnedEvent = EventType("trump_turned")
+)
+
+// Event is something that happened in a game. Events are emitted one at a time in the
+// order they happen, and every listener sees an event before the game moves on, so
+// listeners should hand work off rather than block.
+type Event interface {
+	Type() EventType
+}
+
+type EventListener func(Event)
+
+type HandDealt struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Hand Deck `json:"hand"`
+}
+
+type CardsPassed struct {
+	Round int `json:"round"`
+	Direction PassDirection `json:"direction"`
+	From string `json:"from"`
+	To string `json:"to"`
+	Cards Deck `json:"cards"`
+}
+
+type CardPlayed struct {
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Player string `json:"player"`
+	Card Card `json:"card"`
+}
+
+type HeartsBroken struct {
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Player string `json:"player"`
+}
+
+type TrickWon struct {
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Leader string `json:"leader"`
+	Winner string `json:"winner"`
+	Cards Deck `json:"cards"`
+	Points int `json:"points"`
+}
+
+type RoundScored struct {
+	Round int `json:"round"`
+	RoundPoints map[string]int `json:"roundPoints"`
+	Scores map[string]int `json:"scores"`
+}
+
+type MoonShot struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Sun bool `json:"sun"`
+}
+
+type TurnTimedOut struct {
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Player string `json:"player"`
+	Question Question `json:"question"`
+}
+
+type PhaseChanged struct {
+	Round int `json:"round"`
+	From Phase `json:"from"`
+	To Phase `json:"to"`
+}
+
+// BidMade is a player's bid of how many tricks they will take. Blind bids were made
+// without looking at the hand.
+type BidMade struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Tricks int `json:"tricks"`
+	Blind bool `json:"blind"`
+}
+
+// TrumpCalled is a player's answer when asked to call trump, an empty suit being a pass
+type TrumpCalled struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Suit Suit `json:"suit,omitempty"`
+	Alone bool `json:"alone,omitempty"`
+}
+
+// CallMade is a call in a bridge auction
+type CallMade struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Call BridgeCall `json:"call"`
+}
+
+// BetPlaced is a player's action in a round of betting, with their bet on the street
+// afterwards
+type BetPlaced struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Action string `json:"action"`
+	Bet int `json:"bet"`
+	Pot int `json:"pot"`
+}
+
+// BoardDealt is the board once the cards for a street are dealt onto it
+type BoardDealt struct {
+	Round int `json:"round"`
+	Street Phase `json:"street"`
+	Board Deck `json:"board"`
+}
+
+// PotWon is a pot or side pot going to the players who split it. Hand is the hand it was
+// won with at showdown, and empty when everyone else folded.
+type PotWon struct {
+	Round int `json:"round"`
+	Players []string `json:"players"`
+	Amount int `json:"amount"`
+	Hand string `json:"hand,omitempty"`
+}
+
+// CardDrawn is a player drawing a card. Cards from the stock are kept secret, so the card
+// is only given when it came from the discard pile.
+type CardDrawn struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	FromDiscard bool `json:"fromDiscard"`
+	Card *Card `json:"card,omitempty"`
+}
+
+type CardDiscarded struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Card Card `json:"card"`
+}
+
+// Knocked is a player ending the hand, going gin if they have no deadwood at all
+type Knocked struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Gin bool `json:"gin"`
+}
+
+// MeldsShown is how a player's hand was laid down at the end of a hand, with any cards
+// they laid off on their opponent's melds
+type MeldsShown struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Melds []Deck `json:"melds"`
+	Deadwood Deck `json:"deadwood"`
+	LaidOff Deck `json:"laidOff,omitempty"`
+}
+
+// StarterCut is the card cut for the starter, which scores his heels for the dealer if it
+// is a jack
+type StarterCut struct {
+	Round int `json:"round"`
+	Card Card `json:"card"`
+	Heels bool `json:"heels"`
+}
+
+// CardPegged is a card played in pegging, with the count it made and what it scored
+type CardPegged struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Card Card `json:"card"`
+	Count int `json:"count"`
+	Points int `json:"points"`
+}
+
+// PeggedGo is the point for the last card played before the count starts again
+type PeggedGo struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Count int `json:"count"`
+	Points int `json:"points"`
+}
+
+// HandCounted is a hand or crib counted in the show
+type HandCounted struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Hand Deck `json:"hand"`
+	Starter Card `json:"starter"`
+	Crib bool `json:"crib"`
+	Count HandCount `json:"count"`
+	Points int `json:"points"`
+}
+
+// SuitDeclared is the suit called by the player of an eight
+type SuitDeclared struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Suit Suit `json:"suit"`
+}
+
+// PileReshuffled is the discard pile, all but its top card, shuffled to make a new stock,
+// or a blackjack shoe shuffled up again
+type PileReshuffled struct {
+	Round int `json:"round"`
+	Cards int `json:"cards"`
+}
+
+// UpcardDealt is the dealer's face up card in blackjack
+type UpcardDealt struct {
+	Round int `json:"round"`
+	Card Card `json:"card"`
+}
+
+// HandActed is a move on one of a player's blackjack hands, and the hand after it
+type HandActed struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Hand int `json:"hand"`
+	Action string `json:"action"`
+	Cards Deck `json:"cards"`
+	Total int `json:"total"`
+}
+
+// DealerPlayed is the dealer's hand once the hole card is turned and they have drawn
+type DealerPlayed struct {
+	Round int `json:"round"`
+	Hand Deck `json:"hand"`
+	Total int `json:"total"`
+	Blackjack bool `json:"blackjack"`
+}
+
+// HandSettled is a blackjack hand paid out, or lost, against the dealer
+type HandSettled struct {
+	Round int `json:"round"`
+	Player string `json:"player"`
+	Hand int `json:"hand"`
+	Cards Deck `json:"cards"`
+	Bet int `json:"bet"`
+	Payout int `json:"payout"`
+	Result HandResult `json:"result"`
+}
+
+// TrumpTurned is the card turned up after the deal, its suit being trump for the hand
+type TrumpTurned struct {
+	Round int `json:"round"`
+	Card Card `json:"card"`
+}
+
+type GameOver struct {
+	Result GameResult `json:"result"`
+}
+
+func (HandDealt) Type() EventType { return HandDealtEvent }
+func (CardsPassed) Type() EventType { return CardsPassedEvent }
+func (CardPlayed) Type() EventType { return CardPlayedEvent }
+func (HeartsBroken) Type() EventType { return HeartsBrokenEvent }
+func (TrickWon) Type() EventType { return TrickWonEvent }
+func (RoundScored) Type() EventType { return RoundScoredEvent }
+func (MoonShot) Type() EventType { return MoonShotEvent }
+func (GameOver) Type() EventType { return GameOverEvent }
+func (TurnTimedOut) Type() EventType { return TurnTimedOutEvent }
+func (PhaseChanged) Type() EventType { return PhaseChangedEvent }
+func (BidMade) Type() EventType { return BidMadeEvent }
+func (TrumpCalled) Type() EventType { return TrumpCalledEvent }
+func (CallMade) Type() EventType { return CallMadeEvent }
+func (BetPlaced) Type() EventType { return BetPlacedEvent }
+func (BoardDealt) Type() EventType { return BoardDealtEvent }
+func (PotWon) Type() EventType { return PotWonEvent }
+func (CardDrawn) Type() EventType { return CardDrawnEvent }
+func (CardDiscarded) Type() EventType { return CardDiscardedEvent }
+func (Knocked) Type() EventType { return KnockedEvent }
+func (MeldsShown) Type() EventType { return MeldsShownEvent }
+func (StarterCut) Type() EventType { return StarterCutEvent }
+func (CardPegged) Type() EventType { return CardPeggedEvent }
+func (PeggedGo) Type() EventType { return PeggedGoEvent }
+func (HandCounted) Type() EventType { return HandCountedEvent }
+func (SuitDeclared) Type() EventType { return SuitDeclaredEvent }
+func (PileReshuffled) Type() EventType { return PileReshuffledEvent }
+func (UpcardDealt) Type() EventType { return UpcardDealtEvent }
+func (HandActed) Type() EventType { return HandActedEvent }
+func (DealerPlayed) Type() EventType { return DealerPlayedEvent }
+func (HandSettled) Type() EventType { return HandSettledEvent }
+func (TrumpTurned) Type() EventType { return TrumpTurnedEvent }
+
+type subscription struct {
+	id int
+	listener EventListener
+}
+
+// eventStream keeps listeners in the order they subscribed, so that every listener is
+// called in the same order for each event.
+type eventStream struct {
+	lock sync.Mutex
+	emitting sync.Mutex
+	subscriptions []subscription
+	nextID int
+}
+
+func (s *eventStream) Subscribe(l EventListener) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscriptions = append(s.subscriptions, subscription{id, l})
+	return id
+}
+
+func (s *eventStream) Unsubscribe(id int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, sub := range s.subscriptions {
+		if sub.id == id {
+			s.subscriptions = append(s.subscriptions[:i:i], s.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *eventStream) emit(e Event) {
+	s.lock.Lock()
+	subscriptions := s.subscriptions
+	s.lock.Unlock()
+
+	// Players passing cards decide at the same time, so hold events back until the
+	// listeners are done with the one before
+	s.emitting.Lock()
+	defer s.emitting.Unlock()
+	for _, sub := range subscriptions {
+		sub.listener(e)
+	}
+}
@@ -1,8 +1,25 @@
 package game
 
-// type Game interface {
-
-// }
+// Game is a card game that can be played through to the end without knowing which game it
+// is. Everything particular to a game is found through its GameType.
+type Game interface {
+	GameState
+	Prompter
+	// Start plays the game in the background, sending the result once it is over
+	Start() chan GameResult
+	Cancel()
+	GameOver() bool
+	Pause() bool
+	Resume() bool
+	Paused() bool
+	SetTimer(TurnTimer)
+	CurrentPhase() Phase
+	Subscribe(EventListener) int
+	Unsubscribe(int)
+	// Save returns the game as it is, for its type to restore. Like snapshots, saves are
+	// only whole between moves.
+	Save() ([]byte, error)
+}
 
 type GameState interface {
 	GetDeciderInfo(Decider) interface{}
@@ -17,3 +34,40 @@ type Decider interface {
 	GetName() string
 	Notify(GameState)
 }
+
+type PromptKind string
+const (
+	// Pick Count of the Allowed cards, answered with their indices in Hand
+	CardsPrompt = PromptKind("cards")
+	// Pick one of the Options
+	OptionPrompt = PromptKind("option")
+	// Pick a whole number from Min to Max
+	NumberPrompt = PromptKind("number")
+)
+
+// Prompt describes a question well enough to ask it of someone who does not know the
+// game, so players and CPUs can be written once for every game
+type Prompt struct {
+	Question Question `json:"question"`
+	Kind PromptKind `json:"kind"`
+	Text string `json:"text"`
+	Hand Deck `json:"hand,omitempty"`
+	Count int `json:"count,omitempty"`
+	Allowed Deck `json:"allowed,omitempty"`
+	Options []string `json:"options,omitempty"`
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// Response is what a player picked for a prompt, with only the field for its kind set
+type Response struct {
+	Cards []int `json:"cards,omitempty"`
+	Option string `json:"option,omitempty"`
+	Number int `json:"number,omitempty"`
+}
+
+// Prompter turns the game's questions into prompts, and responses back into its answers
+type Prompter interface {
+	Prompt(Decider, Question) Prompt
+	Answer(Question, Response) Answer
+}
@@ -0,0 +1,526 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	GinRummyPlayers = 2
+	GinRummyHandSize = 10
+	// The most deadwood a player can knock with
+	KnockLimit = 10
+	// The hand is dead if nobody has knocked by the time the stock is down to this many
+	DeadStock = 2
+)
+
+const (
+	DrawQuestion = Question("draw")
+	KnockQuestion = Question("knock")
+)
+
+const (
+	StockOption = "stock"
+	DiscardPileOption = "discard pile"
+	KnockOption = "knock"
+	GinOption = "gin"
+	PlayOnOption = "play on"
+)
+
+// A hand ends when a player knocks, or is thrown in with no score when the stock runs low
+var GinRummyPhases = PhaseTransitions{
+	DealingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type GinRummyPlayer struct {
+	*Seat
+	Hand Deck
+	Score int
+	// Boxes counts the hands the player has won, each worth a bonus at the end
+	Boxes int
+	// How the hand was laid down when it was scored, kept to show until the next deal
+	Melds []Deck
+	Deadwood Deck
+	LaidOff Deck
+}
+
+type GinRummyGame struct {
+	Players map[string]*GinRummyPlayer
+	Options GinRummyOptions
+	// Dealt counts every hand dealt, including dead ones, where Round only counts those scored
+	Dealt int
+	Dealer int
+	Stock Deck
+	DiscardPile Deck
+	// The player whose turn it is, how far through it they are, and the card they took
+	// from the discard pile if they did, which they can not throw straight back
+	Turn string
+	Drawn bool
+	Discarded bool
+	TookDiscard *Card
+	Knocker string
+	Table
+}
+
+type GinRummyPlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Boxes int `json:"boxes"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	// Melds are only shown once the hand is over
+	Melds []Deck `json:"melds,omitempty"`
+	Deadwood Deck `json:"deadwood,omitempty"`
+	LaidOff Deck `json:"laidOff,omitempty"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type GinRummyGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]GinRummyPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Discard *Card `json:"discard,omitempty"`
+	StockSize int `json:"stockSize"`
+	TargetScore int `json:"targetScore"`
+	Knocker string `json:"knocker,omitempty"`
+	Hand Deck `json:"hand"`
+	// The least deadwood the hand can be melded down to
+	DeadwoodCount int `json:"deadwoodCount"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewGinRummyGame(deciders []Decider, seed int64, options GinRummyOptions) *GinRummyGame {
+	g := &GinRummyGame{
+		Players: map[string]*GinRummyPlayer{},
+		Options: options,
+	}
+	g.Init(g, seed, GinRummyPhases, DealingPhase, RandomDecision)
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &GinRummyPlayer{Seat: s}
+	})
+	return g
+}
+
+func (g *GinRummyGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]GinRummyPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		playerInfo[name] = GinRummyPlayerInfo{
+			NumCards: len(p.Hand),
+			Score: p.Score,
+			Boxes: p.Boxes,
+			Lead: name == g.Turn,
+			Dealer: name == g.PlayerOrder[g.Dealer],
+			Melds: p.Melds,
+			Deadwood: p.Deadwood,
+			LaidOff: p.LaidOff,
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+	}
+
+	var discard *Card
+	if len(g.DiscardPile) > 0 {
+		c := g.DiscardPile[len(g.DiscardPile) - 1]
+		discard = &c
+	}
+	p := g.Players[decider.GetName()]
+	_, deadwood := BestMelds(p.Hand)
+
+	return &GinRummyGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Discard: discard,
+		StockSize: len(g.Stock),
+		TargetScore: g.Options.TargetScore,
+		Knocker: g.Knocker,
+		Hand: p.Hand,
+		DeadwoodCount: DeadwoodCount(deadwood),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (gg *GinRummyGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range gg.PlayerOrder {
+		info := gg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v, %v boxes", name, info.Score, info.Boxes)
+		if info.Melds != nil {
+			fmt.Fprintf(&b, ", melds %v deadwood %v", info.Melds, info.Deadwood)
+		}
+		b.WriteString(" ) ")
+	}
+	fmt.Fprintf(&b, "\nStock: %v Discard: ", gg.StockSize)
+	if gg.Discard != nil {
+		b.WriteString(gg.Discard.String())
+	}
+	fmt.Fprintf(&b, "\nHand: %v Deadwood: %v", gg.Hand, gg.DeadwoodCount)
+	return b.String()
+}
+
+func (g *GinRummyGame) Prompt(d Decider, q Question) Prompt {
+	p := g.Players[d.GetName()]
+	switch q {
+	case DrawQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Draw from the stock or take the %v?", g.DiscardPile[len(g.DiscardPile) - 1]),
+			Hand: p.Hand.Copy(),
+			Options: []string{StockOption, DiscardPileOption},
+		}
+	case DiscardQuestion:
+		allowed := p.Hand.Copy()
+		if g.TookDiscard != nil {
+			allowed = allowed.Without(Deck{*g.TookDiscard})
+		}
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Discard a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: allowed,
+		}
+	case KnockQuestion:
+		_, deadwood := BestMelds(p.Hand)
+		knock := KnockOption
+		if len(deadwood) == 0 {
+			knock = GinOption
+		}
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Knock with %d deadwood?", DeadwoodCount(deadwood)),
+			Hand: p.Hand.Copy(),
+			Options: []string{knock, PlayOnOption},
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *GinRummyGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case DrawQuestion:
+		return DrawChoice{FromDiscard: r.Option == DiscardPileOption}
+	case DiscardQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	case KnockQuestion:
+		return KnockCall{Knock: r.Option == KnockOption || r.Option == GinOption}
+	}
+	return nil
+}
+
+func (g *GinRummyGame) GetPlayer(i int) *GinRummyPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// Opponent returns the other player
+func (g *GinRummyGame) Opponent(name string) *GinRummyPlayer {
+	return g.GetPlayer((g.GetOrder(name) + 1) % g.NumPlayers())
+}
+
+func (g *GinRummyGame) GameOver() bool {
+	return g.Cancelled || g.Phase == FinishedPhase
+}
+
+func (g *GinRummyGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Score
+	}
+	return scores
+}
+
+func (g *GinRummyGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+// DealRand returns the random source for the current deal, so any deal can be reproduced
+// on its own
+func (g *GinRummyGame) DealRand() *rand.Rand {
+	return g.SeededRand(int64(g.Dealt))
+}
+
+func (g *GinRummyGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	for g.Phase == PlayingPhase {
+		if cancelled := g.Players[g.Turn].TakeTurn(g); cancelled {
+			return true
+		}
+		g.NotifyAll()
+	}
+
+	// A dead hand is dealt again by the same dealer, without counting as a round
+	g.Dealt++
+	if g.Knocker == "" {
+		g.setPhase(DealingPhase)
+		g.NotifyAll()
+		return false
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Turn = ""
+	g.Round++
+	if g.Winner() != nil {
+		g.AddBonuses(roundPoints)
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	for _, name := range g.PlayerOrder {
+		p := g.Players[name]
+		g.emit(MeldsShown{Round: round, Player: name, Melds: p.Melds, Deadwood: p.Deadwood.Copy(), LaidOff: p.LaidOff.Copy()})
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal gives each player ten cards, starting with the player who is not dealing, and
+// turns up the next card to start the discard pile
+func (g *GinRummyGame) Deal() {
+	g.Knocker = ""
+	g.Drawn, g.Discarded, g.TookDiscard = false, false, nil
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.Melds, p.Deadwood, p.LaidOff = nil, nil, nil
+	}
+	g.Stock = NewDeck()
+	g.Stock.Shuffle(g.DealRand())
+	for i := 1; i <= GinRummyHandSize * g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer + i) % g.NumPlayers())
+		p.Hand = append(p.Hand, g.Stock.Deal())
+	}
+	g.DiscardPile = Deck{g.Stock.Deal()}
+	for _, p := range g.Players {
+		p.Hand.Sort()
+	}
+	g.Turn = g.PlayerOrder[(g.Dealer + 1) % g.NumPlayers()]
+	g.setPhase(PlayingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// TakeTurn has the player draw, discard and then knock if they can and want to, picking up
+// partway through if the turn was already started. The turn passes on unless the hand is
+// over.
+func (p *GinRummyPlayer) TakeTurn(g *GinRummyGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	name := p.GetName()
+
+	if !g.Drawn {
+		answer, cancelled := g.Ask(p.Seat, DrawQuestion, g)
+		if cancelled {
+			return true
+		}
+		// Anything but the discard pile is taken as the stock
+		choice, _ := answer.(DrawChoice)
+		var c Card
+		if choice.FromDiscard {
+			c = g.DiscardPile[len(g.DiscardPile) - 1]
+			g.DiscardPile = g.DiscardPile[:len(g.DiscardPile) - 1]
+			g.TookDiscard = &c
+		} else {
+			c = g.Stock.Deal()
+		}
+		p.Hand = append(p.Hand, c)
+		p.Hand.Sort()
+		g.Drawn = true
+		drawn := CardDrawn{Round: g.Round, Player: name, FromDiscard: choice.FromDiscard}
+		if choice.FromDiscard {
+			drawn.Card = &c
+		}
+		g.emit(drawn)
+		g.NotifyAll()
+	}
+
+	if !g.Discarded {
+		for {
+			answer, cancelled := g.Ask(p.Seat, DiscardQuestion, g)
+			if cancelled {
+				return true
+			}
+			index, violation := ValidateIndex(p.Hand, answer)
+			if violation == nil && g.TookDiscard != nil && p.Hand[index] == *g.TookDiscard {
+				violation = &RuleViolation{
+					Code: DiscardTakenCardViolation,
+					Message: "You can not discard the card you took from the discard pile",
+					Card: g.TookDiscard,
+				}
+			}
+			if violation != nil {
+				ShowViolation(p.Decider, violation)
+				continue
+			}
+			c := p.Hand[index]
+			p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
+			g.DiscardPile = append(g.DiscardPile, c)
+			g.Discarded = true
+			g.emit(CardDiscarded{Round: g.Round, Player: name, Card: c})
+			break
+		}
+	}
+
+	if g.CanKnock(name) {
+		answer, cancelled := g.Ask(p.Seat, KnockQuestion, g)
+		if cancelled {
+			return true
+		}
+		if call, _ := answer.(KnockCall); call.Knock {
+			_, deadwood := BestMelds(p.Hand)
+			g.Knocker = name
+			g.setPhase(ScoringPhase)
+			g.emit(Knocked{Round: g.Round, Player: name, Gin: len(deadwood) == 0})
+			return false
+		}
+	}
+
+	if len(g.Stock) <= DeadStock {
+		g.setPhase(ScoringPhase)
+		return false
+	}
+	g.Turn = g.Opponent(name).GetName()
+	g.Drawn, g.Discarded, g.TookDiscard = false, false, nil
+	return false
+}
+
+// CanKnock is true if the player's hand melds down to little enough deadwood to knock with
+func (g *GinRummyGame) CanKnock(name string) bool {
+	_, deadwood := BestMelds(g.Players[name].Hand)
+	return DeadwoodCount(deadwood) <= KnockLimit
+}
+
+// ScoreRound lays down both hands, letting the defender lay off on the knocker's melds
+// unless the knocker went gin, and gives the winner of the hand their points and a box.
+// The loser deals next.
+func (g *GinRummyGame) ScoreRound() map[string]int {
+	knocker := g.Players[g.Knocker]
+	defender := g.Opponent(g.Knocker)
+	knocker.Melds, knocker.Deadwood = BestMelds(knocker.Hand)
+	knockerCount := DeadwoodCount(knocker.Deadwood)
+	gin := knockerCount == 0
+
+	if gin {
+		defender.Melds, defender.Deadwood = BestMelds(defender.Hand)
+	} else {
+		// The defender's melds are picked to leave the least deadwood after laying off,
+		// which is not always the same as the least before it
+		defender.Melds, defender.Deadwood = ArrangeMelds(defender.Hand, func(melds []Deck, deadwood Deck) int {
+			_, left, _ := LayOff(knocker.Melds, deadwood)
+			return DeadwoodCount(left)
+		})
+		knocker.Melds, defender.Deadwood, defender.LaidOff = LayOff(knocker.Melds, defender.Deadwood)
+	}
+	defenderCount := DeadwoodCount(defender.Deadwood)
+
+	winner, loser, points := knocker, defender, defenderCount - knockerCount
+	switch {
+	case gin:
+		points += g.Options.GinBonus
+	case defenderCount <= knockerCount:
+		winner, loser, points = defender, knocker, knockerCount - defenderCount + g.Options.UndercutBonus
+	}
+	winner.Score += points
+	winner.Boxes++
+	g.Dealer = g.GetOrder(loser.GetName())
+
+	roundPoints := map[string]int{}
+	for name := range g.Players {
+		roundPoints[name] = 0
+	}
+	roundPoints[winner.GetName()] = points
+	return roundPoints
+}
+
+// Winner returns the first player to reach the target score, or nil while nobody has
+func (g *GinRummyGame) Winner() *GinRummyPlayer {
+	for _, p := range g.Players {
+		if p.Score >= g.Options.TargetScore {
+			return p
+		}
+	}
+	return nil
+}
+
+// AddBonuses ends the game by giving the winner the game bonus and every player a bonus
+// for each box, doubling the winner's score if the loser never won a hand. The bonuses are
+// added to the last hand's points.
+func (g *GinRummyGame) AddBonuses(roundPoints map[string]int) {
+	winner := g.Winner()
+	bonuses := map[string]int{}
+	bonuses[winner.GetName()] += g.Options.GameBonus
+	for name, p := range g.Players {
+		bonuses[name] += p.Boxes * g.Options.BoxBonus
+	}
+	if g.Options.Shutout && g.Opponent(winner.GetName()).Boxes == 0 {
+		bonuses[winner.GetName()] += winner.Score + bonuses[winner.GetName()]
+	}
+	for name, bonus := range bonuses {
+		g.Players[name].Score += bonus
+		roundPoints[name] += bonus
+	}
+}
+
+// GinRummyCPU takes the discard when it leaves less deadwood, throws away whatever leaves
+// the least deadwood, and knocks as soon as it can
+func GinRummyCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	gg, ok := g.(*GinRummyGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	hand := gg.Players[d.GetName()].Hand
+
+	switch q {
+	case DrawQuestion:
+		_, now := BestMelds(hand)
+		top := gg.DiscardPile[len(gg.DiscardPile) - 1]
+		_, after := bestDiscard(append(hand.Copy(), top), &top)
+		return DrawChoice{FromDiscard: after < DeadwoodCount(now)}
+	case DiscardQuestion:
+		index, _ := bestDiscard(hand, gg.TookDiscard)
+		return CardPlay{index}
+	case KnockQuestion:
+		return KnockCall{Knock: true}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// bestDiscard returns the index of the card to throw away to leave the least deadwood,
+// the highest card on a tie, and the deadwood left. The kept card can not be thrown.
+func bestDiscard(hand Deck, keep *Card) (int, int) {
+	best, bestCount := -1, 0
+	for i, c := range hand {
+		if keep != nil && c == *keep {
+			continue
+		}
+		_, deadwood := BestMelds(hand.Without(Deck{c}))
+		count := DeadwoodCount(deadwood)
+		if best == -1 || count < bestCount || count == bestCount && DeadwoodValue(c) > DeadwoodValue(hand[best]) {
+			best, bestCount = i, count
+		}
+	}
+	return best, bestCount
+}
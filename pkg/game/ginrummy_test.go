@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestGinRummyScoreRound(t *testing.T) {
+	tests := []struct {
+		name string
+		knocker, defender string
+		winner string
+		points int
+	}{
+		{"knock", "2H 3H 4H 5S 5D 5C 9S 9D 9C 3S", "KH QH JS 10C 8D 7C 6S AD 2D 4C", "k", 65},
+		// The defender can not lay off on a gin hand, so the six of hearts counts
+		{"gin", "2H 3H 4H 5H 5S 5D 5C 9S 9D 9C", "6H QH JS 10C 8D 7C 6S AD 2D 4C", "k", 64 + 25},
+		{"undercut", "2H 3H 4H 5S 5D 5C 9S 9D 9C 8S", "6H 7H 8H QC QD QS JC JD JS AC", "d", 7 + 25},
+		{"tie is an undercut", "2H 3H 4H 5S 5D 5C 9S 9D 9C 8S", "6H 7H 8H QC QD QS JC JD JS 8C", "d", 25},
+		// Laying off the five and nine of hearts brings the defender under the knocker
+		{"undercut by laying off", "2H 3H 4H 5S 5D 5C 9S 9D 9C 8S", "5H 9H QC QD QS JC JD JS AC 2C", "d", 5 + 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deciders := []Decider{NewRandomCPU("k", 1), NewRandomCPU("d", 2)}
+			g := NewGinRummyGame(deciders, 1, GinRummyOptions{TargetScore: 100, GinBonus: 25, UndercutBonus: 25})
+			g.Players["k"].Hand = cards(tt.knocker)
+			g.Players["d"].Hand = cards(tt.defender)
+			g.Knocker = "k"
+
+			points := g.ScoreRound()
+			loser := "d"
+			if tt.winner == "d" {
+				loser = "k"
+			}
+			if points[tt.winner] != tt.points || points[loser] != 0 {
+				t.Errorf("got %v, want %d for %s", points, tt.points, tt.winner)
+			}
+			if g.Players[tt.winner].Boxes != 1 || g.Dealer != g.GetOrder(loser) {
+				t.Errorf("the winner should get a box and the loser deal next")
+			}
+		})
+	}
+}
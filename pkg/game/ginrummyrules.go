@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+)
+
+type GinRummyOptions struct {
+	// The score a player has to reach to end the game
+	TargetScore int `json:"target_score"`
+	GinBonus int `json:"gin_bonus"`
+	UndercutBonus int `json:"undercut_bonus"`
+	// Added at the end of the game, for each hand won and for winning the game
+	BoxBonus int `json:"box_bonus"`
+	GameBonus int `json:"game_bonus"`
+	// Whether the winner's score is doubled when the loser did not win a hand
+	Shutout bool `json:"shutout"`
+}
+
+func (o GinRummyOptions) Validate() error {
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	if o.GinBonus < 0 || o.UndercutBonus < 0 || o.BoxBonus < 0 || o.GameBonus < 0 {
+		return fmt.Errorf("Bonuses can not be negative")
+	}
+	return nil
+}
+
+var GinRummyType = &GameType{
+	Name: "ginrummy",
+	Title: "Gin Rummy",
+	MinPlayers: GinRummyPlayers,
+	MaxPlayers: GinRummyPlayers,
+	Options: []Option{
+		{Key: "target_score", Label: "Target score", Type: NumberOption, Default: 100, Min: 1},
+		{Key: "gin_bonus", Label: "Gin bonus", Type: NumberOption, Default: 25},
+		{Key: "undercut_bonus", Label: "Undercut bonus", Type: NumberOption, Default: 25},
+		{Key: "box_bonus", Label: "Bonus for each hand won", Type: NumberOption, Default: 25},
+		{Key: "game_bonus", Label: "Game bonus", Type: NumberOption, Default: 100},
+		{Key: "shutout", Label: "Double the score for a shutout", Type: BoolOption, Default: true},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o GinRummyOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewGinRummyGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseGinRummySnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreGinRummyGame(s, deciders)
+	},
+	CPU: GinRummyCPU,
+	Validate: func(options Options) error {
+		var o GinRummyOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(GinRummyType)
+}
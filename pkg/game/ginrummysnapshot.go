@@ -0,0 +1,157 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GinRummySnapshot is everything needed to carry on a game of gin rummy
+type GinRummySnapshot struct {
+	TableSnapshot
+	Options GinRummyOptions `json:"options"`
+	Dealt int `json:"dealt"`
+	Dealer int `json:"dealer"`
+	Stock Deck `json:"stock"`
+	DiscardPile Deck `json:"discardPile"`
+	Turn string `json:"turn"`
+	Drawn bool `json:"drawn"`
+	Discarded bool `json:"discarded"`
+	TookDiscard *Card `json:"tookDiscard,omitempty"`
+	Knocker string `json:"knocker,omitempty"`
+	Players map[string]GinRummyPlayerSnapshot `json:"players"`
+}
+
+type GinRummyPlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Score int `json:"score"`
+	Boxes int `json:"boxes"`
+	Melds []Deck `json:"melds,omitempty"`
+	Deadwood Deck `json:"deadwood,omitempty"`
+	LaidOff Deck `json:"laidOff,omitempty"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func copyMelds(melds []Deck) []Deck {
+	if melds == nil {
+		return nil
+	}
+	copied := []Deck{}
+	for _, meld := range melds {
+		copied = append(copied, meld.Copy())
+	}
+	return copied
+}
+
+func (g *GinRummyGame) Snapshot() *GinRummySnapshot {
+	players := map[string]GinRummyPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = GinRummyPlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Score: p.Score,
+			Boxes: p.Boxes,
+			Melds: copyMelds(p.Melds),
+			Deadwood: p.Deadwood.Copy(),
+			LaidOff: p.LaidOff.Copy(),
+			TimeBank: p.TimeBank(),
+		}
+	}
+	var took *Card
+	if g.TookDiscard != nil {
+		c := *g.TookDiscard
+		took = &c
+	}
+
+	return &GinRummySnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Dealt: g.Dealt,
+		Dealer: g.Dealer,
+		Stock: g.Stock.Copy(),
+		DiscardPile: g.DiscardPile.Copy(),
+		Turn: g.Turn,
+		Drawn: g.Drawn,
+		Discarded: g.Discarded,
+		TookDiscard: took,
+		Knocker: g.Knocker,
+		Players: players,
+	}
+}
+
+func (g *GinRummyGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreGinRummyGame sets up a game as it was in the snapshot, with the deciders taking
+// the seats with their names
+func RestoreGinRummyGame(s *GinRummySnapshot, deciders []Decider) (*GinRummyGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewGinRummyGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Dealt = s.Dealt
+	g.Dealer = s.Dealer
+	g.Stock = s.Stock.Copy()
+	g.DiscardPile = s.DiscardPile.Copy()
+	g.Turn = s.Turn
+	g.Drawn = s.Drawn
+	g.Discarded = s.Discarded
+	if s.TookDiscard != nil {
+		c := *s.TookDiscard
+		g.TookDiscard = &c
+	}
+	g.Knocker = s.Knocker
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Score = ps.Score
+		p.Boxes = ps.Boxes
+		p.Melds = copyMelds(ps.Melds)
+		p.Deadwood = ps.Deadwood.Copy()
+		p.LaidOff = ps.LaidOff.Copy()
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks the dealer is at a seat, that a turn being played has a seated player and something to draw, and that a knock being scored was made by a seated player
+func (s *GinRummySnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(GinRummyPlayers, GinRummyPlayers, GinRummyPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if s.Dealer < 0 || s.Dealer >= len(s.PlayerOrder) {
+		return fmt.Errorf("dealer is not at a seat")
+	}
+	if s.Phase == PlayingPhase {
+		if _, ok := s.Players[s.Turn]; !ok {
+			return fmt.Errorf("[%v] is not seated to take a turn", s.Turn)
+		}
+		if len(s.DiscardPile) == 0 && !s.Drawn {
+			return fmt.Errorf("nothing to draw from the discard pile")
+		}
+		if len(s.Stock) == 0 {
+			return fmt.Errorf("nothing to draw from the stock")
+		}
+	}
+	if s.Phase == ScoringPhase && s.Knocker != "" {
+		if _, ok := s.Players[s.Knocker]; !ok {
+			return fmt.Errorf("knocker [%v] is not seated", s.Knocker)
+		}
+	}
+	return nil
+}
+
+func ParseGinRummySnapshot(data []byte) (*GinRummySnapshot, error) {
+	s := &GinRummySnapshot{}
+	return s, parseSnapshot(data, s)
+}
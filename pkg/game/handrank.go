@@ -0,0 +1,205 @@
+package game
+
+import (
+	"math/bits"
+)
+
+type HandCategory int
+const (
+	HighCard = HandCategory(iota)
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var handCategoryNames = []string{
+	"high card",
+	"pair",
+	"two pair",
+	"three of a kind",
+	"straight",
+	"flush",
+	"full house",
+	"four of a kind",
+	"straight flush",
+}
+
+func (c HandCategory) String() string {
+	return handCategoryNames[c]
+}
+
+// HandRank is the value of the best five card poker hand in some cards. Higher ranks beat
+// lower ones, and equal ranks split. The category is kept above the ranks that break ties
+// within it, four bits each, most important first.
+type HandRank uint32
+
+func (r HandRank) Category() HandCategory {
+	return HandCategory(r >> 20)
+}
+
+func (r HandRank) String() string {
+	return r.Category().String()
+}
+
+func makeHandRank(c HandCategory, ranks ...int) HandRank {
+	r := HandRank(c) << 20
+	for i, rank := range ranks {
+		r |= HandRank(rank) << uint(16 - 4 * i)
+	}
+	return r
+}
+
+// straightHigh returns the rank of the top card of the highest straight in the mask of
+// ranks, the five for a wheel, or -1 if there is none
+func straightHigh(ranks uint16) int {
+	for high := len(CardValues) - 1; high >= 4; high-- {
+		straight := uint16(0x1F) << uint(high - 4)
+		if ranks & straight == straight {
+			return high
+		}
+	}
+	wheel := uint16(1) << uint(len(CardValues) - 1) | 0xF
+	if ranks & wheel == wheel {
+		return 3
+	}
+	return -1
+}
+
+// topRanks returns the highest count ranks in the mask, highest first
+func topRanks(ranks uint16, count int) []int {
+	top := make([]int, 0, count)
+	for r := len(CardValues) - 1; r >= 0 && len(top) < count; r-- {
+		if ranks & (1 << uint(r)) != 0 {
+			top = append(top, r)
+		}
+	}
+	return top
+}
+
+// rankIndex is the same as ValueIndex, and suitIndex as SuitIndex, only switching rather
+// than searching, since hands get ranked a lot
+func rankIndex(v CardValue) int {
+	switch v {
+	case Two:
+		return 0
+	case Three:
+		return 1
+	case Four:
+		return 2
+	case Five:
+		return 3
+	case Six:
+		return 4
+	case Seven:
+		return 5
+	case Eight:
+		return 6
+	case Nine:
+		return 7
+	case Ten:
+		return 8
+	case Jack:
+		return 9
+	case Queen:
+		return 10
+	case King:
+		return 11
+	}
+	return 12
+}
+
+func suitIndex(s Suit) int {
+	switch s {
+	case Clubs:
+		return 0
+	case Diamonds:
+		return 1
+	case Spades:
+		return 2
+	}
+	return 3
+}
+
+// EvaluateHand ranks the best five card hand that can be made from five to seven cards,
+// without trying each five in turn. With seven cards or fewer a flush rules out four of
+// a kind and a full house, so it is safe to look for flushes first.
+func EvaluateHand(cards Deck) HandRank {
+	var counts [13]int
+	var suitRanks [4]uint16
+	var all uint16
+	for _, c := range cards {
+		r := rankIndex(c.Value)
+		counts[r]++
+		suitRanks[suitIndex(c.Suit)] |= 1 << uint(r)
+		all |= 1 << uint(r)
+	}
+
+	for _, ranks := range suitRanks {
+		if bits.OnesCount16(ranks) < 5 {
+			continue
+		}
+		if high := straightHigh(ranks); high != -1 {
+			return makeHandRank(StraightFlush, high)
+		}
+		return makeHandRank(Flush, topRanks(ranks, 5)...)
+	}
+
+	// Ranks by how many of them there are, highest first
+	var quads, trips, pairs, singles [7]int
+	var nQuads, nTrips, nPairs, nSingles int
+	for r := len(CardValues) - 1; r >= 0; r-- {
+		switch counts[r] {
+		case 4:
+			quads[nQuads] = r
+			nQuads++
+		case 3:
+			trips[nTrips] = r
+			nTrips++
+		case 2:
+			pairs[nPairs] = r
+			nPairs++
+		case 1:
+			singles[nSingles] = r
+			nSingles++
+		}
+	}
+
+	switch {
+	case nQuads > 0:
+		kicker := -1
+		for r := len(CardValues) - 1; r >= 0 && kicker == -1; r-- {
+			if counts[r] > 0 && r != quads[0] {
+				kicker = r
+			}
+		}
+		if kicker == -1 {
+			return makeHandRank(FourOfAKind, quads[0])
+		}
+		return makeHandRank(FourOfAKind, quads[0], kicker)
+	case nTrips > 1:
+		return makeHandRank(FullHouse, trips[0], trips[1])
+	case nTrips > 0 && nPairs > 0:
+		return makeHandRank(FullHouse, trips[0], pairs[0])
+	}
+	if high := straightHigh(all); high != -1 {
+		return makeHandRank(Straight, high)
+	}
+	switch {
+	case nTrips > 0:
+		return makeHandRank(ThreeOfAKind, trips[0], singles[0], singles[1])
+	case nPairs > 1:
+		kicker := singles[0]
+		if nPairs > 2 && pairs[2] > kicker || nSingles == 0 {
+			kicker = pairs[2]
+		}
+		return makeHandRank(TwoPair, pairs[0], pairs[1], kicker)
+	case nPairs > 0:
+		return makeHandRank(OnePair, pairs[0], singles[0], singles[1], singles[2])
+	}
+	return makeHandRank(HighCard, singles[:5]...)
+}
@@ -0,0 +1,290 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// cards reads a hand written like "AS 10H 2C", for tests
+func cards(s string) Deck {
+	values := map[string]CardValue{
+		"2": Two, "3": Three, "4": Four, "5": Five, "6": Six, "7": Seven, "8": Eight,
+		"9": Nine, "10": Ten, "J": Jack, "Q": Queen, "K": King, "A": Ace,
+	}
+	suits := map[byte]Suit{'C': Clubs, 'D': Diamonds, 'H': Hearts, 'S': Spades}
+	d := Deck{}
+	for _, f := range strings.Fields(s) {
+		d = append(d, Card{Suit: suits[f[len(f) - 1]], Value: values[f[:len(f) - 1]]})
+	}
+	return d
+}
+
+func TestEvaluateHandCategories(t *testing.T) {
+	tests := []struct {
+		hand string
+		want HandCategory
+	}{
+		{"AS KD 9C 7H 3S", HighCard},
+		{"AS AD 9C 7H 3S", OnePair},
+		{"AS AD 9C 9H 3S", TwoPair},
+		{"AS AD AC 9H 3S", ThreeOfAKind},
+		{"5S 4D 3C 2H AS", Straight},
+		{"AS KD QC JH 10S", Straight},
+		{"AS JS 9S 7S 3S", Flush},
+		{"AS AD AC 9H 9S", FullHouse},
+		{"AS AD AC AH 9S", FourOfAKind},
+		{"5S 4S 3S 2S AS", StraightFlush},
+		{"AS KS QS JS 10S", StraightFlush},
+	}
+	for _, test := range tests {
+		if got := EvaluateHand(cards(test.hand)).Category(); got != test.want {
+			t.Errorf("%v is a %v, want %v", test.hand, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateHandOrder(t *testing.T) {
+	// Each hand beats the one before it, crossing every category boundary
+	hands := []string{
+		"7S 5D 4C 3H 2S",
+		"AS KD QC JH 9S",
+		"2S 2D 3C 4H 5S",
+		"AS AD KC QH JS",
+		"2S 2D 3C 3H 4S",
+		"AS AD KC KH QS",
+		"2S 2D 2C 3H 4S",
+		"AS AD AC KH QS",
+		"5S 4D 3C 2H AS",
+		"6S 5D 4C 3H 2S",
+		"AS KD QC JH 10S",
+		"7S 5S 4S 3S 2S",
+		"AS KS QS JS 9S",
+		"2S 2D 2C 3H 3S",
+		"AS AD AC KH KS",
+		"2S 2D 2C 2H 3S",
+		"AS AD AC AH KS",
+		"5S 4S 3S 2S AS",
+		"6S 5S 4S 3S 2S",
+		"AS KS QS JS 10S",
+	}
+	for i := 1; i < len(hands); i++ {
+		lower, higher := EvaluateHand(cards(hands[i - 1])), EvaluateHand(cards(hands[i]))
+		if higher <= lower {
+			t.Errorf("%v (%v) does not beat %v (%v)", hands[i], higher, hands[i - 1], lower)
+		}
+	}
+}
+
+func TestEvaluateHandWheel(t *testing.T) {
+	wheel := EvaluateHand(cards("AS 2D 3C 4H 5S"))
+	six := EvaluateHand(cards("2D 3C 4H 5S 6S"))
+	if wheel >= six {
+		t.Errorf("the wheel ranks %v, not below a six high straight at %v", wheel, six)
+	}
+	if wheel <= EvaluateHand(cards("AS KD QC JH 9S")) {
+		t.Errorf("the wheel does not beat ace high")
+	}
+}
+
+func TestEvaluateHandKickers(t *testing.T) {
+	tests := []struct {
+		name string
+		better string
+		worse string
+	}{
+		{"pair kicker", "8S 8D AC 5H 3S", "8H 8C KC QH JS"},
+		{"pair last kicker", "8S 8D AC 5H 3S", "8H 8C AD 5S 2S"},
+		{"two pair top pair", "KS KD 3C 3H 2S", "QS QD JC JH AS"},
+		{"two pair bottom pair", "KS KD 4C 4H 2S", "KH KC 3C 3H AS"},
+		{"two pair kicker", "KS KD 4C 4H 9S", "KH KC 4D 4S 8S"},
+		{"trips kicker", "7S 7D 7C AH 2S", "7H 7C 7D KH QS"},
+		{"trips second kicker", "7S 7D 7C AH 3S", "7H 7C 7D AS 2S"},
+	}
+	for _, test := range tests {
+		better, worse := EvaluateHand(cards(test.better)), EvaluateHand(cards(test.worse))
+		if better <= worse {
+			t.Errorf("%v: %v does not beat %v", test.name, test.better, test.worse)
+		}
+	}
+}
+
+func TestEvaluateHandBestFiveOfSeven(t *testing.T) {
+	tests := []struct {
+		hand string
+		best string
+	}{
+		// A flush beats the straight in the same seven cards
+		{"9H 8H 7C 6H 5D 2H KH", "KH 9H 8H 6H 2H"},
+		// Two trips make a full house of the higher three over the lower pair
+		{"9S 9D 9C 4H 4S 4D KC", "9S 9D 9C 4H 4S"},
+		// The best two of three pairs, with the highest card left as the kicker
+		{"AS AD 8C 8H 3S 3D QC", "AS AD 8C 8H QC"},
+		// Six to a straight uses the top five
+		{"2S 3D 4C 5H 6S 7D KC", "3D 4C 5H 6S 7D"},
+	}
+	for _, test := range tests {
+		got, want := EvaluateHand(cards(test.hand)), EvaluateHand(cards(test.best))
+		if got != want {
+			t.Errorf("%v ranks %v, want %v like %v", test.hand, got, want, test.best)
+		}
+	}
+}
+
+func TestEvaluateHandSplits(t *testing.T) {
+	board := "AS KD QC JH 9S"
+	// Both players play the board's ace high, so their hole cards don't matter
+	a := EvaluateHand(append(cards("2C 3D"), cards(board)...))
+	b := EvaluateHand(append(cards("4C 5D"), cards(board)...))
+	if a != b {
+		t.Errorf("playing the board ranks %v and %v, want a split", a, b)
+	}
+	// The same hand in other suits splits too
+	a = EvaluateHand(append(cards("10C 2D"), cards(board)...))
+	b = EvaluateHand(append(cards("10D 3H"), cards(board)...))
+	if a != b || a.Category() != Straight {
+		t.Errorf("two broadway straights rank %v and %v, want a split", a, b)
+	}
+}
+
+func TestAwardPotsSidePots(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+	g := NewHoldemGame(deciders, 1, HoldemOptions{})
+	g.Board = cards("2C 7D 9H JS KC")
+	g.Showdown = true
+	hands := map[string]string{"a": "KD KH", "b": "JD JH", "c": "9D 9C", "d": "3D 4D"}
+	committed := map[string]int{"a": 50, "b": 150, "c": 300, "d": 300}
+	for name, p := range g.Players {
+		p.Hand = cards(hands[name])
+		p.Committed = committed[name]
+		p.StartChips = committed[name]
+	}
+	g.Players["d"].Folded = true
+
+	_, won := g.AwardPots()
+	want := []PotWon{
+		{Players: []string{"a"}, Amount: 200},
+		{Players: []string{"b"}, Amount: 300},
+		{Players: []string{"c"}, Amount: 300},
+	}
+	if len(won) != len(want) {
+		t.Fatalf("got %d pots, want %d: %+v", len(won), len(want), won)
+	}
+	for i := range want {
+		if won[i].Amount != want[i].Amount || strings.Join(won[i].Players, ",") != strings.Join(want[i].Players, ",") {
+			t.Errorf("pot %d went %v %d, want %v %d", i, won[i].Players, won[i].Amount, want[i].Players, want[i].Amount)
+		}
+	}
+	chips := map[string]int{"a": 200, "b": 300, "c": 300, "d": 0}
+	for name, want := range chips {
+		if got := g.Players[name].Chips; got != want {
+			t.Errorf("%v has %d chips, want %d", name, got, want)
+		}
+	}
+}
+
+func TestAwardPotsSplitOddChip(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3)}
+	g := NewHoldemGame(deciders, 1, HoldemOptions{})
+	g.Board = cards("AS KD QC JH 9S")
+	g.Showdown = true
+	for name, p := range g.Players {
+		p.Hand = map[string]Deck{"a": cards("2C 3D"), "b": cards("4C 5D"), "c": cards("6C 7D")}[name]
+		p.Committed = 51
+	}
+	g.Players["c"].Committed = 1
+	g.Players["c"].Folded = true
+
+	_, won := g.AwardPots()
+	if len(won) != 1 || len(won[0].Players) != 2 || won[0].Amount != 103 {
+		t.Fatalf("got %+v, want one pot of 103 split two ways", won)
+	}
+	// The odd chip goes to the first winner left of the button
+	first := g.Players[won[0].Players[0]].Chips
+	second := g.Players[won[0].Players[1]].Chips
+	if first != 52 || second != 51 {
+		t.Errorf("the split went %d and %d, want 52 and 51", first, second)
+	}
+}
+
+// slowRank ranks exactly five cards the obvious way, by sorting the ranks by how many of
+// each there are, to check EvaluateHand against
+func slowRank(hand Deck) HandRank {
+	counts := map[int]int{}
+	suits := map[Suit]bool{}
+	for _, c := range hand {
+		counts[c.ValueIndex()]++
+		suits[c.Suit] = true
+	}
+	ranks := []int{}
+	for r := range counts {
+		ranks = append(ranks, r)
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		if counts[ranks[i]] != counts[ranks[j]] {
+			return counts[ranks[i]] > counts[ranks[j]]
+		}
+		return ranks[i] > ranks[j]
+	})
+	flush := len(suits) == 1
+	straight := -1
+	if len(ranks) == 5 {
+		if ranks[0] - ranks[4] == 4 {
+			straight = ranks[0]
+		}
+		if ranks[0] == 12 && ranks[1] == 3 {
+			straight = 3
+		}
+	}
+	top, second := counts[ranks[0]], 0
+	if len(ranks) > 1 {
+		second = counts[ranks[1]]
+	}
+	switch {
+	case straight >= 0 && flush:
+		return makeHandRank(StraightFlush, straight)
+	case top == 4:
+		return makeHandRank(FourOfAKind, ranks...)
+	case top == 3 && second == 2:
+		return makeHandRank(FullHouse, ranks...)
+	case flush:
+		return makeHandRank(Flush, ranks...)
+	case straight >= 0:
+		return makeHandRank(Straight, straight)
+	case top == 3:
+		return makeHandRank(ThreeOfAKind, ranks...)
+	case top == 2 && second == 2:
+		return makeHandRank(TwoPair, ranks...)
+	case top == 2:
+		return makeHandRank(OnePair, ranks...)
+	}
+	return makeHandRank(HighCard, ranks...)
+}
+
+// slowBest tries every five of the cards
+func slowBest(hand Deck) HandRank {
+	if len(hand) == 5 {
+		return slowRank(hand)
+	}
+	best := HandRank(0)
+	for i := range hand {
+		rest := append(append(Deck{}, hand[:i]...), hand[i+1:]...)
+		if r := slowBest(rest); r > best {
+			best = r
+		}
+	}
+	return best
+}
+
+func TestEvaluateHandMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		d := NewShuffledDeck(r)
+		for _, n := range []int{5, 6, 7} {
+			if got, want := EvaluateHand(d[:n]), slowBest(d[:n]); got != want {
+				t.Fatalf("%v ranks %x, want %x", d[:n], got, want)
+			}
+		}
+	}
+}
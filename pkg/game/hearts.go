@@ -1,15 +1,17 @@
 package game
 
 import (
+	"fmt"
 	"math/rand"
-	"sort"
+	"strings"
 )
 
 type Player struct {
-	Decider Decider
+	*Seat
 	Hand Deck
 	Score int
-	roundPoints int
+	taken Deck
+	tricksWon int
 }
 
 type PassDirection string
@@ -25,24 +27,35 @@ const (
 	PlayOnTrickQuestion = Question("play_on_trick")
 )
 
+const (
+	MinHeartsPlayers = 3
+	MaxHeartsPlayers = 6
+	CardsToPass = 3
+)
+
+// HeartsRemovalOrder is the order cards are taken out of the deck so it deals out evenly,
+// e.g. the 2 of diamonds for 3 players or the 2s of diamonds and clubs for 5. Hearts and
+// the queen of spades always stay in, so a moon is always worth the same.
+var HeartsRemovalOrder = Deck{{Diamonds, Two}, {Clubs, Two}, {Diamonds, Three}, {Clubs, Three}}
+
 type HeartsGame struct {
 	Players map[string]*Player
-	PlayerOrder []string
 	PassDirection PassDirection
-	CurrentTrick Deck
 	HeartsBroken bool
 	MaxPoints int
-	Leader string
-	cancelListeners map[int]chan bool
-	nextListenerID int
-	Cancelled bool
+	Rules HeartsRules
+	Table
+	TrickTaking
 }
 
 type PlayerInfo struct {
 	NumCards int `json:"numCards"`
 	Score int `json:"score"`
 	RoundPoints int `json:"roundPoints"`
+	PointCards Deck `json:"pointCards"`
 	Lead bool `json:"lead"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
 }
 
 type HeartsGameInfo struct {
@@ -51,43 +64,58 @@ type HeartsGameInfo struct {
 	PlayerOrder []string `json:"playerOrder"`
 	PassDirection PassDirection `json:"passDirection"`
 	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
 	HeartsBroken bool `json:"heartsBroken"`
+	Trick int `json:"trick"`
+	OpeningCard Card `json:"openingCard"`
 	MaxPoints int `json:"maxPoints"`
+	Rules HeartsRules `json:"rules"`
 	Hand Deck `json:"hand"`
+	LegalPlays Deck `json:"legalPlays"`
+	LegalPasses Deck `json:"legalPasses"`
+	PassCount int `json:"passCount"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
 }
 
-func NewHeartsGame(deciders []Decider, maxPoints int) *HeartsGame {
-	players := map[string]*Player{}
-	playerOrder := []string{}
-	for _, d := range deciders {
-		players[d.GetName()] = &Player{Decider: d}
-		playerOrder = append(playerOrder, d.GetName())
+func NewHeartsGame(deciders []Decider, maxPoints int, seed int64, rules HeartsRules) *HeartsGame {
+	if len(rules.PassRotation) == 0 {
+		rules.PassRotation = DefaultPassRotation(len(deciders))
 	}
-
-	return &HeartsGame{
-		Players: players,
-		PlayerOrder: playerOrder,
-		PassDirection: PassLeft,
+	g := &HeartsGame{
+		Players: map[string]*Player{},
+		PassDirection: rules.PassDirection(0),
 		MaxPoints: maxPoints,
-		cancelListeners: map[int]chan bool{},
-		Cancelled: false,
+		Rules: rules,
 	}
+	g.Init(g, seed, HeartsPhases, DealingPhase, RandomDecision)
+	g.InitTricks(heartsTricks{g: g})
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &Player{Seat: s}
+	})
+	return g
 }
 
-func NewDefaultHeartsGame(name string) *HeartsGame {
-	deciders := []Decider{&RandomCPU{"Alice"}, &RandomCPU{"Bob"}, &RandomCPU{"Charlie"}, &CLIPlayer{name}}
-	rand.Shuffle(len(deciders), func(i, j int) { deciders[i], deciders[j] = deciders[j], deciders[i] })
+func NewDefaultHeartsGame(name string, seed int64) *HeartsGame {
+	deciders := []Decider{NewRandomCPU("Alice", seed-1), NewRandomCPU("Bob", seed-2), NewRandomCPU("Charlie", seed-3), &CLIPlayer{name}}
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(deciders), func(i, j int) { deciders[i], deciders[j] = deciders[j], deciders[i] })
 	
-	return NewHeartsGame(deciders, 100)
+	return NewHeartsGame(deciders, 100, seed, DefaultHeartsRules())
 }
 
 func (g *HeartsGame) GetDeciderInfo(decider Decider) interface{} {
 	playerInfo := map[string]PlayerInfo{}
 	for name, player := range g.Players {
+		timeLeft, timeBank := player.ClockInfo(&g.Table)
 		playerInfo[name] = PlayerInfo{
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
 			NumCards: len(player.Hand),
 			Score: player.Score,
-			RoundPoints: player.roundPoints,
+			RoundPoints: g.Rules.PointValue(player.taken),
+			PointCards: g.Rules.PointCards(player.taken),
 			Lead: name == g.Leader,
 		}
 	}
@@ -98,172 +126,264 @@ func (g *HeartsGame) GetDeciderInfo(decider Decider) interface{} {
 		PlayerOrder: g.PlayerOrder,
 		PassDirection: g.PassDirection,
 		CurrentTrick: g.CurrentTrick,
+		Tricks: g.Tricks,
 		HeartsBroken: g.HeartsBroken,
+		Trick: g.Trick,
+		OpeningCard: g.OpeningCard(),
 		MaxPoints: g.MaxPoints,
+		Rules: g.Rules,
 		Hand: g.Players[decider.GetName()].Hand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		LegalPasses: g.LegalPasses(decider.GetName()),
+		PassCount: CardsToPass,
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
 	}
 }
 
-func (g *HeartsGame) GetPlayer(i int) *Player {
-	return g.Players[g.PlayerOrder[i]]
+// String shows the table the way the command line player sees it
+func (hg *HeartsGameInfo) String() string {
+	var b strings.Builder
+	b.WriteString("Scores: ")
+	for _, name := range hg.PlayerOrder {
+		fmt.Fprintf(&b, "( %v: %v ) ", name, hg.PlayerInfo[name].Score)
+	}
+	fmt.Fprintf(&b, "\nCurrent Trick: %v\n", hg.CurrentTrick)
+	fmt.Fprintf(&b, "Hand: %v", hg.Hand.NumberedString())
+	return b.String()
 }
 
-func (g *HeartsGame) GetOrder(name string) int {
-	for i := 0; i < 4; i++ {
-		if g.PlayerOrder[i] == name {
-			return i
+// Prompt describes passing and playing as picking cards from the hand
+func (g *HeartsGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := Prompt{Question: q, Kind: CardsPrompt, Hand: g.Players[name].Hand.Copy()}
+	switch q {
+	case PassCardsQuestion:
+		p.Text = fmt.Sprintf("Pick %d cards to pass %v", CardsToPass, g.PassDirection)
+		p.Count = CardsToPass
+		p.Allowed = g.LegalPasses(name)
+	case PlayOnTrickQuestion:
+		p.Text = "Play a card"
+		p.Count = 1
+		p.Allowed = g.LegalPlays(name)
+	}
+	return p
+}
+
+func (g *HeartsGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case PassCardsQuestion:
+		return PassSelection{r.Cards}
+	case PlayOnTrickQuestion:
+		if len(r.Cards) != 1 {
+			return nil
 		}
+		return CardPlay{r.Cards[0]}
 	}
-	return -1
+	return nil
 }
 
+func (g *HeartsGame) Save() ([]byte, error) {
+	return g.Snapshot().JSON()
+}
+
+func (g *HeartsGame) GetPlayer(i int) *Player {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// Loser returns the first player, in seating order, at or over the max points
 func (g *HeartsGame) Loser() *Player {
-	for _, p := range g.Players {
-		if p.Score >= g.MaxPoints {
-			return p
+	for _, name := range g.PlayerOrder {
+		if g.Players[name].Score >= g.MaxPoints {
+			return g.Players[name]
 		}
 	}
 	return nil
 }
 
+func (g *HeartsGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Score
+	}
+	return scores
+}
+
 func (g *HeartsGame) GameOver() bool {
 	return g.Cancelled || g.Loser() != nil
 }
 
 func (g *HeartsGameInfo) Loser() string {
-	for name, p := range g.PlayerInfo {
-		if p.Score >= g.MaxPoints {
+	for _, name := range g.PlayerOrder {
+		if g.PlayerInfo[name].Score >= g.MaxPoints {
 			return name
 		}
 	}
 	return ""
 }
 
-func (g *HeartsGame) Start() chan bool {
-	completeChannel := make(chan bool)
-	go func() {
-		for !g.GameOver() {
-			if g.PlayRound() {
-				break
-			}
-		}
-		for _, p := range g.Players {
-			p.Decider.Notify(g)
-		}
-		completeChannel <- true
-	}()
-	
-	return completeChannel
+// Result ranks the players, lowest score first, as things stand
+func (g *HeartsGame) Result() GameResult {
+	return g.RankedResult(true)
 }
 
-func (g *HeartsGame) Cancel() {
-	g.Cancelled = true
-	for _, c := range g.cancelListeners {
-		c <- true
+func (g *HeartsGame) FirstTrick() bool {
+	return g.Trick == 0
+}
+
+// Deck returns the cards dealt each round, with enough cards removed that everyone gets
+// the same number
+func (g *HeartsGame) Deck() Deck {
+	removed := HeartsRemovalOrder[:len(NewDeck()) % g.NumPlayers()]
+	d := Deck{}
+	for _, c := range NewDeck() {
+		if removed.Index(c) == -1 {
+			d = append(d, c)
+		}
 	}
+	return d
 }
 
-func (g *HeartsGame) GetCancelListener() (int, chan bool) {
-	ret := make(chan bool)
-	g.cancelListeners[g.nextListenerID] = ret
-	g.nextListenerID++
-	return g.nextListenerID-1, ret
+func (g *HeartsGame) HandSize() int {
+	return len(g.Deck()) / g.NumPlayers()
 }
 
-func (g *HeartsGame) RemoveCancelListener(id int) {
-	delete(g.cancelListeners, id)
+// OpeningCard is the lowest club left in the deck, which has to lead the first trick
+func (g *HeartsGame) OpeningCard() Card {
+	d := g.Deck()
+	d.Sort()
+	return d[0]
 }
 
-func (g *HeartsGame) NotifyAll() {
-	for _, p := range g.Players {
-		p.Decider.Notify(g)
+func (g *HeartsGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
 	}
-}
 
-func (g *HeartsGame) FirstTrick() bool {
-	for _, p := range g.Players {
-		if len(p.Hand) == 13 {
+	if g.Phase == PassingPhase {
+		if cancelled := g.PassCards(); cancelled {
 			return true
 		}
 	}
+
+	if g.Phase == PlayingPhase {
+		for g.Trick < g.HandSize() {
+			if cancelled := g.PlayTrick(); cancelled {
+				return true
+			}
+		}
+		g.setPhase(ScoringPhase)
+	}
+
+	// Score the round
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.GetPlayer(i).taken = nil
+		g.GetPlayer(i).tricksWon = 0
+	}
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Leader = ""
+	g.Round++
+	if g.Loser() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
 	return false
 }
 
-func (g *HeartsGame) PlayRound() bool {
-	// Hand out the next set of cards
-	d := NewDeck()
-	d.Shuffle()
+// Deal hands out the next set of cards
+func (g *HeartsGame) Deal() {
+	g.PassDirection = g.Rules.PassDirection(g.Round)
+	g.HeartsBroken = false
+	g.StartTricks()
+	d := g.Deck()
+	d.Shuffle(g.RoundRand())
 	pi := 0
 	for !d.Empty() {
 		g.GetPlayer(pi).Hand = append(g.GetPlayer(pi).Hand, d.Deal())
-		pi = (pi + 1) % 4
+		pi = (pi + 1) % g.NumPlayers()
 	}
-	for i := 0; i < 4; i++ {
+	for i := 0; i < g.NumPlayers(); i++ {
 		g.GetPlayer(i).Hand.Sort()
 	}
+	g.setPhase(PassingPhase)
 
-	if cancelled := g.PassCards(); cancelled {
-		return true
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
 	}
-	
+}
 
-	// Play starts with the 2 of clubs. Hearts must be broken to play hearts leading. 13 tricks are played
-	var leader int
-	for i := 0; i < 4; i++ {
-		if g.GetPlayer(i).Hand.Contains(Two, Clubs) {
-			leader = i
-		}
-	}
-	var cancelled bool
-	for trick := 0; trick < 13; trick++ {
-		leader, cancelled = g.PlayTrick(leader)
-		if cancelled {
-			return true
+// startPlay has the lowest club, usually the 2, lead the first trick
+func (g *HeartsGame) startPlay() {
+	opening := g.OpeningCard()
+	for i := 0; i < g.NumPlayers(); i++ {
+		if g.GetPlayer(i).Hand.Contains(opening.Value, opening.Suit) {
+			g.Leader = g.PlayerOrder[i]
 		}
 	}
-	g.Leader = ""
+	g.setPhase(PlayingPhase)
+}
 
-	// Score the round
-	var shotTheMoon *Player
-	for _, p := range g.Players {
-		if p.roundPoints == 26 {
-			shotTheMoon = p
+// ScoreRound adds the points taken this round to each player's score, and returns how
+// much each score changed
+func (g *HeartsGame) ScoreRound() map[string]int {
+	// Shooting the moon takes every heart and the queen of spades, shooting the sun
+	// takes every trick
+	var shooter string
+	bonus := 0
+	for name, p := range g.Players {
+		if g.Rules.ShootTheSun && p.tricksWon == g.HandSize() {
+			shooter, bonus = name, SunPoints
+		} else if g.Rules.PenaltyPoints(p.taken) == MoonPoints {
+			shooter, bonus = name, MoonPoints
 		}
 	}
-	if shotTheMoon != nil {
-		for _, p := range g.Players {
-			if p != shotTheMoon {
-				p.Score += 26
-			}
-		}
-	} else {
-		for _, p := range g.Players {
-			p.Score += p.roundPoints
-		}
+	if shooter != "" {
+		g.emit(MoonShot{Round: g.Round, Player: shooter, Sun: bonus == SunPoints})
 	}
-	for i := 0; i < 4; i++ {
-		g.GetPlayer(i).roundPoints = 0
-	}
-	g.NotifyAll()
 
-	return false
+	roundPoints := map[string]int{}
+	for name, p := range g.Players {
+		points := g.Rules.BonusPoints(p.taken)
+		switch {
+		case shooter == "":
+			points += g.Rules.PenaltyPoints(p.taken)
+		case name == shooter && g.Rules.MoonScoring == MoonSubtractFromSelf:
+			points -= bonus
+		case name != shooter && g.Rules.MoonScoring == MoonAddToOthers:
+			points += bonus
+		}
+		p.Score += points
+		roundPoints[name] = points
+	}
+	return roundPoints
 }
 
 func (g *HeartsGame) PassCards() bool {
 	if g.PassDirection != NoPass {
 		// Depending on the pass direction, pass 3 cards
-		resultsChannels := []chan *Deck{make(chan *Deck), make(chan *Deck), make(chan *Deck), make(chan *Deck)}
-		for i := 0; i < 4; i++ {
+		resultsChannels := []chan *Deck{}
+		for i := 0; i < g.NumPlayers(); i++ {
+			resultsChannels = append(resultsChannels, make(chan *Deck, 1))
+		}
+		for i := 0; i < g.NumPlayers(); i++ {
 			go func(i int) {
 				cards, cancelled := g.GetPlayer(i).PassCards(g)
 				if cancelled {
 					resultsChannels[i] <- nil
+					return
 				}
 				resultsChannels[i] <- &cards
 			}(i)
 		}
-		passedCards := make([]Deck, 4)
-		for i := 0; i < 4; i++ {
+		passedCards := make([]Deck, g.NumPlayers())
+		for i := 0; i < g.NumPlayers(); i++ {
 			res := <-resultsChannels[i]
 			if res == nil {
 				return true
@@ -271,103 +391,117 @@ func (g *HeartsGame) PassCards() bool {
 			passedCards[i] = *res 
 		}
 
-		switch (g.PassDirection) {
-		case PassLeft:
-			for i := 0; i < 4; i++ {
-				g.GetPlayer(i).GetPassedCards(passedCards[(i+3)%4], g)
-			}
-			g.PassDirection = PassRight
-		case PassRight:
-			for i := 0; i < 4; i++ {
-				g.GetPlayer(i).GetPassedCards(passedCards[(i+1)%4], g)
-			}
-			g.PassDirection = PassAcross
-		case PassAcross:
-			for i := 0; i < 4; i++ {
-				g.GetPlayer(i).GetPassedCards(passedCards[(i+2)%4], g)
-			}
-			g.PassDirection = NoPass
+		// Hands only change once everyone has chosen, so nobody's hand changes while another
+		// player is still looking at the table
+		for i := 0; i < g.NumPlayers(); i++ {
+			g.GetPlayer(i).Hand = g.GetPlayer(i).Hand.Without(passedCards[i])
 		}
-
-		for i := 0; i < 4; i++ {
+		offset := g.PassOffset()
+		for i := 0; i < g.NumPlayers(); i++ {
+			g.GetPlayer((i + offset) % g.NumPlayers()).GetPassedCards(passedCards[i], g)
+		}
+		for i := 0; i < g.NumPlayers(); i++ {
 			g.GetPlayer(i).Hand.Sort()
 		}
+		g.startPlay()
+
+		for i := 0; i < g.NumPlayers(); i++ {
+			to := (i + offset) % g.NumPlayers()
+			g.emit(CardsPassed{
+				Round: g.Round,
+				Direction: g.PassDirection,
+				From: g.PlayerOrder[i],
+				To: g.PlayerOrder[to],
+				Cards: passedCards[i],
+			})
+		}
 	} else {
-		g.PassDirection = PassLeft
+		g.startPlay()
 	}
 	g.NotifyAll()
 
 	return false
 }
 
-func (g *HeartsGame) PlayTrick(leader int) (int, bool) {
-	g.Leader = g.GetPlayer(leader).Decider.GetName()
+// PassOffset is how many seats to the left the cards go this round. Across is half way
+// round the table, rounding down when there is an odd number of players
+func (g *HeartsGame) PassOffset() int {
+	switch g.PassDirection {
+	case PassLeft:
+		return 1
+	case PassRight:
+		return g.NumPlayers() - 1
+	case PassAcross:
+		return g.NumPlayers() / 2
+	}
+	return 0
+}
 
-	var highestTrump int
-	var highestValue int
-	for i := 0; i < 4; i++ {
-		currentPlayer := g.GetPlayer((i + leader) % 4)
-		card, cancelled := currentPlayer.PlayOnTrick(g)
-		if cancelled {
-			return 0, true
+// PlayTrick plays out the current trick, picking up after any cards already on it, and
+// leaves the winner to lead the next one
+func (g *HeartsGame) PlayTrick() bool {
+	play := func(name string) (Card, bool) {
+		p := g.Players[name]
+		return g.PlayFromHand(&g.Table, p.Seat, &p.Hand, g)
+	}
+	played := func(name string, card Card) {
+		breaks := !g.HeartsBroken && g.Rules.BreaksHearts(card)
+		if breaks {
+			g.HeartsBroken = true
 		}
-
-		g.CurrentTrick = append(g.CurrentTrick, card)
-		if card.Suit == *g.LeadSuit() && highestValue < card.ValueIndex() {
-			highestTrump = i
-			highestValue = card.ValueIndex()
+		g.emit(CardPlayed{Round: g.Round, Trick: g.Trick, Player: name, Card: card})
+		if breaks {
+			g.emit(HeartsBroken{Round: g.Round, Trick: g.Trick, Player: name})
 		}
 		g.NotifyAll()
 	}
+	trick := g.Trick
+	won, cancelled := g.PlayCards(SeatsFrom(g.PlayerOrder, g.Leader), play, played)
+	if cancelled {
+		return true
+	}
 
-	leader = (leader + highestTrump) % 4
-	
-	g.GetPlayer(leader).roundPoints += PointValue(g.CurrentTrick)
-	
-	g.CurrentTrick = Deck{}
+	winner := g.Players[won.Winner]
+	winner.taken = append(winner.taken, won.Cards...)
+	winner.tricksWon++
+	g.emit(TrickWon{
+		Round: g.Round,
+		Trick: trick,
+		Leader: won.Leader,
+		Winner: won.Winner,
+		Cards: won.Cards,
+		Points: won.Points,
+	})
 	g.NotifyAll()
 
-	return leader, false
+	return false
 }
 
 func (p *Player) GetAnswer(q Question, game *HeartsGame) (Answer, bool) {
-	ansChan := make(chan Answer)
-	go func() {
-		ansChan <- p.Decider.Decide(q, game)
-	}()
-	
-	var answer Answer
-	cancelID, cancelChan := game.GetCancelListener()
-	select {
-	case answer = <-ansChan:
-		game.RemoveCancelListener(cancelID)
-		return answer, false
-	case <-cancelChan:
-		return nil, true
-	}
+	return game.Ask(p.Seat, q, game)
 }
 
+// PassCards asks which cards to pass, but leaves them in the hand until everyone has
+// chosen
 func (p *Player) PassCards(game *HeartsGame) (Deck, bool) {
+	p.StartTurn(&game.Table)
+	defer p.EndTurn(&game.Table)
 	for {
 		answer, cancelled := p.GetAnswer(PassCardsQuestion, game)
 		if cancelled {
 			return Deck{}, true
 		}
 
-		indices, ok := answer.([]int)
-		for !ok || len(indices) != 3 {
-			p.Decider.ShowInfo("Could not understand answer")
+		indices, violation := ValidatePass(p.Hand, answer, CardsToPass)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
 			continue
 		}
-		
-		sort.Slice(indices, func(i, j int) bool {return indices[i] < indices[j]})
-
-		cards := Deck{p.Hand[indices[0]], p.Hand[indices[1]], p.Hand[indices[2]]}
-		res := append(p.Hand[:indices[0]], p.Hand[indices[0]+1:indices[1]]...)
-		res = append(res, p.Hand[indices[1]+1:indices[2]]...)
-		res = append(res, p.Hand[indices[2]+1:]...)
-		p.Hand = res
 
+		cards := Deck{}
+		for _, i := range indices {
+			cards = append(cards, p.Hand[i])
+		}
 		return cards, false
 	}
 }
@@ -376,83 +510,69 @@ func (p *Player) GetPassedCards(cards []Card, game *HeartsGame) {
 	p.Hand = append(p.Hand, cards...)
 }
 
-func (p *Player) PlayOnTrick(game *HeartsGame) (Card, bool) {
-	for {
-		answer, cancelled := p.GetAnswer(PlayOnTrickQuestion, game)
-		if cancelled {
-			return Card{}, true
-		}
+// heartsTricks are the rules of a hearts trick: nothing is trumps, the opening card leads
+// the first trick, hearts can't be led until they are broken, points can't be thrown on
+// the first trick unless the rules allow it, and tricks are worth their penalty cards
+type heartsTricks struct {
+	PlainTricks
+	g *HeartsGame
+}
 
-		index, ok := answer.(int)
-		if !ok {
-			p.Decider.ShowInfo("Could not understand answer")
-			continue
+func (r heartsTricks) CheckLead(hand Deck, card Card) *RuleViolation {
+	g := r.g
+	if opening := g.OpeningCard(); g.FirstTrick() && card != opening {
+		return &RuleViolation{
+			Code: MustLeadOpeningCardViolation,
+			Message: fmt.Sprintf("Must lead with the %v of %v", opening.Value, opening.Suit),
+			Card: &opening,
 		}
-
-		card := p.Hand[index]
-		if game.LeadSuit() == nil {
-			if game.FirstTrick() && (card.Suit != Clubs || card.Value != Two) {
-				p.Decider.ShowInfo("Must lead with the 2 of clubs")
-				continue
-			}
-			if card.Suit == Hearts && !game.HeartsBroken {
-				p.Decider.ShowInfo("Hearts not broken, lead with another suit")
-				continue
-			}
-
-			p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
-			return card, false
-		} else {
-			leadSuit := *game.LeadSuit()
-			isLeadSuit := leadSuit == card.Suit
-			if !isLeadSuit && p.HasSuit(leadSuit) {
-				p.Decider.ShowInfo("Must play the lead suit: " + string(leadSuit))
-				continue
-			}
-
-			playingHeart := card.Suit == Hearts || card.Suit == Spades && card.Value == Queen
-			hasNonHeartCard := p.HasSuit(Clubs) || p.HasSuit(Diamonds) || p.Hand.ContainsNonQueenSpade()
-			if game.FirstTrick() && playingHeart && hasNonHeartCard {
-				p.Decider.ShowInfo("Cannot play heart or QoS on the first trick unless you have no alternative")
-				continue
-			}
-			
-			if playingHeart {
-				game.HeartsBroken = true
-			}
-			p.Hand = append(p.Hand[:index], p.Hand[index+1:]...)
-			return card, false
+	}
+	onlyHearts := !hand.HasSuit(Clubs) && !hand.HasSuit(Diamonds) && !hand.HasSuit(Spades)
+	if g.Rules.MustBreakHearts && card.Suit == Hearts && !g.HeartsBroken && !onlyHearts {
+		return &RuleViolation{
+			Code: HeartsNotBrokenViolation,
+			Message: "Hearts not broken, lead with another suit",
 		}
 	}
+	return nil
 }
 
-func (g *HeartsGame) LeadSuit() *Suit {
-	if len(g.CurrentTrick) == 0 {
-		return nil
+func (r heartsTricks) CheckFollow(hand Deck, card Card) *RuleViolation {
+	g := r.g
+	hasNonPenaltyCard := hand.HasSuit(Clubs) || hand.HasSuit(Diamonds) || hand.ContainsNonQueenSpade()
+	if g.FirstTrick() && !g.Rules.FirstTrickBleeding && g.Rules.IsPenaltyCard(card) && hasNonPenaltyCard {
+		return &RuleViolation{
+			Code: NoPointsOnFirstTrickViolation,
+			Message: "Cannot play heart or QoS on the first trick unless you have no alternative",
+		}
 	}
-	return &g.CurrentTrick[0].Suit
+	return nil
 }
 
-func (p *Player) HasSuit(s Suit) bool {
-	for _, c := range p.Hand {
-		if c.Suit == s {
-			return true
-		}
-	}
-	return false
+func (r heartsTricks) TrickPoints(cards Deck) int {
+	return r.g.Rules.PointValue(cards)
 }
 
-func PointValue(d Deck) int {
-	points := 0
+// LegalPlays returns the cards the player may play, or nothing if they are not being
+// asked to play a card right now
+func (g *HeartsGame) LegalPlays(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok || p.Asking() != PlayOnTrickQuestion {
+		return Deck{}
+	}
+	return g.LegalCards(p.Hand)
+}
 
-	for _, c := range d {
-		if c.Suit == Hearts {
-			points += 1
-		}
-		if  c.Suit == Spades && c.Value == Queen {
-			points += 13
-		}
+// LegalPasses returns the cards the player may pass, or nothing if they are not being
+// asked to pass right now. Any card may be passed, CardsToPass of them at a time.
+func (g *HeartsGame) LegalPasses(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok || p.Asking() != PassCardsQuestion {
+		return Deck{}
 	}
+	return p.Hand.Copy()
+}
 
-	return points
-}
\ No newline at end of file
+func (p *Player) HasSuit(s Suit) bool {
+	return p.Hand.HasSuit(s)
+}
@@ -0,0 +1,363 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// heartsCPUs are random CPUs named a, b, c and on, seeded from the seed given
+func heartsCPUs(players int, seed int64) []Decider {
+	deciders := []Decider{}
+	for i := 0; i < players; i++ {
+		deciders = append(deciders, NewRandomCPU(string(rune('a' + i)), seed + int64(i)))
+	}
+	return deciders
+}
+
+// playHearts plays a game through, returning every event written out in order
+func playHearts(g *HeartsGame) []string {
+	events := []string{}
+	g.Subscribe(func(e Event) {
+		events = append(events, fmt.Sprintf("%T %+v", e, e))
+	})
+	<-g.Start()
+	return events
+}
+
+func TestHeartsSameSeed(t *testing.T) {
+	first := playHearts(NewHeartsGame(heartsCPUs(4, 10), 50, 1, DefaultHeartsRules()))
+	second := playHearts(NewHeartsGame(heartsCPUs(4, 10), 50, 1, DefaultHeartsRules()))
+	if len(first) != len(second) {
+		t.Fatalf("got %d events the first time and %d the second", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("event %d differs:\n%s\n%s", i, first[i], second[i])
+		}
+	}
+
+	// Another seed deals other hands
+	g := NewHeartsGame(heartsCPUs(4, 10), 50, 1, DefaultHeartsRules())
+	other := NewHeartsGame(heartsCPUs(4, 10), 50, 2, DefaultHeartsRules())
+	g.Deal()
+	other.Deal()
+	if g.Players["a"].Hand.String() == other.Players["a"].Hand.String() {
+		t.Errorf("seeds 1 and 2 both dealt a %v", g.Players["a"].Hand)
+	}
+}
+
+func TestHeartsReplay(t *testing.T) {
+	g := NewHeartsGame(heartsCPUs(4, 20), 50, 3, DefaultHeartsRules())
+	recorder := NewRecorder(g)
+	played := playHearts(g)
+	record := recorder.Record()
+
+	replayed := []string{}
+	replay, err := NewReplayer(record).Replay(func(e Event) {
+		replayed = append(replayed, fmt.Sprintf("%T %+v", e, e))
+	})
+	if err != nil {
+		t.Fatalf("replaying: %v", err)
+	}
+	if len(played) != len(replayed) {
+		t.Fatalf("got %d events played and %d replayed", len(played), len(replayed))
+	}
+	for i := range played {
+		if played[i] != replayed[i] {
+			t.Fatalf("event %d differs:\n%s\n%s", i, played[i], replayed[i])
+		}
+	}
+	if fmt.Sprint(replay.Scores()) != fmt.Sprint(g.Scores()) {
+		t.Errorf("replay ended on %v, want %v", replay.Scores(), g.Scores())
+	}
+
+	// Stepping in part way through a trick
+	state, err := NewReplayer(record).StateAt(Position{Round: 1, Trick: 4, Card: 2})
+	if err != nil {
+		t.Fatalf("stepping in: %v", err)
+	}
+	if state.Round != 1 || state.Trick != 4 || len(state.CurrentTrick) != 2 {
+		t.Errorf("stepped in at round %d trick %d with %v played, want round 1 trick 4 with 2 cards", state.Round, state.Trick, state.CurrentTrick)
+	}
+	if _, err := NewReplayer(record).StateAt(Position{Round: 100}); err != ErrPositionNotRecorded {
+		t.Errorf("stepping in after the end: got %v, want %v", err, ErrPositionNotRecorded)
+	}
+
+	// A record of someone playing a card they were never dealt does not replay
+	plays := []int{}
+	for i, m := range record.Moves {
+		if m.Type == PlayMove {
+			plays = append(plays, i)
+		}
+	}
+	tampered := record
+	tampered.Moves = append([]Move{}, record.Moves...)
+	tampered.Moves[plays[0]].Cards = record.Moves[plays[1]].Cards
+	if _, err := NewReplayer(tampered).Replay(nil); !errors.Is(err, ErrRecordMismatch) {
+		t.Errorf("replaying a card that was not held: got %v, want %v", err, ErrRecordMismatch)
+	}
+}
+
+// firstLegal passes the first three cards in the hand and plays the first card it can, so
+// a game played by it only depends on the deal
+func firstLegal(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	info := g.GetDeciderInfo(d).(*HeartsGameInfo)
+	if q == PassCardsQuestion {
+		return PassSelection{[]int{0, 1, 2}}
+	}
+	return CardPlay{info.Hand.Index(info.LegalPlays[0])}
+}
+
+func TestHeartsScoreRound(t *testing.T) {
+	hearts := Deck{}
+	for _, c := range NewDeck() {
+		if c.Suit == Hearts {
+			hearts = append(hearts, c)
+		}
+	}
+	moon := append(cards("QS"), hearts...)
+
+	tests := []struct {
+		name string
+		rules func(r *HeartsRules)
+		taken map[string]Deck
+		tricks map[string]int
+		want map[string]int
+	}{
+		{
+			"penalty cards", func(r *HeartsRules) {},
+			map[string]Deck{"a": cards("QS 2H 3H"), "b": cards("4H JD")}, nil,
+			map[string]int{"a": 15, "b": 1, "c": 0, "d": 0},
+		},
+		{
+			"jack of diamonds", func(r *HeartsRules) { r.JackOfDiamonds = true },
+			map[string]Deck{"a": cards("QS 2H 3H"), "b": cards("4H JD")}, nil,
+			map[string]int{"a": 15, "b": -9, "c": 0, "d": 0},
+		},
+		{
+			"moon adds to the others", func(r *HeartsRules) {},
+			map[string]Deck{"a": moon}, map[string]int{"a": 10},
+			map[string]int{"a": 0, "b": 26, "c": 26, "d": 26},
+		},
+		{
+			"moon takes off the shooter", func(r *HeartsRules) { r.MoonScoring = MoonSubtractFromSelf },
+			map[string]Deck{"a": moon}, map[string]int{"a": 10},
+			map[string]int{"a": -26, "b": 0, "c": 0, "d": 0},
+		},
+		// The jack still counts for whoever took it when the moon is shot
+		{
+			"moon with the jack of diamonds", func(r *HeartsRules) { r.JackOfDiamonds = true },
+			map[string]Deck{"a": moon, "b": cards("JD")}, map[string]int{"a": 10, "b": 1},
+			map[string]int{"a": 0, "b": 16, "c": 26, "d": 26},
+		},
+		{
+			"sun", func(r *HeartsRules) { r.ShootTheSun = true },
+			map[string]Deck{"a": NewDeck()}, map[string]int{"a": 13},
+			map[string]int{"a": 0, "b": 52, "c": 52, "d": 52},
+		},
+		{
+			"every trick is only a moon without the sun", func(r *HeartsRules) {},
+			map[string]Deck{"a": NewDeck()}, map[string]int{"a": 13},
+			map[string]int{"a": 0, "b": 26, "c": 26, "d": 26},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultHeartsRules()
+			tt.rules(&rules)
+			g := NewHeartsGame(heartsCPUs(4, 1), 100, 1, rules)
+			for name, taken := range tt.taken {
+				g.Players[name].taken = taken
+				g.Players[name].tricksWon = tt.tricks[name]
+			}
+			points := g.ScoreRound()
+			if fmt.Sprint(points) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", points, tt.want)
+			}
+			if fmt.Sprint(g.Scores()) != fmt.Sprint(tt.want) {
+				t.Errorf("scores are %v, want %v", g.Scores(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHeartsCheckPlay(t *testing.T) {
+	tests := []struct {
+		name string
+		rules func(r *HeartsRules)
+		trick int
+		broken bool
+		played string
+		hand string
+		card string
+		want ViolationCode
+	}{
+		{"opening card leads", func(r *HeartsRules) {}, 0, false, "", "2C 5D", "5D", MustLeadOpeningCardViolation},
+		{"leading hearts", func(r *HeartsRules) {}, 1, false, "", "5H 5D", "5H", HeartsNotBrokenViolation},
+		{"leading broken hearts", func(r *HeartsRules) {}, 1, true, "", "5H 5D", "5H", ""},
+		{"leading nothing but hearts", func(r *HeartsRules) {}, 1, false, "", "5H 6H", "5H", ""},
+		{"hearts need not be broken", func(r *HeartsRules) { r.MustBreakHearts = false }, 1, false, "", "5H 5D", "5H", ""},
+		{"queen on the first trick", func(r *HeartsRules) {}, 0, false, "2C", "QS 5D", "QS", NoPointsOnFirstTrickViolation},
+		{"heart on the first trick", func(r *HeartsRules) {}, 0, false, "2C", "5H 5D", "5H", NoPointsOnFirstTrickViolation},
+		{"only points on the first trick", func(r *HeartsRules) {}, 0, false, "2C", "QS 5H", "QS", ""},
+		{"first trick bleeding", func(r *HeartsRules) { r.FirstTrickBleeding = true }, 0, false, "2C", "QS 5D", "QS", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultHeartsRules()
+			tt.rules(&rules)
+			g := NewHeartsGame(heartsCPUs(4, 1), 100, 1, rules)
+			g.Trick, g.HeartsBroken, g.CurrentTrick = tt.trick, tt.broken, cards(tt.played)
+			var got ViolationCode
+			if violation := g.CheckPlay(cards(tt.hand), cards(tt.card)[0]); violation != nil {
+				got = violation.Code
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeartsQueenBreaksHearts(t *testing.T) {
+	for _, breaks := range []bool{true, false} {
+		rules := DefaultHeartsRules()
+		rules.QueenBreaksHearts = breaks
+		deciders := []Decider{}
+		for _, name := range []string{"a", "b", "c", "d"} {
+			deciders = append(deciders, NewStrategyCPU(name, 1, firstLegal))
+		}
+		g := NewHeartsGame(deciders, 100, 1, rules)
+		g.Trick, g.Leader = 1, "a"
+		for name, hand := range map[string]string{"a": "QS", "b": "2S 5C", "c": "3S 6C", "d": "4S 7C"} {
+			g.Players[name].Hand = cards(hand)
+		}
+		if cancelled := g.PlayTrick(); cancelled {
+			t.Fatalf("the trick was cancelled")
+		}
+		if g.HeartsBroken != breaks {
+			t.Errorf("with the queen breaking hearts %v, hearts broken is %v", breaks, g.HeartsBroken)
+		}
+	}
+}
+
+func TestHeartsPlayerCounts(t *testing.T) {
+	tests := []struct {
+		players int
+		removed string
+		handSize int
+		opening string
+	}{
+		{3, "2D", 17, "2C"},
+		{4, "", 13, "2C"},
+		{5, "2D 2C", 10, "3C"},
+		{6, "2D 2C 3D 3C", 8, "4C"},
+	}
+	for _, tt := range tests {
+		g := NewHeartsGame(heartsCPUs(tt.players, 1), 100, 1, DefaultHeartsRules())
+		deck := g.Deck()
+		for _, c := range cards(tt.removed) {
+			if deck.Contains(c.Value, c.Suit) {
+				t.Errorf("%d players: the %v is left in", tt.players, c)
+			}
+		}
+		if len(deck) != len(NewDeck()) - len(cards(tt.removed)) || g.HandSize() != tt.handSize {
+			t.Errorf("%d players: got %d cards dealt %d each, want %d each", tt.players, len(deck), g.HandSize(), tt.handSize)
+		}
+		if opening := g.OpeningCard(); opening != cards(tt.opening)[0] {
+			t.Errorf("%d players: got %v leading, want %s", tt.players, opening, tt.opening)
+		}
+
+		g.Deal()
+		for _, name := range g.PlayerOrder {
+			if len(g.Players[name].Hand) != tt.handSize {
+				t.Errorf("%d players: %s was dealt %d cards", tt.players, name, len(g.Players[name].Hand))
+			}
+		}
+		g.startPlay()
+		if opening := g.OpeningCard(); !g.Players[g.Leader].Hand.Contains(opening.Value, opening.Suit) {
+			t.Errorf("%d players: %s leads without the %v", tt.players, g.Leader, opening)
+		}
+	}
+}
+
+func TestHeartsPassAcross(t *testing.T) {
+	for players := MinHeartsPlayers; players <= MaxHeartsPlayers; players++ {
+		for _, d := range DefaultPassRotation(players) {
+			if d == PassAcross && players % 2 != 0 {
+				t.Errorf("%d players pass across by default", players)
+			}
+		}
+		rules := DefaultHeartsRules()
+		rules.PassRotation = []PassDirection{PassLeft, PassAcross}
+		if err := rules.Validate(players); (err == nil) != (players % 2 == 0) {
+			t.Errorf("%d players passing across: got %v", players, err)
+		}
+		_, err := HeartsType.NewGame(heartsCPUs(players, 1), 1, Options{"pass_rotation": []string{"across"}})
+		if (err == nil) != (players % 2 == 0) {
+			t.Errorf("%d players set up to pass across: got %v", players, err)
+		}
+	}
+
+	// Across is half way round the table
+	g := NewHeartsGame(heartsCPUs(6, 1), 100, 1, DefaultHeartsRules())
+	g.PassDirection = PassAcross
+	if offset := g.PassOffset(); offset != 3 {
+		t.Errorf("6 players pass across %d seats, want 3", offset)
+	}
+}
+
+// strictCPU fails the test if it is ever told a move it made was not allowed
+type strictCPU struct {
+	*RandomCPU
+	t *testing.T
+}
+
+func (c strictCPU) ShowInfo(info string) {
+	c.t.Errorf("%s was told: %s", c.ID, info)
+}
+
+func TestHeartsLegalMoves(t *testing.T) {
+	// Plays at random from the legal plays, checking them against the table as it goes
+	strategy := func(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+		info := g.GetDeciderInfo(d).(*HeartsGameInfo)
+		if q == PassCardsQuestion {
+			if len(info.LegalPlays) != 0 || info.LegalPasses.String() != info.Hand.String() {
+				t.Errorf("%s passing from %v can play %v and pass %v", info.Name, info.Hand, info.LegalPlays, info.LegalPasses)
+			}
+			return RandomDecision(r, d, q, g)
+		}
+
+		legal := info.LegalPlays
+		if len(info.LegalPasses) != 0 || len(legal) == 0 {
+			t.Errorf("%s playing from %v can play %v and pass %v", info.Name, info.Hand, legal, info.LegalPasses)
+			return RandomDecision(r, d, q, g)
+		}
+		if info.Trick == 0 && len(info.CurrentTrick) == 0 && legal.String() != (Deck{info.OpeningCard}).String() {
+			t.Errorf("%s can open with %v", info.Name, legal)
+		}
+		if len(info.CurrentTrick) > 0 && info.Hand.HasSuit(info.CurrentTrick[0].Suit) {
+			for _, c := range legal {
+				if c.Suit != info.CurrentTrick[0].Suit {
+					t.Errorf("%s can play the %v on %v", info.Name, c, info.CurrentTrick)
+				}
+			}
+		}
+		return CardPlay{info.Hand.Index(legal[r.Intn(len(legal))])}
+	}
+	deciders := []Decider{}
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		deciders = append(deciders, strictCPU{NewStrategyCPU(name, int64(i), strategy), t})
+	}
+	g := NewHeartsGame(deciders, 100, 1, DefaultHeartsRules())
+	<-g.Start()
+
+	// Nobody is asked anything once the game is over
+	for _, name := range append(g.PlayerOrder, "nobody") {
+		if plays, passes := g.LegalPlays(name), g.LegalPasses(name); len(plays) != 0 || len(passes) != 0 {
+			t.Errorf("%s can play %v and pass %v after the game", name, plays, passes)
+		}
+	}
+}
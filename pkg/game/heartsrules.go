@@ -0,0 +1,206 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type MoonScoring string
+const (
+	MoonAddToOthers = MoonScoring("add_to_others")
+	MoonSubtractFromSelf = MoonScoring("subtract_from_self")
+)
+
+const (
+	MoonPoints = 26
+	SunPoints = 52
+	JackOfDiamondsPoints = -10
+)
+
+type HeartsRules struct {
+	// The jack of diamonds takes 10 points off whoever wins it (Omnibus Hearts)
+	JackOfDiamonds bool `json:"jack_of_diamonds"`
+	// Whether shooting the moon adds to everyone else's score or takes off your own
+	MoonScoring MoonScoring `json:"moon_scoring"`
+	// Taking every trick of a round scores double a moon
+	ShootTheSun bool `json:"shoot_the_sun"`
+	// Hearts and the queen of spades may be played on the first trick
+	FirstTrickBleeding bool `json:"first_trick_bleeding"`
+	// Hearts can not be led until a heart has been played, unless nothing else is left
+	MustBreakHearts bool `json:"must_break_hearts"`
+	// Playing the queen of spades breaks hearts as well
+	QueenBreaksHearts bool `json:"queen_breaks_hearts"`
+	// The pass direction of each round, repeating once the end is reached. Left empty, the
+	// usual rotation for the number of players is used
+	PassRotation []PassDirection `json:"pass_rotation"`
+}
+
+func DefaultHeartsRules() HeartsRules {
+	return HeartsRules{
+		JackOfDiamonds: false,
+		MoonScoring: MoonAddToOthers,
+		ShootTheSun: false,
+		FirstTrickBleeding: false,
+		MustBreakHearts: true,
+		QueenBreaksHearts: true,
+		PassRotation: []PassDirection{},
+	}
+}
+
+// DefaultPassRotation passes across only when there is someone sitting directly across
+func DefaultPassRotation(players int) []PassDirection {
+	if players % 2 == 0 {
+		return []PassDirection{PassLeft, PassRight, PassAcross, NoPass}
+	}
+	return []PassDirection{PassLeft, PassRight, NoPass}
+}
+
+// Validate checks the rules can be played by the number of players, which is 0 while the
+// table is still filling up
+func (r HeartsRules) Validate(players int) error {
+	if r.MoonScoring != MoonAddToOthers && r.MoonScoring != MoonSubtractFromSelf {
+		return fmt.Errorf("[%v] is not a way to score the moon", r.MoonScoring)
+	}
+	for _, d := range r.PassRotation {
+		if d != PassLeft && d != PassRight && d != PassAcross && d != NoPass {
+			return fmt.Errorf("[%v] is not a pass direction", d)
+		}
+		if d == PassAcross && players % 2 != 0 {
+			return fmt.Errorf("Can only pass across with an even number of players")
+		}
+	}
+	return nil
+}
+
+func (r HeartsRules) PassDirection(round int) PassDirection {
+	return r.PassRotation[round % len(r.PassRotation)]
+}
+
+// PointValue is what the cards are worth to whoever took them, before any moon is shot
+func (r HeartsRules) PointValue(d Deck) int {
+	return r.PenaltyPoints(d) + r.BonusPoints(d)
+}
+
+// PenaltyPoints counts the hearts and the queen of spades, the cards that make up a moon
+func (r HeartsRules) PenaltyPoints(d Deck) int {
+	points := 0
+
+	for _, c := range d {
+		if c.Suit == Hearts {
+			points += 1
+		}
+		if c.Suit == Spades && c.Value == Queen {
+			points += 13
+		}
+	}
+
+	return points
+}
+
+func (r HeartsRules) BonusPoints(d Deck) int {
+	if r.JackOfDiamonds && d.Contains(Jack, Diamonds) {
+		return JackOfDiamondsPoints
+	}
+	return 0
+}
+
+// PointCards picks out the cards that are worth points, either way
+func (r HeartsRules) PointCards(d Deck) Deck {
+	cards := Deck{}
+	for _, c := range d {
+		if r.IsPenaltyCard(c) || r.JackOfDiamonds && c.Suit == Diamonds && c.Value == Jack {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+func (r HeartsRules) IsPenaltyCard(c Card) bool {
+	return c.Suit == Hearts || c.Suit == Spades && c.Value == Queen
+}
+
+func (r HeartsRules) BreaksHearts(c Card) bool {
+	return c.Suit == Hearts || r.QueenBreaksHearts && c.Suit == Spades && c.Value == Queen
+}
+
+// HeartsOptions are the settings a lobby host can change, the rules sitting alongside the
+// max points
+type HeartsOptions struct {
+	MaxPoints int `json:"max_points"`
+	HeartsRules
+}
+
+var passChoices = []Choice{
+	{string(PassLeft), "Left"},
+	{string(PassRight), "Right"},
+	{string(PassAcross), "Across"},
+	{string(NoPass), "No pass"},
+}
+
+var HeartsType = &GameType{
+	Name: "hearts",
+	Title: "Hearts",
+	MinPlayers: MinHeartsPlayers,
+	MaxPlayers: MaxHeartsPlayers,
+	Options: []Option{
+		{Key: "max_points", Label: "Max Points", Type: NumberOption, Default: 100, Min: 1},
+		{Key: "jack_of_diamonds", Label: "Jack of diamonds takes off 10 points", Type: BoolOption, Default: false},
+		{Key: "shoot_the_sun", Label: "Shooting the sun (every trick) scores double", Type: BoolOption, Default: false},
+		{Key: "first_trick_bleeding", Label: "Points allowed on the first trick", Type: BoolOption, Default: false},
+		{Key: "must_break_hearts", Label: "Hearts must be broken before leading them", Type: BoolOption, Default: true},
+		{Key: "queen_breaks_hearts", Label: "Queen of spades breaks hearts", Type: BoolOption, Default: true},
+		{Key: "moon_scoring", Label: "Shooting the moon", Type: ChoiceOption, Default: string(MoonAddToOthers), Choices: []Choice{
+			{string(MoonAddToOthers), "Adds 26 to everyone else"},
+			{string(MoonSubtractFromSelf), "Takes 26 off the shooter"},
+		}},
+		// Left empty, the usual rotation for the number of players is used
+		{Key: "pass_rotation", Label: "Pass rotation", Type: ListOption, Default: []string{}, Choices: passChoices},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o HeartsOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		if err := o.Validate(len(deciders)); err != nil {
+			return nil, err
+		}
+		return NewHeartsGame(deciders, o.MaxPoints, seed, o.HeartsRules), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreHeartsGame(s, deciders)
+	},
+	CPU: RandomDecision,
+	Validate: func(options Options) error {
+		var o HeartsOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate(0)
+	},
+	Record: func(g Game, saved []byte) (Recording, error) {
+		hg, _ := g.(*HeartsGame)
+		if saved == nil {
+			return NewRecorder(hg), nil
+		}
+		var record GameRecord
+		if err := json.Unmarshal(saved, &record); err != nil {
+			return nil, err
+		}
+		return ResumeRecorder(hg, record), nil
+	},
+	Replay: func(record []byte) (GameReplay, error) {
+		var r GameRecord
+		if err := json.Unmarshal(record, &r); err != nil {
+			return nil, err
+		}
+		return NewReplayer(r), nil
+	},
+}
+
+func init() {
+	Register(HeartsType)
+}
@@ -0,0 +1,828 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+const (
+	HoldemMinPlayers = 2
+	HoldemMaxPlayers = 10
+	HoleCards = 2
+)
+
+const (
+	BetQuestion = Question("bet")
+	RaiseQuestion = Question("raise")
+)
+
+// Options offered when it is a player's turn to bet
+const (
+	FoldOption = "fold"
+	CheckOption = "check"
+	CallOption = "call"
+	RaiseOption = "raise"
+	AllInOption = "all in"
+)
+
+// Hands are dealt, bet on over four streets, and won at showdown, or as soon as everyone
+// else folds
+var HoldemPhases = PhaseTransitions{
+	DealingPhase: {PreflopPhase},
+	PreflopPhase: {FlopPhase, ScoringPhase},
+	FlopPhase: {TurnPhase, ScoringPhase},
+	TurnPhase: {RiverPhase, ScoringPhase},
+	RiverPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type HoldemPlayer struct {
+	*Seat
+	Hand Deck
+	Chips int
+	// What the player has put in on this street, and in the whole hand
+	Bet int
+	Committed int
+	// Chips at the start of the hand, to score the hand by
+	StartChips int
+	Folded bool
+	// Whether the player has acted since the betting was last opened by a full raise
+	Acted bool
+	// Players with no chips left are out, and BustedIn is the hand they went out in
+	Out bool
+	BustedIn int
+}
+
+type HoldemGame struct {
+	Players map[string]*HoldemPlayer
+	Options HoldemOptions
+	Button int
+	Board Deck
+	Stock Deck
+	// The bet to match on this street, and the least a raise can add to it
+	CurrentBet int
+	MinRaise int
+	// The player whose turn it is to bet
+	Turn string
+	// Hands are shown at the end of a hand that went to showdown, until the next deal
+	Showdown bool
+	Table
+}
+
+// Pot is an amount of chips that the eligible players can win. Players who go all in can
+// only win side pots they put chips into.
+type Pot struct {
+	Amount int `json:"amount"`
+	Eligible []string `json:"eligible"`
+}
+
+type HoldemPlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Bet int `json:"bet"`
+	Folded bool `json:"folded"`
+	AllIn bool `json:"allIn"`
+	Out bool `json:"out"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	// Hands are only shown at showdown
+	Hand Deck `json:"hand,omitempty"`
+	HandName string `json:"handName,omitempty"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type HoldemGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]HoldemPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Board Deck `json:"board"`
+	Pot int `json:"pot"`
+	CurrentBet int `json:"currentBet"`
+	SmallBlind int `json:"smallBlind"`
+	BigBlind int `json:"bigBlind"`
+	Hand Deck `json:"hand"`
+	HandName string `json:"handName,omitempty"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewHoldemGame(deciders []Decider, seed int64, options HoldemOptions) *HoldemGame {
+	g := &HoldemGame{
+		Players: map[string]*HoldemPlayer{},
+		Options: options,
+	}
+	// Running out of time checks if it can, and folds if it can't
+	g.Init(g, seed, HoldemPhases, DealingPhase, HoldemTimeout)
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &HoldemPlayer{Seat: s, Chips: options.StartingChips}
+	})
+	return g
+}
+
+func (g *HoldemGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]HoldemPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		info := HoldemPlayerInfo{
+			NumCards: len(p.Hand),
+			Score: p.Chips,
+			Bet: p.Bet,
+			Folded: p.Folded,
+			AllIn: p.AllIn(),
+			Out: p.Out,
+			Lead: name == g.Turn,
+			Dealer: name == g.PlayerOrder[g.Button],
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+		if g.Showdown && !p.Folded && !p.Out {
+			info.Hand = p.Hand
+			info.HandName = EvaluateHand(append(p.Hand.Copy(), g.Board...)).String()
+		}
+		playerInfo[name] = info
+	}
+
+	p := g.Players[decider.GetName()]
+	var handName string
+	if len(p.Hand) + len(g.Board) >= 5 {
+		handName = EvaluateHand(append(p.Hand.Copy(), g.Board...)).String()
+	}
+	small, big := g.Blinds()
+
+	return &HoldemGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Board: g.Board,
+		Pot: g.PotTotal(),
+		CurrentBet: g.CurrentBet,
+		SmallBlind: small,
+		BigBlind: big,
+		Hand: p.Hand,
+		HandName: handName,
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (hg *HoldemGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range hg.PlayerOrder {
+		info := hg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v", name, info.Score)
+		switch {
+		case info.Out:
+			b.WriteString(", out")
+		case info.Folded:
+			b.WriteString(", folded")
+		case info.Bet > 0:
+			fmt.Fprintf(&b, ", bet %v", info.Bet)
+		}
+		if info.Hand != nil {
+			fmt.Fprintf(&b, ", %v %v", info.Hand, info.HandName)
+		}
+		b.WriteString(" ) ")
+	}
+	fmt.Fprintf(&b, "\nBlinds: %v/%v Pot: %v", hg.SmallBlind, hg.BigBlind, hg.Pot)
+	fmt.Fprintf(&b, "\nBoard: %v\n", hg.Board)
+	fmt.Fprintf(&b, "Hand: %v %v", hg.Hand, hg.HandName)
+	return b.String()
+}
+
+// AllIn is true for a player still in the hand with nothing left to bet
+func (p *HoldemPlayer) AllIn() bool {
+	return p.Chips == 0 && !p.Folded && !p.Out
+}
+
+// canAct is true for a player who can still bet in this hand
+func (p *HoldemPlayer) canAct() bool {
+	return !p.Folded && !p.Out && p.Chips > 0
+}
+
+// BetOptions lists what the player can do facing the current bet
+func (g *HoldemGame) BetOptions(name string) []string {
+	p := g.Players[name]
+	toCall := g.CurrentBet - p.Bet
+	options := []string{}
+	if toCall > 0 {
+		options = append(options, FoldOption)
+		if p.Chips > toCall {
+			options = append(options, CallOption)
+		}
+	} else {
+		options = append(options, CheckOption)
+	}
+	// Betting is only reopened to players who have already acted by a full raise
+	if !p.Acted && p.Chips + p.Bet > g.CurrentBet + g.MinRaise {
+		options = append(options, RaiseOption)
+	}
+	if !p.Acted || p.Chips <= toCall {
+		options = append(options, AllInOption)
+	}
+	return options
+}
+
+func (g *HoldemGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case BetQuestion:
+		text := fmt.Sprintf("You have %d chips, the pot is %d. ", p.Chips, g.PotTotal())
+		if toCall := g.CurrentBet - p.Bet; toCall > 0 {
+			text += fmt.Sprintf("Call %d, ", toCall)
+		}
+		text += fmt.Sprintf("raise to at least %d, or go all in?", g.CurrentBet + g.MinRaise)
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: text,
+			Hand: p.Hand.Copy(),
+			Options: g.BetOptions(name),
+		}
+	case RaiseQuestion:
+		return Prompt{
+			Question: q,
+			Kind: NumberPrompt,
+			Text: "Raise your bet to",
+			Hand: p.Hand.Copy(),
+			Min: g.CurrentBet + g.MinRaise,
+			Max: p.Bet + p.Chips,
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *HoldemGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case BetQuestion:
+		return Wager{Action: r.Option}
+	case RaiseQuestion:
+		return Wager{Action: RaiseOption, To: r.Number}
+	}
+	return nil
+}
+
+func (g *HoldemGame) GetPlayer(i int) *HoldemPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// nextSeat returns the next seat to the left of the seat with a player in it who is not
+// out, or the seat itself if nobody else is left
+func (g *HoldemGame) nextSeat(seat int) int {
+	for i := 1; i <= g.NumPlayers(); i++ {
+		next := (seat + i) % g.NumPlayers()
+		if !g.GetPlayer(next).Out {
+			return next
+		}
+	}
+	return seat
+}
+
+// Blinds returns the small and big blinds for this hand, which double every BlindLevelHands
+// hands if that is set
+func (g *HoldemGame) Blinds() (int, int) {
+	small, big := g.Options.SmallBlind, g.Options.BigBlind
+	if g.Options.BlindLevelHands > 0 {
+		for i := 0; i < g.Round / g.Options.BlindLevelHands && big < g.Options.StartingChips; i++ {
+			small, big = small * 2, big * 2
+		}
+	}
+	return small, big
+}
+
+// PotTotal is every chip bet in this hand
+func (g *HoldemGame) PotTotal() int {
+	total := 0
+	for _, p := range g.Players {
+		total += p.Committed
+	}
+	return total
+}
+
+// Pots splits the chips bet in the hand into the main pot and side pots, smallest first.
+// Each is made up to the next level a player went all in at, and can be won by the
+// players still in who put that much in.
+func (g *HoldemGame) Pots() []Pot {
+	levels := []int{}
+	for _, p := range g.Players {
+		if !p.Folded && !p.Out && p.Committed > 0 {
+			levels = append(levels, p.Committed)
+		}
+	}
+	sort.Ints(levels)
+
+	pots := []Pot{}
+	prev := 0
+	for _, level := range levels {
+		if level == prev {
+			continue
+		}
+		pot := Pot{Eligible: []string{}}
+		for i := 1; i <= g.NumPlayers(); i++ {
+			p := g.GetPlayer((g.Button + i) % g.NumPlayers())
+			pot.Amount += min(p.Committed, level) - min(p.Committed, prev)
+			if !p.Folded && !p.Out && p.Committed >= level {
+				pot.Eligible = append(pot.Eligible, p.GetName())
+			}
+		}
+		pots = append(pots, pot)
+		prev = level
+	}
+	// Anything put in by folded players beyond the biggest bet still in goes in the last pot
+	for _, p := range g.Players {
+		if extra := p.Committed - prev; extra > 0 && len(pots) > 0 {
+			pots[len(pots) - 1].Amount += extra
+		}
+	}
+	return pots
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// inHand lists the players who have not folded, from the left of the button
+func (g *HoldemGame) inHand() []string {
+	players := []string{}
+	for i := 1; i <= g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Button + i) % g.NumPlayers())
+		if !p.Folded && !p.Out {
+			players = append(players, p.GetName())
+		}
+	}
+	return players
+}
+
+// Winner returns the last player with chips, or nil while the game goes on
+func (g *HoldemGame) Winner() *HoldemPlayer {
+	var winner *HoldemPlayer
+	for _, p := range g.Players {
+		if p.Out {
+			continue
+		}
+		if winner != nil {
+			return nil
+		}
+		winner = p
+	}
+	return winner
+}
+
+func (g *HoldemGame) GameOver() bool {
+	return g.Cancelled || g.Winner() != nil
+}
+
+// Scores gives every player their chips
+func (g *HoldemGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Chips
+	}
+	return scores
+}
+
+// Result ranks the players by chips, and those who went out by how long they lasted
+func (g *HoldemGame) Result() GameResult {
+	players := append([]string{}, g.PlayerOrder...)
+	better := func(a *HoldemPlayer, b *HoldemPlayer) bool {
+		if a.Chips != b.Chips {
+			return a.Chips > b.Chips
+		}
+		return a.Out && b.Out && a.BustedIn > b.BustedIn
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		return better(g.Players[players[i]], g.Players[players[j]])
+	})
+	placements := []Placement{}
+	for i, name := range players {
+		place := i + 1
+		if i > 0 && !better(g.Players[players[i-1]], g.Players[name]) {
+			place = placements[i-1].Place
+		}
+		placements = append(placements, Placement{Place: place, Player: name, Score: g.Players[name].Chips})
+	}
+	return GameResult{
+		Placements: placements,
+		ScoreSheet: g.ScoreSheet,
+		Reason: g.EndReason(),
+	}
+}
+
+func (g *HoldemGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	for g.Phase != ScoringPhase {
+		if cancelled := g.Betting(); cancelled {
+			return true
+		}
+		g.NextStreet()
+	}
+
+	// Everything is settled before any of it is announced, so a game saved on hearing
+	// about it carries on from the next hand
+	round := g.Round
+	roundPoints, won := g.AwardPots()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	for _, p := range g.Players {
+		if p.Chips == 0 && !p.Out {
+			p.Out, p.BustedIn = true, round
+		}
+	}
+	g.Turn = ""
+	g.Round++
+	if g.Winner() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	for _, e := range won {
+		g.emit(e)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal moves the button on, posts the blinds, and deals everyone still in two cards
+func (g *HoldemGame) Deal() {
+	if g.Round > 0 {
+		g.Button = g.nextSeat(g.Button)
+	}
+	g.Board = Deck{}
+	g.Showdown = false
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.Bet, p.Committed = 0, 0
+		p.StartChips = p.Chips
+		p.Folded, p.Acted = false, false
+	}
+	g.Stock = NewDeck()
+	g.Stock.Shuffle(g.RoundRand())
+	for i := 0; i < HoleCards; i++ {
+		for seat := g.nextSeat(g.Button); ; seat = g.nextSeat(seat) {
+			p := g.GetPlayer(seat)
+			p.Hand = append(p.Hand, g.Stock.Deal())
+			if seat == g.Button {
+				break
+			}
+		}
+	}
+
+	// Heads up, the button posts the small blind
+	small, big := g.Blinds()
+	smallSeat := g.nextSeat(g.Button)
+	if g.nextSeat(smallSeat) == g.Button {
+		smallSeat = g.Button
+	}
+	bigSeat := g.nextSeat(smallSeat)
+	g.putIn(g.GetPlayer(smallSeat), small)
+	g.putIn(g.GetPlayer(bigSeat), big)
+	g.CurrentBet, g.MinRaise = big, big
+	g.Turn = g.PlayerOrder[g.nextSeat(bigSeat)]
+	g.setPhase(PreflopPhase)
+
+	for _, name := range g.PlayerOrder {
+		if p := g.Players[name]; !p.Out {
+			g.emit(HandDealt{Round: g.Round, Player: name, Hand: p.Hand.Copy()})
+		}
+	}
+	g.NotifyAll()
+}
+
+// putIn moves chips from the player's stack into their bet, as many as they have left
+func (g *HoldemGame) putIn(p *HoldemPlayer, chips int) {
+	chips = min(chips, p.Chips)
+	p.Chips -= chips
+	p.Bet += chips
+	p.Committed += chips
+}
+
+// BettingOver is true once everyone still in has matched the bet and had their say, or
+// when there is nobody left to bet against
+func (g *HoldemGame) BettingOver() bool {
+	if len(g.inHand()) < 2 {
+		return true
+	}
+	canAct := 0
+	for _, p := range g.Players {
+		if !p.canAct() {
+			continue
+		}
+		canAct++
+		if p.Bet < g.CurrentBet {
+			return false
+		}
+	}
+	// A lone player left with chips only has to act if they have a bet to call
+	if canAct < 2 {
+		return true
+	}
+	for _, p := range g.Players {
+		if p.canAct() && !p.Acted {
+			return false
+		}
+	}
+	return true
+}
+
+// Betting goes round the table from whoever's turn it is until the betting is over
+func (g *HoldemGame) Betting() bool {
+	for !g.BettingOver() {
+		p := g.Players[g.Turn]
+		if !p.canAct() {
+			g.Turn = g.PlayerOrder[g.nextSeat(g.GetOrder(g.Turn))]
+			continue
+		}
+		if cancelled := p.Wager(g); cancelled {
+			return true
+		}
+		g.NotifyAll()
+	}
+	return false
+}
+
+func (p *HoldemPlayer) Wager(g *HoldemGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, BetQuestion, g)
+		if cancelled {
+			return true
+		}
+		wager, violation := g.CheckWager(p.GetName(), answer)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+
+		if wager.Action == RaiseOption {
+			answer, cancelled = g.Ask(p.Seat, RaiseQuestion, g)
+			if cancelled {
+				return true
+			}
+			if wager, violation = g.CheckRaise(p.GetName(), answer); violation != nil {
+				ShowViolation(p.Decider, violation)
+				continue
+			}
+		}
+
+		g.applyWager(p, wager)
+		g.Turn = g.PlayerOrder[g.nextSeat(g.GetOrder(p.GetName()))]
+		g.emit(BetPlaced{Round: g.Round, Player: p.GetName(), Action: wager.Action, Bet: p.Bet, Pot: g.PotTotal()})
+		return false
+	}
+}
+
+// CheckWager returns the rule broken by the player's answer to BetQuestion. A raise
+// without an amount is only asking to raise, and is answered with the amount later.
+func (g *HoldemGame) CheckWager(name string, answer Answer) (Wager, *RuleViolation) {
+	wager, ok := answer.(Wager)
+	if !ok {
+		return wager, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	allowed := false
+	for _, option := range g.BetOptions(name) {
+		allowed = allowed || option == wager.Action
+	}
+	if !allowed {
+		return wager, &RuleViolation{Code: InvalidBetViolation, Message: "You can not " + wager.Action + " now"}
+	}
+	if wager.Action == RaiseOption && wager.To != 0 {
+		return g.CheckRaise(name, answer)
+	}
+	return wager, nil
+}
+
+// CheckRaise returns the rule broken by the player's answer to RaiseQuestion, which has to
+// raise to somewhere between the least raise and all the player's chips
+func (g *HoldemGame) CheckRaise(name string, answer Answer) (Wager, *RuleViolation) {
+	wager, ok := answer.(Wager)
+	if !ok || wager.Action != RaiseOption {
+		return wager, &RuleViolation{Code: MalformedAnswerViolation, Message: "Could not understand answer"}
+	}
+	p := g.Players[name]
+	if wager.To < g.CurrentBet + g.MinRaise || wager.To > p.Bet + p.Chips {
+		return wager, &RuleViolation{
+			Code: InvalidBetViolation,
+			Message: fmt.Sprintf("Raise to between %d and %d", g.CurrentBet + g.MinRaise, p.Bet + p.Chips),
+		}
+	}
+	return wager, nil
+}
+
+// applyWager puts the player's chips in. A raise of at least the last raise opens the
+// betting again for everyone, but going all in for less does not.
+func (g *HoldemGame) applyWager(p *HoldemPlayer, wager Wager) {
+	to := p.Bet
+	switch wager.Action {
+	case FoldOption:
+		p.Folded = true
+	case CallOption:
+		to = g.CurrentBet
+	case RaiseOption:
+		to = wager.To
+	case AllInOption:
+		to = p.Bet + p.Chips
+	}
+	g.putIn(p, to - p.Bet)
+	p.Acted = true
+
+	if raise := to - g.CurrentBet; raise > 0 {
+		g.CurrentBet = to
+		if raise >= g.MinRaise {
+			g.MinRaise = raise
+			for _, other := range g.Players {
+				if other != p {
+					other.Acted = false
+				}
+			}
+		}
+	}
+}
+
+// NextStreet deals the next cards onto the board and starts its betting with the first
+// player left of the button, or moves on to scoring once the betting is done with
+func (g *HoldemGame) NextStreet() {
+	if len(g.inHand()) < 2 {
+		g.setPhase(ScoringPhase)
+		return
+	}
+
+	next, cards := ScoringPhase, 0
+	switch g.Phase {
+	case PreflopPhase:
+		next, cards = FlopPhase, 3
+	case FlopPhase:
+		next, cards = TurnPhase, 1
+	case TurnPhase:
+		next, cards = RiverPhase, 1
+	}
+	if next == ScoringPhase {
+		g.Showdown = true
+		g.setPhase(ScoringPhase)
+		return
+	}
+
+	// Burn a card before dealing
+	g.Stock.Deal()
+	for i := 0; i < cards; i++ {
+		g.Board = append(g.Board, g.Stock.Deal())
+	}
+	for _, p := range g.Players {
+		p.Bet, p.Acted = 0, false
+	}
+	_, big := g.Blinds()
+	g.CurrentBet, g.MinRaise = 0, big
+	g.Turn = g.PlayerOrder[g.nextSeat(g.Button)]
+	g.setPhase(next)
+	g.emit(BoardDealt{Round: g.Round, Street: next, Board: g.Board.Copy()})
+	g.NotifyAll()
+}
+
+// AwardPots gives each pot to the best hand among the players who can win it, splitting
+// ties with any odd chips going to the first winner left of the button. It returns how
+// many chips each player won or lost on the hand, and who won each pot.
+func (g *HoldemGame) AwardPots() (map[string]int, []PotWon) {
+	ranks := map[string]HandRank{}
+	for _, name := range g.inHand() {
+		ranks[name] = EvaluateHand(append(g.Players[name].Hand.Copy(), g.Board...))
+	}
+
+	won := []PotWon{}
+	for _, pot := range g.Pots() {
+		winners := []string{}
+		for _, name := range pot.Eligible {
+			switch {
+			case len(winners) == 0 || ranks[name] > ranks[winners[0]]:
+				winners = []string{name}
+			case ranks[name] == ranks[winners[0]]:
+				winners = append(winners, name)
+			}
+		}
+		share := pot.Amount / len(winners)
+		for i, name := range winners {
+			won := share
+			if i < pot.Amount % len(winners) {
+				won++
+			}
+			g.Players[name].Chips += won
+		}
+		hand := ""
+		if g.Showdown && len(pot.Eligible) > 1 {
+			hand = ranks[winners[0]].String()
+		}
+		won = append(won, PotWon{Round: g.Round, Players: winners, Amount: pot.Amount, Hand: hand})
+	}
+
+	roundPoints := map[string]int{}
+	for name, p := range g.Players {
+		roundPoints[name] = p.Chips - p.StartChips
+		p.Bet, p.Committed = 0, 0
+	}
+	return roundPoints, won
+}
+
+// HoldemTimeout checks when it can, and folds when it can't
+func HoldemTimeout(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	hg, ok := g.(*HoldemGame)
+	if !ok || q != BetQuestion {
+		return RandomDecision(r, d, q, g)
+	}
+	if p := hg.Players[d.GetName()]; p.Bet >= hg.CurrentBet {
+		return Wager{Action: CheckOption}
+	}
+	return Wager{Action: FoldOption}
+}
+
+// HoldemCPU bets on the strength of its hand: raising with strong hands, calling with
+// fair ones when the price is right, and otherwise checking or folding. A little
+// randomness keeps it from being read too easily.
+func HoldemCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	hg, ok := g.(*HoldemGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	p := hg.Players[d.GetName()]
+	strength := HoldemStrength(p.Hand, hg.Board) + r.Intn(2)
+	options := hg.BetOptions(d.GetName())
+	can := func(option string) bool {
+		for _, o := range options {
+			if o == option {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch q {
+	case BetQuestion:
+		toCall := hg.CurrentBet - p.Bet
+		switch {
+		case strength >= 4 && can(RaiseOption):
+			return Wager{Action: RaiseOption}
+		case strength >= 4 && can(AllInOption) && !can(CallOption):
+			return Wager{Action: AllInOption}
+		case toCall == 0:
+			return Wager{Action: CheckOption}
+		case strength >= 2 && toCall <= hg.PotTotal() / 2 || strength >= 3:
+			if can(CallOption) {
+				return Wager{Action: CallOption}
+			}
+			return Wager{Action: AllInOption}
+		}
+		return Wager{Action: FoldOption}
+	case RaiseQuestion:
+		// Raise by about the pot, as much as it can
+		return Wager{Action: RaiseOption, To: min(hg.CurrentBet + max(hg.MinRaise, hg.PotTotal()), p.Bet + p.Chips)}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// HoldemStrength scores a hand from 0 to 5. Before the flop it goes on pairs and high
+// cards, after on the hand made with the board.
+func HoldemStrength(hand Deck, board Deck) int {
+	if len(board) == 0 {
+		high, low := hand[0].ValueIndex(), hand[1].ValueIndex()
+		if low > high {
+			high, low = low, high
+		}
+		switch {
+		case high == low && high >= 8:
+			return 5
+		case high == low || high >= 11 && low >= 9:
+			return 3
+		case high >= 10 || hand[0].Suit == hand[1].Suit && high - low <= 2:
+			return 2
+		}
+		return 1
+	}
+
+	switch category := EvaluateHand(append(hand.Copy(), board...)).Category(); {
+	case category >= Straight:
+		return 5
+	case category >= TwoPair:
+		return 4
+	case category == OnePair:
+		return 2
+	}
+	return 0
+}
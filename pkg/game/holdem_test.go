@@ -0,0 +1,65 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHoldemCheckRaise(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2)}
+	g := NewHoldemGame(deciders, 1, HoldemOptions{StartingChips: 1000, SmallBlind: 5, BigBlind: 10})
+	g.CurrentBet, g.MinRaise = 10, 10
+	g.Players["a"].Bet, g.Players["a"].Chips = 5, 995
+
+	tests := []struct {
+		wager Wager
+		ok bool
+	}{
+		{Wager{Action: RaiseOption, To: 20}, true},
+		{Wager{Action: RaiseOption, To: 1000}, true},
+		{Wager{Action: RaiseOption, To: 0}, false},
+		{Wager{Action: RaiseOption, To: 19}, false},
+		{Wager{Action: RaiseOption, To: 1001}, false},
+		{Wager{Action: CallOption, To: 20}, false},
+	}
+	for _, tt := range tests {
+		if _, violation := g.CheckRaise("a", tt.wager); (violation == nil) != tt.ok {
+			t.Errorf("raising with %+v: got %v, want allowed %v", tt.wager, violation, tt.ok)
+		}
+	}
+	// Asking to raise is fine before the amount is given
+	if _, violation := g.CheckWager("a", Wager{Action: RaiseOption}); violation != nil {
+		t.Errorf("asking to raise was not allowed: %s", violation.Message)
+	}
+}
+
+func TestHoldemRaiseOfNothingAskedAgain(t *testing.T) {
+	// Asks to raise, answers with no amount, then asks again and raises to 30
+	answers := []Answer{
+		Wager{Action: RaiseOption},
+		Wager{Action: RaiseOption},
+		Wager{Action: RaiseOption},
+		Wager{Action: RaiseOption, To: 30},
+	}
+	scripted := func(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+		answer := answers[0]
+		answers = answers[1:]
+		return answer
+	}
+	deciders := []Decider{NewStrategyCPU("a", 1, scripted), NewRandomCPU("b", 2)}
+	g := NewHoldemGame(deciders, 1, HoldemOptions{StartingChips: 1000, SmallBlind: 5, BigBlind: 10})
+	g.CurrentBet, g.MinRaise = 10, 10
+	a, b := g.Players["a"], g.Players["b"]
+	a.Bet, a.Committed, a.Chips = 5, 5, 995
+	b.Bet, b.Committed, b.Chips = 10, 10, 990
+
+	if cancelled := a.Wager(g); cancelled {
+		t.Fatalf("the wager was cancelled")
+	}
+	if len(answers) != 0 {
+		t.Fatalf("got %d answers left, want the raise of nothing asked again", len(answers))
+	}
+	if a.Bet != 30 || a.Chips != 970 || g.CurrentBet != 30 || g.PotTotal() != 40 {
+		t.Errorf("got a bet of %d with %d chips left and a pot of %d, want 30, 970 and 40", a.Bet, a.Chips, g.PotTotal())
+	}
+}
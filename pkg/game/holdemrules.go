@@ -0,0 +1,65 @@
+package game
+
+import (
+	"fmt"
+)
+
+type HoldemOptions struct {
+	StartingChips int `json:"starting_chips"`
+	SmallBlind int `json:"small_blind"`
+	BigBlind int `json:"big_blind"`
+	// The blinds double every this many hands, or never if zero
+	BlindLevelHands int `json:"blind_level_hands"`
+}
+
+func (o HoldemOptions) Validate() error {
+	if o.SmallBlind < 1 || o.BigBlind < o.SmallBlind {
+		return fmt.Errorf("Small blind must be at least 1, and the big blind at least the small blind")
+	}
+	if o.StartingChips < o.BigBlind {
+		return fmt.Errorf("Players must start with at least the big blind")
+	}
+	if o.BlindLevelHands < 0 {
+		return fmt.Errorf("Hands between blind increases can not be negative")
+	}
+	return nil
+}
+
+var HoldemType = &GameType{
+	Name: "holdem",
+	Title: "Texas Hold'em",
+	MinPlayers: HoldemMinPlayers,
+	MaxPlayers: HoldemMaxPlayers,
+	Options: []Option{
+		{Key: "starting_chips", Label: "Starting chips", Type: NumberOption, Default: 1000, Min: 1},
+		{Key: "small_blind", Label: "Small blind", Type: NumberOption, Default: 10, Min: 1},
+		{Key: "big_blind", Label: "Big blind", Type: NumberOption, Default: 20, Min: 1},
+		{Key: "blind_level_hands", Label: "Double the blinds every (0 for never)", Type: NumberOption, Default: 10},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o HoldemOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewHoldemGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseHoldemSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreHoldemGame(s, deciders)
+	},
+	CPU: HoldemCPU,
+	Validate: func(options Options) error {
+		var o HoldemOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(HoldemType)
+}
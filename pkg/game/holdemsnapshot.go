@@ -0,0 +1,140 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HoldemSnapshot is everything needed to carry on a game of hold'em
+type HoldemSnapshot struct {
+	TableSnapshot
+	Options HoldemOptions `json:"options"`
+	Button int `json:"button"`
+	Board Deck `json:"board"`
+	Stock Deck `json:"stock"`
+	CurrentBet int `json:"currentBet"`
+	MinRaise int `json:"minRaise"`
+	Turn string `json:"turn"`
+	Showdown bool `json:"showdown"`
+	Players map[string]HoldemPlayerSnapshot `json:"players"`
+}
+
+type HoldemPlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Chips int `json:"chips"`
+	Bet int `json:"bet"`
+	Committed int `json:"committed"`
+	StartChips int `json:"startChips"`
+	Folded bool `json:"folded"`
+	Acted bool `json:"acted"`
+	Out bool `json:"out"`
+	BustedIn int `json:"bustedIn"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *HoldemGame) Snapshot() *HoldemSnapshot {
+	players := map[string]HoldemPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = HoldemPlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Chips: p.Chips,
+			Bet: p.Bet,
+			Committed: p.Committed,
+			StartChips: p.StartChips,
+			Folded: p.Folded,
+			Acted: p.Acted,
+			Out: p.Out,
+			BustedIn: p.BustedIn,
+			TimeBank: p.TimeBank(),
+		}
+	}
+
+	return &HoldemSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Button: g.Button,
+		Board: g.Board.Copy(),
+		Stock: g.Stock.Copy(),
+		CurrentBet: g.CurrentBet,
+		MinRaise: g.MinRaise,
+		Turn: g.Turn,
+		Showdown: g.Showdown,
+		Players: players,
+	}
+}
+
+func (g *HoldemGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreHoldemGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names
+func RestoreHoldemGame(s *HoldemSnapshot, deciders []Decider) (*HoldemGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewHoldemGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Button = s.Button
+	g.Board = s.Board.Copy()
+	g.Stock = s.Stock.Copy()
+	g.CurrentBet = s.CurrentBet
+	g.MinRaise = s.MinRaise
+	g.Turn = s.Turn
+	g.Showdown = s.Showdown
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Chips = ps.Chips
+		p.Bet = ps.Bet
+		p.Committed = ps.Committed
+		p.StartChips = ps.StartChips
+		p.Folded = ps.Folded
+		p.Acted = ps.Acted
+		p.Out = ps.Out
+		p.BustedIn = ps.BustedIn
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks the button is at a seat, nobody has a negative stack or bet, and that a betting round has a seated player to act and no more than five cards on the board
+func (s *HoldemSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(HoldemMinPlayers, HoldemMaxPlayers, HoldemPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if s.Button < 0 || s.Button >= len(s.PlayerOrder) {
+		return fmt.Errorf("button is not at a seat")
+	}
+	for _, name := range s.PlayerOrder {
+		ps := s.Players[name]
+		if ps.Chips < 0 || ps.Bet < 0 || ps.Bet > ps.Committed {
+			return fmt.Errorf("[%v] has a negative stack or bet", name)
+		}
+	}
+	switch s.Phase {
+	case PreflopPhase, FlopPhase, TurnPhase, RiverPhase:
+		if _, ok := s.Players[s.Turn]; !ok {
+			return fmt.Errorf("[%v] is not seated to bet", s.Turn)
+		}
+		if len(s.Board) > 5 || len(s.Stock) + len(s.Board) > len(NewDeck()) {
+			return fmt.Errorf("too many cards on the board")
+		}
+	}
+	return nil
+}
+
+func ParseHoldemSnapshot(data []byte) (*HoldemSnapshot, error) {
+	s := &HoldemSnapshot{}
+	return s, parseSnapshot(data, s)
+}
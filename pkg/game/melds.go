@@ -0,0 +1,223 @@
+package game
+
+import (
+	"sort"
+)
+
+// Melds are sets of three or four cards of the same value, and runs of three or more cards
+// in a row in one suit, with aces low.
+
+// sortMeld puts a meld in order, aces low
+func sortMeld(meld Deck) {
+	sort.Slice(meld, func(i, j int) bool {
+		if meld[i].AceLowIndex() != meld[j].AceLowIndex() {
+			return meld[i].AceLowIndex() < meld[j].AceLowIndex()
+		}
+		return meld[i].SuitIndex() < meld[j].SuitIndex()
+	})
+}
+
+// DeadwoodValue is what an unmelded card counts against its holder
+func DeadwoodValue(c Card) int {
+	return c.PipValue()
+}
+
+func DeadwoodCount(cards Deck) int {
+	count := 0
+	for _, c := range cards {
+		count += DeadwoodValue(c)
+	}
+	return count
+}
+
+// IsSet is true for three or four cards of the same value
+func IsSet(cards Deck) bool {
+	if len(cards) < 3 || len(cards) > 4 {
+		return false
+	}
+	for _, c := range cards {
+		if c.Value != cards[0].Value {
+			return false
+		}
+	}
+	return true
+}
+
+// IsRun is true for three or more cards of one suit in a row, in any order
+func IsRun(cards Deck) bool {
+	if len(cards) < 3 {
+		return false
+	}
+	low, high := cards[0].AceLowIndex(), cards[0].AceLowIndex()
+	for _, c := range cards {
+		if c.Suit != cards[0].Suit {
+			return false
+		}
+		low, high = min(low, c.AceLowIndex()), max(high, c.AceLowIndex())
+	}
+	seen := map[int]bool{}
+	for _, c := range cards {
+		seen[c.AceLowIndex()] = true
+	}
+	return len(seen) == len(cards) && high - low == len(cards) - 1
+}
+
+// possibleMelds lists every meld that can be made from the hand, as indices into it
+func possibleMelds(hand Deck) [][]int {
+	melds := [][]int{}
+	byValue := map[CardValue][]int{}
+	bySuit := map[Suit][]int{}
+	for i, c := range hand {
+		byValue[c.Value] = append(byValue[c.Value], i)
+		bySuit[c.Suit] = append(bySuit[c.Suit], i)
+	}
+
+	for _, v := range CardValues {
+		same := byValue[v]
+		if len(same) >= 3 {
+			melds = append(melds, same)
+		}
+		// Any three of four make a set too, leaving the fourth for a run
+		if len(same) == 4 {
+			for skip := range same {
+				set := []int{}
+				for i, index := range same {
+					if i != skip {
+						set = append(set, index)
+					}
+				}
+				melds = append(melds, set)
+			}
+		}
+	}
+
+	for _, s := range Suits {
+		byRank := make([]int, len(CardValues))
+		for i := range byRank {
+			byRank[i] = -1
+		}
+		for _, index := range bySuit[s] {
+			byRank[hand[index].AceLowIndex()] = index
+		}
+		for low := range byRank {
+			run := []int{}
+			for r := low; r < len(byRank) && byRank[r] != -1; r++ {
+				run = append(run, byRank[r])
+				if len(run) >= 3 {
+					melds = append(melds, append([]int{}, run...))
+				}
+			}
+		}
+	}
+	return melds
+}
+
+// ArrangeMelds finds the melds in the hand that leave the least deadwood, as judged by
+// cost, which is given the melds and the cards left over. It tries every way of making
+// melds from the hand, which is quick enough for the ten or eleven cards of a rummy hand.
+func ArrangeMelds(hand Deck, cost func(melds []Deck, deadwood Deck) int) ([]Deck, Deck) {
+	candidates := possibleMelds(hand)
+	used := make([]bool, len(hand))
+	chosen := [][]int{}
+
+	bestCost := -1
+	var bestMelds []Deck
+	var bestDeadwood Deck
+	var search func(next int)
+	search = func(next int) {
+		// Each meld is chosen by its first card, so every arrangement is only tried once
+		for next < len(hand) && used[next] {
+			next++
+		}
+		if next == len(hand) {
+			melds := []Deck{}
+			for _, meld := range chosen {
+				cards := Deck{}
+				for _, i := range meld {
+					cards = append(cards, hand[i])
+				}
+				melds = append(melds, cards)
+			}
+			deadwood := Deck{}
+			for i, c := range hand {
+				if !used[i] {
+					deadwood = append(deadwood, c)
+				}
+			}
+			if c := cost(melds, deadwood); bestCost == -1 || c < bestCost {
+				bestCost, bestMelds, bestDeadwood = c, melds, deadwood
+			}
+			return
+		}
+
+		for _, meld := range candidates {
+			if !meldStartsAt(meld, next, used) {
+				continue
+			}
+			for _, i := range meld {
+				used[i] = true
+			}
+			chosen = append(chosen, meld)
+			search(next + 1)
+			chosen = chosen[:len(chosen) - 1]
+			for _, i := range meld {
+				used[i] = false
+			}
+		}
+
+		// Or leave the card as deadwood
+		search(next + 1)
+	}
+	search(0)
+	return bestMelds, bestDeadwood
+}
+
+// meldStartsAt is true if the meld has the card at index first as its lowest index, and
+// none of its cards are used yet
+func meldStartsAt(meld []int, first int, used []bool) bool {
+	hasFirst := false
+	for _, i := range meld {
+		if i < first || used[i] {
+			return false
+		}
+		hasFirst = hasFirst || i == first
+	}
+	return hasFirst
+}
+
+// BestMelds arranges the hand to leave the least deadwood
+func BestMelds(hand Deck) ([]Deck, Deck) {
+	return ArrangeMelds(hand, func(melds []Deck, deadwood Deck) int {
+		return DeadwoodCount(deadwood)
+	})
+}
+
+// LayOff adds what it can of the deadwood onto the melds, returning the melds with the
+// cards added, the deadwood left over and the cards laid off. Cards are laid off one at a
+// time until none fit, so a run can be extended by several cards.
+func LayOff(melds []Deck, deadwood Deck) ([]Deck, Deck, Deck) {
+	extended := []Deck{}
+	for _, meld := range melds {
+		extended = append(extended, meld.Copy())
+	}
+	left := deadwood.Copy()
+	laidOff := Deck{}
+	for placed := true; placed; {
+		placed = false
+		for i := 0; i < len(left); i++ {
+			for m, meld := range extended {
+				grown := append(meld.Copy(), left[i])
+				if IsSet(grown) || IsRun(grown) {
+					sortMeld(grown)
+					extended[m] = grown
+					laidOff = append(laidOff, left[i])
+					left = append(left[:i], left[i+1:]...)
+					i--
+					placed = true
+					break
+				}
+			}
+		}
+	}
+	return extended, left, laidOff
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestBestMelds(t *testing.T) {
+	tests := []struct {
+		hand string
+		melds int
+		deadwood int
+	}{
+		{"AS 2S 3S 4S 5S", 1, 0},
+		// Aces are low, so they can not go on the end of a run above the king
+		{"QS KS AS", 0, 21},
+		// The seven is needed in both the set and the run, and the run leaves less
+		{"7H 7S 7D 8D 9D", 1, 14},
+		// With a fourth seven, the set and the run can both be made
+		{"7H 7S 7D 7C 8D 9D", 2, 0},
+		{"KH KS KD QH JH 10H", 2, 0},
+		{"2C 3C 4C 4D 4H 5C", 1, 8},
+		{"2H 3H 4H 5H 5S 5D 5C 9S 9D 9C", 3, 0},
+		{"KH QH JS 10C 8D 7C 6S AD 2D 4C", 0, 68},
+	}
+	for _, tt := range tests {
+		melds, deadwood := BestMelds(cards(tt.hand))
+		if len(melds) != tt.melds || DeadwoodCount(deadwood) != tt.deadwood {
+			t.Errorf("%s: got melds %v leaving %d, want %d melds leaving %d", tt.hand, melds, DeadwoodCount(deadwood), tt.melds, tt.deadwood)
+		}
+		for _, meld := range melds {
+			if !IsSet(meld) && !IsRun(meld) {
+				t.Errorf("%s: %v is not a meld", tt.hand, meld)
+			}
+		}
+	}
+}
+
+func TestLayOff(t *testing.T) {
+	melds := []Deck{cards("7H 8H 9H"), cards("KS KD KC")}
+	// The jack only fits once the ten has been laid off
+	extended, left, laidOff := LayOff(melds, cards("JH 10H KH 2C"))
+	if len(left) != 1 || left[0] != cards("2C")[0] {
+		t.Errorf("got %v left over, want 2C", left)
+	}
+	if len(laidOff) != 3 {
+		t.Errorf("got %v laid off, want three cards", laidOff)
+	}
+	if len(extended[0]) != 5 || len(extended[1]) != 4 {
+		t.Errorf("got melds %v", extended)
+	}
+	if len(melds[0]) != 3 || len(melds[1]) != 3 {
+		t.Errorf("the melds given were changed to %v", melds)
+	}
+}
@@ -0,0 +1,596 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	OhHellMinPlayers = 3
+	OhHellMaxPlayers = 7
+	WhistPlayers = 4
+	// Making a bid exactly scores this on top of a point for each trick bid
+	ExactBidBonus = 10
+	// At whist a partnership scores a point for each trick over its book of six
+	WhistBook = 6
+)
+
+// Oh hell deals, bids then plays. Whist has no bidding, so it goes straight from the deal
+// to playing.
+var OhHellPhases = PhaseTransitions{
+	DealingPhase: {BiddingPhase, PlayingPhase},
+	BiddingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type OhHellPlayer struct {
+	*Seat
+	Hand Deck
+	Bid int
+	HasBid bool
+	TricksWon int
+	// At whist partners share a score, each keeping a copy of it
+	Score int
+}
+
+type OhHellGame struct {
+	Players map[string]*OhHellPlayer
+	Options OhHellOptions
+	HandSize int
+	// The card turned up after the deal to make its suit trump, or the dealer's last card
+	// at whist. Trump is empty for a hand with no card left to turn.
+	TurnedUp *Card
+	Trump Suit
+	Table
+	TrickTaking
+}
+
+type OhHellPlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Bid string `json:"bid"`
+	TricksWon int `json:"tricksWon"`
+	Partner string `json:"partner,omitempty"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type OhHellGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]OhHellPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	TurnedUp *Card `json:"turnedUp,omitempty"`
+	HandSize int `json:"handSize"`
+	BidTotal int `json:"bidTotal"`
+	RoundsLeft int `json:"roundsLeft,omitempty"`
+	Trick int `json:"trick"`
+	Hand Deck `json:"hand"`
+	LegalPlays Deck `json:"legalPlays"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewOhHellGame(deciders []Decider, seed int64, options OhHellOptions) *OhHellGame {
+	g := &OhHellGame{
+		Players: map[string]*OhHellPlayer{},
+		Options: options,
+	}
+	g.Init(g, seed, OhHellPhases, DealingPhase, RandomDecision)
+	g.InitTricks(ohHellTricks{g: g})
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &OhHellPlayer{Seat: s}
+	})
+	return g
+}
+
+func (g *OhHellGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]OhHellPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		info := OhHellPlayerInfo{
+			NumCards: len(p.Hand),
+			Score: p.Score,
+			Bid: p.BidString(),
+			TricksWon: p.TricksWon,
+			Lead: name == g.Leader,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+		if g.Options.Whist {
+			info.Partner = g.Partner(name)
+		}
+		playerInfo[name] = info
+	}
+
+	roundsLeft := 0
+	if !g.Options.Whist {
+		roundsLeft = g.TotalRounds() - g.Round
+	}
+
+	return &OhHellGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		CurrentTrick: g.CurrentTrick,
+		Tricks: g.Tricks,
+		TurnedUp: g.TurnedUp,
+		HandSize: g.HandSize,
+		BidTotal: g.BidTotal(),
+		RoundsLeft: roundsLeft,
+		Trick: g.Trick,
+		Hand: g.Players[decider.GetName()].Hand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (og *OhHellGameInfo) String() string {
+	var b strings.Builder
+	for _, name := range og.PlayerOrder {
+		info := og.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v, bid %v, took %v ) ", name, info.Score, info.Bid, info.TricksWon)
+	}
+	if og.TurnedUp != nil {
+		fmt.Fprintf(&b, "\nTrump: %v", og.TurnedUp)
+	}
+	fmt.Fprintf(&b, "\nCurrent Trick: %v\n", og.CurrentTrick)
+	fmt.Fprintf(&b, "Hand: %v", og.Hand.NumberedString())
+	return b.String()
+}
+
+// BidString is the bid as players see it, empty until it is made
+func (p *OhHellPlayer) BidString() string {
+	if !p.HasBid {
+		return ""
+	}
+	return strconv.Itoa(p.Bid)
+}
+
+func (g *OhHellGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case BidQuestion:
+		options := []string{}
+		for i := 0; i <= g.HandSize; i++ {
+			if g.CheckBid(name, i) == nil {
+				options = append(options, strconv.Itoa(i))
+			}
+		}
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("How many tricks will you take? %d bid so far", g.BidTotal()),
+			Hand: p.Hand.Copy(),
+			Options: options,
+		}
+	case PlayOnTrickQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *OhHellGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case BidQuestion:
+		tricks, err := strconv.Atoi(r.Option)
+		if err != nil {
+			return nil
+		}
+		return TrickBid{tricks}
+	case PlayOnTrickQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	}
+	return nil
+}
+
+func (g *OhHellGame) GetPlayer(i int) *OhHellPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// Partner is the player sitting across the table, who is the player's partner at whist
+func (g *OhHellGame) Partner(name string) string {
+	return g.PlayerOrder[(g.GetOrder(name) + g.NumPlayers() / 2) % g.NumPlayers()]
+}
+
+// Dealer is the seat of the player dealing this round, which moves to the left each round
+func (g *OhHellGame) Dealer() int {
+	return g.Round % g.NumPlayers()
+}
+
+// MaxHandSize is the most cards dealt to each player, leaving a card over to turn for trump
+func (g *OhHellGame) MaxHandSize() int {
+	most := (len(CardValues) * len(Suits) - 1) / g.NumPlayers()
+	if g.Options.MaxHandSize > 0 && g.Options.MaxHandSize < most {
+		return g.Options.MaxHandSize
+	}
+	return most
+}
+
+// TotalRounds is how many hands of oh hell are played, going up a card a hand from one
+// to the most, then back down again
+func (g *OhHellGame) TotalRounds() int {
+	return 2 * g.MaxHandSize() - 1
+}
+
+// RoundHandSize is how many cards each player is dealt in the round
+func (g *OhHellGame) RoundHandSize(round int) int {
+	if g.Options.Whist {
+		return len(CardValues) * len(Suits) / g.NumPlayers()
+	}
+	if round < g.MaxHandSize() {
+		return round + 1
+	}
+	return g.TotalRounds() - round
+}
+
+// BidTotal is every bid made so far this round
+func (g *OhHellGame) BidTotal() int {
+	total := 0
+	for _, p := range g.Players {
+		total += p.Bid
+	}
+	return total
+}
+
+// GameOver is true after the last hand of oh hell, or once a partnership reaches the
+// target at whist
+func (g *OhHellGame) GameOver() bool {
+	return g.Cancelled || g.Phase == FinishedPhase
+}
+
+func (g *OhHellGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name, p := range g.Players {
+		scores[name] = p.Score
+	}
+	return scores
+}
+
+// Result ranks the players, partners at whist sharing their place
+func (g *OhHellGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+func (g *OhHellGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	if g.Phase == BiddingPhase {
+		if cancelled := g.Bid(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == PlayingPhase {
+		for g.Trick < g.HandSize {
+			if cancelled := g.PlayTrick(); cancelled {
+				return true
+			}
+		}
+		g.setPhase(ScoringPhase)
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Leader = ""
+	g.Round++
+	if g.finished() {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// finished is true once every hand of oh hell has been played, or a partnership has
+// reached the target at whist
+func (g *OhHellGame) finished() bool {
+	if !g.Options.Whist {
+		return g.Round >= g.TotalRounds()
+	}
+	for _, p := range g.Players {
+		if p.Score >= g.Options.TargetScore {
+			return true
+		}
+	}
+	return false
+}
+
+// Deal hands out the round's cards from the left of the dealer, and turns up the next card
+// for trump. At whist every card is dealt, and the dealer's last card is turned instead.
+func (g *OhHellGame) Deal() {
+	g.StartTricks()
+	g.HandSize = g.RoundHandSize(g.Round)
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.Bid, p.HasBid = 0, false
+		p.TricksWon = 0
+	}
+	d := NewDeck()
+	d.Shuffle(g.RoundRand())
+	for i := 1; i <= g.HandSize * g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		p.Hand = append(p.Hand, d.Deal())
+	}
+	g.TurnedUp, g.Trump = nil, ""
+	if dealer := g.GetPlayer(g.Dealer()); g.Options.Whist {
+		c := dealer.Hand[len(dealer.Hand) - 1]
+		g.TurnedUp = &c
+	} else if !d.Empty() {
+		c := d.Deal()
+		g.TurnedUp = &c
+	}
+	if g.TurnedUp != nil {
+		g.Trump = g.TurnedUp.Suit
+	}
+	for _, p := range g.Players {
+		p.Hand.Sort()
+	}
+	g.Leader = g.PlayerOrder[(g.Dealer() + 1) % g.NumPlayers()]
+	if g.Options.Whist {
+		g.setPhase(PlayingPhase)
+	} else {
+		g.setPhase(BiddingPhase)
+	}
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+	if g.TurnedUp != nil {
+		g.emit(TrumpTurned{Round: g.Round, Card: *g.TurnedUp})
+	}
+}
+
+// Bid goes round the table from the left of the dealer, picking up after any bids already
+// made, so the dealer bids last
+func (g *OhHellGame) Bid() bool {
+	for i := 1; i <= g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		if p.HasBid {
+			continue
+		}
+		if cancelled := p.MakeBid(g); cancelled {
+			return true
+		}
+		g.emit(BidMade{Round: g.Round, Player: p.GetName(), Tricks: p.Bid})
+		g.NotifyAll()
+	}
+
+	g.setPhase(PlayingPhase)
+	return false
+}
+
+// CheckBid returns the rule broken by the bid. With the hook, the dealer can't make a bid
+// that lets every bid be made.
+func (g *OhHellGame) CheckBid(name string, bid int) *RuleViolation {
+	if bid < 0 || bid > g.HandSize {
+		return &RuleViolation{
+			Code: InvalidBidViolation,
+			Message: fmt.Sprintf("Bid must be from 0 to %d tricks", g.HandSize),
+		}
+	}
+	if g.Options.Hook && name == g.PlayerOrder[g.Dealer()] && g.BidTotal() + bid == g.HandSize {
+		return &RuleViolation{
+			Code: InvalidBidViolation,
+			Message: fmt.Sprintf("The dealer can not bid %d, the bids can not add up to %d", bid, g.HandSize),
+		}
+	}
+	return nil
+}
+
+func (p *OhHellPlayer) MakeBid(g *OhHellGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+	for {
+		answer, cancelled := g.Ask(p.Seat, BidQuestion, g)
+		if cancelled {
+			return true
+		}
+		bid, violation := ValidateBid(answer, 0, g.HandSize)
+		if violation == nil {
+			violation = g.CheckBid(p.GetName(), bid)
+		}
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		p.Bid, p.HasBid = bid, true
+		return false
+	}
+}
+
+func (g *OhHellGame) PlayTrick() bool {
+	play := func(name string) (Card, bool) {
+		p := g.Players[name]
+		return g.PlayFromHand(&g.Table, p.Seat, &p.Hand, g)
+	}
+	played := func(name string, card Card) {
+		g.emit(CardPlayed{Round: g.Round, Trick: g.Trick, Player: name, Card: card})
+		g.NotifyAll()
+	}
+	trick := g.Trick
+	won, cancelled := g.PlayCards(SeatsFrom(g.PlayerOrder, g.Leader), play, played)
+	if cancelled {
+		return true
+	}
+
+	g.Players[won.Winner].TricksWon++
+	g.emit(TrickWon{Round: g.Round, Trick: trick, Leader: won.Leader, Winner: won.Winner, Cards: won.Cards})
+	g.NotifyAll()
+
+	return false
+}
+
+// ohHellTricks are the rules of an oh hell or whist trick: the turned up suit is trumps,
+// and any card can be led, trumps included
+type ohHellTricks struct {
+	PlainTricks
+	g *OhHellGame
+}
+
+func (r ohHellTricks) TrumpSuit() Suit {
+	return r.g.Trump
+}
+
+func (g *OhHellGame) LegalPlays(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok || p.Asking() != PlayOnTrickQuestion {
+		return Deck{}
+	}
+	return g.LegalCards(p.Hand)
+}
+
+// ScoreRound adds each player's points for the round to their score, and returns them. At
+// oh hell a player who takes exactly the tricks they bid scores ExactBidBonus and a point
+// for each, and anyone else scores nothing. At whist each partnership scores a point for
+// every trick it took over its book.
+func (g *OhHellGame) ScoreRound() map[string]int {
+	roundPoints := map[string]int{}
+	for name, p := range g.Players {
+		points := 0
+		switch {
+		case g.Options.Whist:
+			points = max(0, p.TricksWon + g.Players[g.Partner(name)].TricksWon - WhistBook)
+		case p.TricksWon == p.Bid:
+			points = ExactBidBonus + p.Bid
+		}
+		roundPoints[name] = points
+	}
+	for name, p := range g.Players {
+		p.Score += roundPoints[name]
+	}
+	return roundPoints
+}
+
+// OhHellCPU bids the tricks its high cards and trumps look good for, steering clear of the
+// dealer's forbidden bid. In play it tries to win cheaply while it still needs tricks,
+// and to duck under the trick once it has its bid.
+func OhHellCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	og, ok := g.(*OhHellGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	name := d.GetName()
+	p := og.Players[name]
+
+	switch q {
+	case BidQuestion:
+		bid := EstimateOhHellTricks(p.Hand, og.Trump)
+		for offset := 0; offset <= og.HandSize; offset++ {
+			for _, b := range []int{bid - offset, bid + offset} {
+				if og.CheckBid(name, b) == nil {
+					return TrickBid{b}
+				}
+			}
+		}
+	case PlayOnTrickQuestion:
+		need := og.Options.Whist || p.TricksWon < p.Bid
+		// Trumps rank above every other suit
+		rank := func(c Card) int {
+			if c.Suit == og.Trump {
+				return c.ValueIndex() + len(CardValues)
+			}
+			return c.ValueIndex()
+		}
+		lowest := func(cards Deck) Card {
+			best := cards[0]
+			for _, c := range cards {
+				if rank(c) < rank(best) {
+					best = c
+				}
+			}
+			return best
+		}
+		winning, losing := Deck{}, Deck{}
+		for _, c := range og.LegalPlays(name) {
+			if og.TrickWinner(append(og.CurrentTrick.Copy(), c)) == len(og.CurrentTrick) {
+				winning = append(winning, c)
+			} else {
+				losing = append(losing, c)
+			}
+		}
+		var card Card
+		switch {
+		case need && len(winning) > 0:
+			card = lowest(winning)
+		case !need && len(losing) > 0:
+			// Throw the highest card that still loses
+			card = losing[0]
+			for _, c := range losing {
+				if rank(c) > rank(card) {
+					card = c
+				}
+			}
+		case len(losing) > 0:
+			card = lowest(losing)
+		default:
+			card = lowest(winning)
+		}
+		return CardPlay{p.Hand.Index(card)}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// EstimateOhHellTricks counts aces, guarded kings and high trumps, and trumps beyond the
+// first few as likely tricks
+func EstimateOhHellTricks(hand Deck, trump Suit) int {
+	tricks := 0
+	trumps := 0
+	for _, c := range hand {
+		if c.Suit == trump {
+			trumps++
+		}
+	}
+	for _, c := range hand {
+		switch {
+		case c.Value == Ace:
+			tricks++
+		case c.Suit == trump && (c.Value == King || c.Value == Queen && trumps >= 3):
+			tricks++
+		case c.Value == King && len(suitCards(hand, c.Suit)) >= 2 && len(hand) >= 5:
+			tricks++
+		}
+	}
+	if trumps > 3 {
+		tricks += trumps - 3
+	}
+	return min(tricks, len(hand))
+}
+
+func suitCards(hand Deck, s Suit) Deck {
+	cards := Deck{}
+	for _, c := range hand {
+		if c.Suit == s {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
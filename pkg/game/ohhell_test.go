@@ -0,0 +1,67 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestOhHellHook(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+	g := NewOhHellGame(deciders, 1, OhHellOptions{Hook: true, TargetScore: 1})
+	g.HandSize = 5
+	for name, bid := range map[string]int{"b": 2, "c": 1, "d": 1} {
+		g.Players[name].Bid, g.Players[name].HasBid = bid, true
+	}
+	dealer := g.PlayerOrder[g.Dealer()]
+	if dealer != "a" {
+		t.Fatalf("got %s dealing the first hand, want a", dealer)
+	}
+
+	for bid := 0; bid <= g.HandSize; bid++ {
+		violation := g.CheckBid(dealer, bid)
+		if bid == 1 && violation == nil {
+			t.Errorf("the dealer was allowed to bid %d to make the bids add up to %d", bid, g.HandSize)
+		}
+		if bid != 1 && violation != nil {
+			t.Errorf("the dealer was not allowed to bid %d: %s", bid, violation.Message)
+		}
+	}
+	// Only the dealer is on the hook
+	if violation := g.CheckBid("b", 1); violation != nil {
+		t.Errorf("b was not allowed to bid 1: %s", violation.Message)
+	}
+	for _, option := range g.Prompt(deciders[0], BidQuestion).Options {
+		if option == "1" {
+			t.Errorf("the dealer is offered a bid of 1")
+		}
+	}
+
+	g.Options.Hook = false
+	if violation := g.CheckBid(dealer, 1); violation != nil {
+		t.Errorf("without the hook the dealer was not allowed to bid 1: %s", violation.Message)
+	}
+}
+
+func TestOhHellHookInPlay(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3)}
+	g := NewOhHellGame(deciders, 1, OhHellOptions{Hook: true, MaxHandSize: 4, TargetScore: 1})
+	handSizes := map[int]int{}
+	bids := map[int]int{}
+	g.Subscribe(func(e Event) {
+		switch e := e.(type) {
+		case HandDealt:
+			handSizes[e.Round] = len(e.Hand)
+		case BidMade:
+			bids[e.Round] += e.Tricks
+		}
+	})
+	<-g.Start()
+
+	if len(handSizes) == 0 {
+		t.Fatalf("no hands were dealt")
+	}
+	for round, size := range handSizes {
+		if bids[round] == size {
+			t.Errorf("the bids in round %d add up to the %d cards dealt", round, size)
+		}
+	}
+}
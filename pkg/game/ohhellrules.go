@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+)
+
+type OhHellOptions struct {
+	// Plain whist: four players in partnerships, every card dealt and no bidding
+	Whist bool `json:"whist"`
+	// The most cards dealt in a hand of oh hell, or as many as the deck allows if zero
+	MaxHandSize int `json:"max_hand_size"`
+	// The hook stops the dealer bidding so that every bid could be made
+	Hook bool `json:"hook"`
+	// The points a partnership has to reach to win at whist
+	TargetScore int `json:"target_score"`
+}
+
+func (o OhHellOptions) Validate() error {
+	if o.MaxHandSize < 0 {
+		return fmt.Errorf("Most cards in a hand can not be negative")
+	}
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	return nil
+}
+
+var OhHellType = &GameType{
+	Name: "ohhell",
+	Title: "Oh Hell",
+	MinPlayers: OhHellMinPlayers,
+	MaxPlayers: OhHellMaxPlayers,
+	Options: []Option{
+		{Key: "whist", Label: "Play plain whist (4 players, no bidding)", Type: BoolOption, Default: false},
+		{Key: "max_hand_size", Label: "Most cards in a hand (0 for as many as possible)", Type: NumberOption, Default: 0},
+		{Key: "hook", Label: "Dealer can not make the bids add up", Type: BoolOption, Default: true},
+		{Key: "target_score", Label: "Whist points to win", Type: NumberOption, Default: 5, Min: 1},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o OhHellOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		if o.Whist && len(deciders) != WhistPlayers {
+			return nil, fmt.Errorf("Whist is played by %d players", WhistPlayers)
+		}
+		return NewOhHellGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseOhHellSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreOhHellGame(s, deciders)
+	},
+	CPU: OhHellCPU,
+	Validate: func(options Options) error {
+		var o OhHellOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(OhHellType)
+}
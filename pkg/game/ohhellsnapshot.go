@@ -0,0 +1,132 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OhHellSnapshot is everything needed to carry on a game of oh hell or whist
+type OhHellSnapshot struct {
+	TableSnapshot
+	Options OhHellOptions `json:"options"`
+	HandSize int `json:"handSize"`
+	TurnedUp *Card `json:"turnedUp"`
+	Trump Suit `json:"trump"`
+	Trick int `json:"trick"`
+	Leader string `json:"leader"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Players map[string]OhHellPlayerSnapshot `json:"players"`
+}
+
+type OhHellPlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Bid int `json:"bid"`
+	HasBid bool `json:"hasBid"`
+	TricksWon int `json:"tricksWon"`
+	Score int `json:"score"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *OhHellGame) Snapshot() *OhHellSnapshot {
+	players := map[string]OhHellPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = OhHellPlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Bid: p.Bid,
+			HasBid: p.HasBid,
+			TricksWon: p.TricksWon,
+			Score: p.Score,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	var turnedUp *Card
+	if g.TurnedUp != nil {
+		c := *g.TurnedUp
+		turnedUp = &c
+	}
+
+	return &OhHellSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		HandSize: g.HandSize,
+		TurnedUp: turnedUp,
+		Trump: g.Trump,
+		Trick: g.Trick,
+		Leader: g.Leader,
+		CurrentTrick: g.CurrentTrick.Copy(),
+		Tricks: append([]CompletedTrick{}, g.Tricks...),
+		Players: players,
+	}
+}
+
+func (g *OhHellGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreOhHellGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names
+func RestoreOhHellGame(s *OhHellSnapshot, deciders []Decider) (*OhHellGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewOhHellGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.HandSize = s.HandSize
+	if s.TurnedUp != nil {
+		c := *s.TurnedUp
+		g.TurnedUp = &c
+	}
+	g.Trump = s.Trump
+	g.Trick = s.Trick
+	g.Leader = s.Leader
+	g.CurrentTrick = s.CurrentTrick.Copy()
+	g.Tricks = append([]CompletedTrick{}, s.Tricks...)
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Bid = ps.Bid
+		p.HasBid = ps.HasBid
+		p.TricksWon = ps.TricksWon
+		p.Score = ps.Score
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks whist has four players and the hand size can be dealt from one deck, and that a trick being played has a seated leader and no more cards than players
+func (s *OhHellSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(OhHellMinPlayers, OhHellMaxPlayers, OhHellPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if s.Options.Whist && len(s.PlayerOrder) != WhistPlayers {
+		return fmt.Errorf("cannot play whist with %d players", len(s.PlayerOrder))
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if s.HandSize < 0 || s.HandSize * len(s.PlayerOrder) > len(NewDeck()) {
+		return fmt.Errorf("cannot deal %d cards each", s.HandSize)
+	}
+	if s.Phase == PlayingPhase {
+		if _, ok := s.Players[s.Leader]; !ok {
+			return fmt.Errorf("[%v] is not seated to lead", s.Leader)
+		}
+		if len(s.CurrentTrick) > len(s.PlayerOrder) {
+			return fmt.Errorf("too many cards on the trick")
+		}
+	}
+	return nil
+}
+
+func ParseOhHellSnapshot(data []byte) (*OhHellSnapshot, error) {
+	s := &OhHellSnapshot{}
+	return s, parseSnapshot(data, s)
+}
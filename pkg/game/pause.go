@@ -0,0 +1,102 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// pauser holds a game still between moves. While paused, no one is asked to decide,
+// answers that come in are held back, and the turn timers stop.
+type pauser struct {
+	pauseLock sync.Mutex
+	paused bool
+	pausedAt time.Time
+	// Closed while the game is paused, and while it is running respectively
+	pausing chan bool
+	resuming chan bool
+}
+
+// Pause stops the game where it is, returning false if it was already paused or over
+func (t *Table) Pause() bool {
+	if t.game.GameOver() {
+		return false
+	}
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+
+	if t.paused || t.Cancelled {
+		return false
+	}
+	t.paused = true
+	t.pausedAt = time.Now()
+	close(t.pausing)
+	t.resuming = make(chan bool)
+	return true
+}
+
+// Resume carries on from where the game was paused, with the clocks as they were
+func (t *Table) Resume() bool {
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+
+	if !t.paused {
+		return false
+	}
+	pausedFor := time.Since(t.pausedAt)
+	for _, s := range t.seats {
+		s.shiftClock(pausedFor)
+	}
+	t.paused = false
+	t.pausedAt = time.Time{}
+	close(t.resuming)
+	t.pausing = make(chan bool)
+	return true
+}
+
+func (t *Table) Paused() bool {
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+	return t.paused
+}
+
+func (t *Table) pauseSignals() (pausing chan bool, resuming chan bool) {
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+	return t.pausing, t.resuming
+}
+
+// waitWhilePaused blocks until the game is running, returning true if it was cancelled
+// instead
+func (t *Table) waitWhilePaused() bool {
+	_, resuming := t.pauseSignals()
+	select {
+	case <-resuming:
+		return false
+	case <-t.cancelled:
+		return true
+	}
+}
+
+// clockNow is the time as far as the turn timers are concerned, which stands still while
+// the game is paused
+func (t *Table) clockNow() time.Time {
+	t.pauseLock.Lock()
+	defer t.pauseLock.Unlock()
+	if t.paused {
+		return t.pausedAt
+	}
+	return time.Now()
+}
+
+// shiftClock gives back the time a turn spent paused
+func (s *Seat) shiftClock(pausedFor time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.turnStarted.IsZero() {
+		s.turnStarted = s.turnStarted.Add(pausedFor)
+	}
+	if !s.deadline.IsZero() {
+		s.deadline = s.deadline.Add(pausedFor)
+	}
+}
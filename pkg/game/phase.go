@@ -0,0 +1,66 @@
+package game
+
+import (
+	"fmt"
+)
+
+// Phase is the part of the round the game is in. A game only ever moves through its
+// phases in order, round after round, until the round that ends it is scored.
+type Phase string
+const (
+	DealingPhase = Phase("dealing")
+	PassingPhase = Phase("passing")
+	BiddingPhase = Phase("bidding")
+	DiscardingPhase = Phase("discarding")
+	PlayingPhase = Phase("playing")
+	// The betting rounds of hold'em
+	PreflopPhase = Phase("preflop")
+	FlopPhase = Phase("flop")
+	TurnPhase = Phase("turn")
+	RiverPhase = Phase("river")
+	// Bets go down before a blackjack deal, and insurance is offered against a dealer's ace
+	BettingPhase = Phase("betting")
+	InsurancePhase = Phase("insurance")
+	ScoringPhase = Phase("scoring")
+	FinishedPhase = Phase("finished")
+)
+
+// PhaseTransitions lists the phases each phase of a game can move on to
+type PhaseTransitions map[Phase][]Phase
+
+var HeartsPhases = PhaseTransitions{
+	DealingPhase: {PassingPhase},
+	PassingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+func (t PhaseTransitions) Valid(p Phase) bool {
+	_, ok := t[p]
+	return ok
+}
+
+func (t PhaseTransitions) CanMove(from Phase, to Phase) bool {
+	for _, allowed := range t[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// setPhase moves a game on to the next phase. The game has to be whole by then, since
+// listeners may take a snapshot as soon as they hear of it.
+func (t *Table) setPhase(next Phase) {
+	if !t.phases.CanMove(t.Phase, next) {
+		panic(fmt.Sprintf("cannot move from the %v phase to %v", t.Phase, next))
+	}
+	from := t.Phase
+	t.Phase = next
+	t.emit(PhaseChanged{Round: t.Round, From: from, To: next})
+}
+
+func (t *Table) CurrentPhase() Phase {
+	return t.Phase
+}
@@ -5,7 +5,6 @@ import (
 	"math/rand"
 	"sort"
 	"strings"
-	"time"
 )
 
 type Suit string
@@ -42,10 +41,6 @@ type Card struct {
 
 type Deck []Card
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 func NewDeck() Deck {
 	cards := []Card{}
 	for _, s := range Suits {
@@ -56,8 +51,29 @@ func NewDeck() Deck {
 	return cards
 }
 
-func (d Deck) Shuffle() {
-	rand.Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+func NewShuffledDeck(r *rand.Rand) Deck {
+	d := NewDeck()
+	d.Shuffle(r)
+	return d
+}
+
+func (d Deck) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+}
+
+func (d Deck) Copy() Deck {
+	return append(Deck{}, d...)
+}
+
+// Without returns the deck minus one copy of each of the cards
+func (d Deck) Without(cards Deck) Deck {
+	res := d.Copy()
+	for _, c := range cards {
+		if i := res.Index(c); i != -1 {
+			res = append(res[:i], res[i+1:]...)
+		}
+	}
+	return res
 }
 
 func (d *Deck) Deal() Card {
@@ -79,6 +95,24 @@ func (d Deck) Contains(v CardValue, s Suit) bool {
 	return false
 }
 
+func (d Deck) HasSuit(s Suit) bool {
+	for _, c := range d {
+		if c.Suit == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Deck) Index(c Card) int {
+	for i, dc := range d {
+		if dc == c {
+			return i
+		}
+	}
+	return -1
+}
+
 func (d Deck) Sort() {
 	sort.Slice(d, func(i, j int) bool {
 		if d[i].SuitIndex() != d[j].SuitIndex() {
@@ -106,6 +140,20 @@ func (c Card) ValueIndex() int {
 	return -1
 }
 
+// AceLowIndex is the card's place among the values with aces below twos, as runs are
+// counted in rummy and cribbage
+func (c Card) AceLowIndex() int {
+	return (c.ValueIndex() + 1) % len(CardValues)
+}
+
+// PipValue counts aces as one, face cards as ten and the rest as their number
+func (c Card) PipValue() int {
+	if v := c.AceLowIndex() + 1; v < 10 {
+		return v
+	}
+	return 10
+}
+
 func (d *Deck) Play(c Card) {
 	*d = append(*d, c)
 }
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GameType is everything needed to set up and play one kind of game. Types are
+// registered by name, so new games can be offered without changing what hosts them.
+type GameType struct {
+	Name string `json:"name"`
+	Title string `json:"title"`
+	MinPlayers int `json:"minPlayers"`
+	MaxPlayers int `json:"maxPlayers"`
+	// The settings a host can change, with their defaults
+	Options []Option `json:"options"`
+	// New deals a game for the deciders in seating order, with every option filled in
+	New func(deciders []Decider, seed int64, options Options) (Game, error) `json:"-"`
+	// Restore picks a saved game back up, with the deciders taking the seats with their names
+	Restore func(saved []byte, deciders []Decider) (Game, error) `json:"-"`
+	// CPU decides for computer players
+	CPU Strategy `json:"-"`
+	// Validate checks the options make sense together, beyond what each option allows on
+	// its own. It is optional.
+	Validate func(Options) error `json:"-"`
+	// Record starts recording a game's moves, carrying on from a saved record if there is
+	// one. The game is nil when only the saved record is needed. Record and Replay are
+	// optional, for games that can be replayed.
+	Record func(g Game, saved []byte) (Recording, error) `json:"-"`
+	Replay func(record []byte) (GameReplay, error) `json:"-"`
+}
+
+// Recording keeps the moves of a game as it is played
+type Recording interface {
+	Save() ([]byte, error)
+	Stop()
+}
+
+// GameReplay plays a recorded game back
+type GameReplay interface {
+	// Events passes every event of the game to the listener, in order
+	Events(EventListener) error
+	// ViewsAt returns what each player could see at the position
+	ViewsAt(Position) (map[string]interface{}, error)
+}
+
+type OptionType string
+const (
+	NumberOption = OptionType("number")
+	BoolOption = OptionType("bool")
+	// One of the choices
+	ChoiceOption = OptionType("choice")
+	// Any number of the choices, in order, repeats allowed
+	ListOption = OptionType("list")
+)
+
+// Option is one setting of a game, described well enough for a lobby to offer it
+type Option struct {
+	Key string `json:"key"`
+	Label string `json:"label"`
+	Type OptionType `json:"type"`
+	Default interface{} `json:"default"`
+	Choices []Choice `json:"choices,omitempty"`
+	// Bounds for numbers, where a Max of zero is no limit
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+type Choice struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// Options are the settings of a game by key, as they come from JSON
+type Options map[string]interface{}
+
+// Decode fills in v from the options, matching keys to JSON field names
+func (o Options) Decode(v interface{}) error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+var gameTypes = map[string]*GameType{}
+
+// Register makes the game type available by its name. It panics if the name is taken, so
+// it belongs in an init function.
+func Register(t *GameType) {
+	if _, ok := gameTypes[t.Name]; ok {
+		panic(fmt.Sprintf("game type [%v] registered twice", t.Name))
+	}
+	gameTypes[t.Name] = t
+}
+
+func LookupGameType(name string) (*GameType, bool) {
+	t, ok := gameTypes[name]
+	return t, ok
+}
+
+// GameTypes lists every registered game type by title
+func GameTypes() []*GameType {
+	types := []*GameType{}
+	for _, t := range gameTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Title < types[j].Title })
+	return types
+}
+
+// NewGame checks the players and options suit the game, then deals it. Options left out
+// take their defaults.
+func (t *GameType) NewGame(deciders []Decider, seed int64, options Options) (Game, error) {
+	if err := t.CheckPlayers(len(deciders)); err != nil {
+		return nil, err
+	}
+	if err := t.CheckOptions(options); err != nil {
+		return nil, err
+	}
+	return t.New(deciders, seed, t.WithDefaults(options))
+}
+
+func (t *GameType) CheckPlayers(players int) error {
+	if players < t.MinPlayers {
+		return fmt.Errorf("Too few players, need at least %d", t.MinPlayers)
+	}
+	if players > t.MaxPlayers {
+		return fmt.Errorf("Too many players, can have at most %d", t.MaxPlayers)
+	}
+	return nil
+}
+
+func (t *GameType) Option(key string) *Option {
+	for i := range t.Options {
+		if t.Options[i].Key == key {
+			return &t.Options[i]
+		}
+	}
+	return nil
+}
+
+func (t *GameType) DefaultOptions() Options {
+	return t.WithDefaults(Options{})
+}
+
+// WithDefaults returns a copy of the options with the defaults filled in
+func (t *GameType) WithDefaults(options Options) Options {
+	filled := Options{}
+	for _, opt := range t.Options {
+		filled[opt.Key] = opt.Default
+	}
+	for key, value := range options {
+		filled[key] = value
+	}
+	return filled
+}
+
+// CheckOptions returns an error for the first option that is not allowed
+func (t *GameType) CheckOptions(options Options) error {
+	for key, value := range options {
+		opt := t.Option(key)
+		if opt == nil {
+			return fmt.Errorf("%v has no [%v] option", t.Title, key)
+		}
+		if err := opt.Check(value); err != nil {
+			return err
+		}
+	}
+	if t.Validate != nil {
+		return t.Validate(t.WithDefaults(options))
+	}
+	return nil
+}
+
+// Check returns an error if the value is not allowed for the option
+func (o Option) Check(value interface{}) error {
+	// Values are checked as they would come from JSON, whether they did or not
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch o.Type {
+	case NumberOption:
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%v must be a whole number", o.Label)
+		}
+		if o.Max == 0 && int(n) < o.Min {
+			return fmt.Errorf("%v must be at least %d", o.Label, o.Min)
+		}
+		if o.Max != 0 && (int(n) < o.Min || int(n) > o.Max) {
+			return fmt.Errorf("%v must be from %d to %d", o.Label, o.Min, o.Max)
+		}
+	case BoolOption:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%v must be on or off", o.Label)
+		}
+	case ChoiceOption:
+		if s, ok := v.(string); !ok || !o.hasChoice(s) {
+			return fmt.Errorf("[%v] is not a choice for %v", v, o.Label)
+		}
+	case ListOption:
+		list, ok := v.([]interface{})
+		if !ok && v != nil {
+			return fmt.Errorf("%v must be a list", o.Label)
+		}
+		for _, item := range list {
+			if s, ok := item.(string); !ok || !o.hasChoice(s) {
+				return fmt.Errorf("[%v] is not a choice for %v", item, o.Label)
+			}
+		}
+	}
+	return nil
+}
+
+func (o Option) hasChoice(value string) bool {
+	for _, c := range o.Choices {
+		if c.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGameTypeDefaults(t *testing.T) {
+	for _, gt := range GameTypes() {
+		if found, ok := LookupGameType(gt.Name); !ok || found != gt {
+			t.Errorf("%s is not looked up by its name", gt.Name)
+		}
+		if err := gt.CheckOptions(gt.DefaultOptions()); err != nil {
+			t.Errorf("%s does not allow its own defaults: %v", gt.Name, err)
+		}
+		if err := gt.CheckPlayers(gt.MinPlayers - 1); err == nil {
+			t.Errorf("%s allows %d players", gt.Name, gt.MinPlayers - 1)
+		}
+		if err := gt.CheckPlayers(gt.MaxPlayers + 1); err == nil {
+			t.Errorf("%s allows %d players", gt.Name, gt.MaxPlayers + 1)
+		}
+		deciders := []Decider{}
+		for i := 0; i < gt.MinPlayers; i++ {
+			deciders = append(deciders, NewRandomCPU(fmt.Sprint(i), int64(i)))
+		}
+		if _, err := gt.NewGame(deciders, 1, Options{}); err != nil {
+			t.Errorf("%s can not be dealt with %d players: %v", gt.Name, gt.MinPlayers, err)
+		}
+	}
+	if _, ok := LookupGameType("snap"); ok {
+		t.Errorf("looked up a game that was never registered")
+	}
+}
+
+func TestGameTypeCheckOptions(t *testing.T) {
+	tests := []struct {
+		game *GameType
+		options Options
+		ok bool
+	}{
+		{HeartsType, Options{"jokers": true}, false},
+		{HeartsType, Options{"max_points": 1.5}, false},
+		{HeartsType, Options{"max_points": 0}, false},
+		{HeartsType, Options{"max_points": 50}, true},
+		{HeartsType, Options{"jack_of_diamonds": "yes"}, false},
+		{HeartsType, Options{"moon_scoring": "sideways"}, false},
+		{HeartsType, Options{"moon_scoring": string(MoonSubtractFromSelf)}, true},
+		{HeartsType, Options{"pass_rotation": []string{"up"}}, false},
+		{HeartsType, Options{"pass_rotation": "left"}, false},
+		{HeartsType, Options{"pass_rotation": []string{"left", "no_pass"}}, true},
+		{BlackjackType, Options{"decks": 9}, false},
+		// Options that are fine on their own but not together are caught by the game's Validate
+		{BlackjackType, Options{"max_bet": 5}, false},
+		{HoldemType, Options{"big_blind": 5}, false},
+		{HoldemType, Options{"big_blind": 40}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.game.CheckOptions(tt.options); (err == nil) != tt.ok {
+			t.Errorf("%s with %v: got %v, want allowed %v", tt.game.Name, tt.options, err, tt.ok)
+		}
+	}
+}
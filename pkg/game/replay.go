@@ -0,0 +1,329 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type MoveType string
+const (
+	PassMove = MoveType("pass")
+	PlayMove = MoveType("play")
+	// Timeouts are kept for the log, the move made for the player follows as usual
+	TimeoutMove = MoveType("timeout")
+)
+
+// Move is a single decision made by a player. Passes carry the cards passed, plays carry
+// the one card played.
+type Move struct {
+	Type MoveType `json:"type"`
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Player string `json:"player"`
+	Cards Deck `json:"cards"`
+}
+
+// GameRecord holds everything needed to reconstruct a game: the deals all come from the
+// seed, so only the moves made by the players have to be kept.
+type GameRecord struct {
+	Seed int64 `json:"seed,string"`
+	PlayerOrder []string `json:"playerOrder"`
+	MaxPoints int `json:"maxPoints"`
+	Rules HeartsRules `json:"rules"`
+	Moves []Move `json:"moves"`
+}
+
+// Position is a point in a recorded game: the table after Card cards have been played in
+// trick Trick of round Round. All three count from zero.
+type Position struct {
+	Round int `json:"round"`
+	Trick int `json:"trick"`
+	Card int `json:"card"`
+}
+
+var (
+	ErrPositionNotRecorded = errors.New("position is not in the record")
+	ErrRecordMismatch = errors.New("record does not match the game")
+)
+
+//----------------------------------------------------//
+//--------------------- Recorder ---------------------//
+//----------------------------------------------------//
+
+type Recorder struct {
+	lock sync.Mutex
+	record GameRecord
+	game *HeartsGame
+	subscription int
+}
+
+// NewRecorder starts recording the moves of the game. It should be created before the
+// game is started so that no moves are missed.
+func NewRecorder(g *HeartsGame) *Recorder {
+	r := &Recorder{
+		record: GameRecord{
+			Seed: g.Seed,
+			PlayerOrder: append([]string{}, g.PlayerOrder...),
+			MaxPoints: g.MaxPoints,
+			Rules: g.Rules,
+			Moves: []Move{},
+		},
+		game: g,
+	}
+	r.subscription = g.Subscribe(r.listen)
+	return r
+}
+
+// ResumeRecorder carries on a record made before the game was restored from a snapshot.
+// The game is nil when there is nothing left to record.
+func ResumeRecorder(g *HeartsGame, record GameRecord) *Recorder {
+	record.Moves = append([]Move{}, record.Moves...)
+	r := &Recorder{record: record, game: g}
+	if g != nil {
+		r.subscription = g.Subscribe(r.listen)
+	}
+	return r
+}
+
+func (r *Recorder) listen(e Event) {
+	var m Move
+	switch e := e.(type) {
+	case CardsPassed:
+		m = Move{Type: PassMove, Round: e.Round, Player: e.From, Cards: e.Cards.Copy()}
+	case CardPlayed:
+		m = Move{Type: PlayMove, Round: e.Round, Trick: e.Trick, Player: e.Player, Cards: Deck{e.Card}}
+	case TurnTimedOut:
+		m = Move{Type: TimeoutMove, Round: e.Round, Trick: e.Trick, Player: e.Player, Cards: Deck{}}
+	default:
+		return
+	}
+
+	r.lock.Lock()
+	r.record.Moves = append(r.record.Moves, m)
+	r.lock.Unlock()
+}
+
+// Record returns a copy of everything recorded so far.
+func (r *Recorder) Record() GameRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.record
+	record.Moves = append([]Move{}, r.record.Moves...)
+	return record
+}
+
+func (r *Recorder) Save() ([]byte, error) {
+	return json.Marshal(r.Record())
+}
+
+func (r *Recorder) Stop() {
+	if r.game != nil {
+		r.game.Unsubscribe(r.subscription)
+	}
+}
+
+//----------------------------------------------------//
+//--------------------- Replayer ---------------------//
+//----------------------------------------------------//
+
+type Replayer struct {
+	Record GameRecord
+}
+
+func NewReplayer(record GameRecord) *Replayer {
+	return &Replayer{Record: record}
+}
+
+// Replay plays the whole record back, passing every event to the listener, and returns
+// the game as it was when the record ends.
+func (r *Replayer) Replay(listener EventListener) (*HeartsGame, error) {
+	return r.run(-1, listener)
+}
+
+// Events replays the record for its events alone
+func (r *Replayer) Events(listener EventListener) error {
+	_, err := r.Replay(listener)
+	return err
+}
+
+// ViewsAt returns what each player could see at the given position
+func (r *Replayer) ViewsAt(pos Position) (map[string]interface{}, error) {
+	g, err := r.StateAt(pos)
+	if err != nil {
+		return nil, err
+	}
+	views := map[string]interface{}{}
+	for name, p := range g.Players {
+		views[name] = g.GetDeciderInfo(p.Decider)
+	}
+	return views, nil
+}
+
+// StateAt returns the game as it was at the given position.
+func (r *Replayer) StateAt(pos Position) (*HeartsGame, error) {
+	plays, err := r.playsBefore(pos)
+	if err != nil {
+		return nil, err
+	}
+	return r.run(plays, nil)
+}
+
+// playsBefore counts the cards played in the record up to the position. Only tricks that
+// have at least one recorded card can be stepped into.
+func (r *Replayer) playsBefore(pos Position) (int, error) {
+	plays := 0
+	inTrick := 0
+	for _, m := range r.Record.Moves {
+		if m.Type != PlayMove {
+			continue
+		}
+		if m.Round < pos.Round || m.Round == pos.Round && m.Trick < pos.Trick {
+			plays++
+		} else if m.Round == pos.Round && m.Trick == pos.Trick {
+			inTrick++
+		}
+	}
+	if pos.Card < 0 || pos.Card > inTrick || inTrick == 0 {
+		return 0, ErrPositionNotRecorded
+	}
+	return plays + pos.Card, nil
+}
+
+// run replays the record, stopping before the given number of plays have been made, or
+// at the end of the record if stopAt is negative.
+func (r *Replayer) run(stopAt int, listener EventListener) (*HeartsGame, error) {
+	script := &replayScript{
+		passes: map[string][]Deck{},
+		plays: map[string][]Card{},
+		stopAt: stopAt,
+		stopped: make(chan error, 1),
+		release: make(chan bool),
+	}
+	for _, m := range r.Record.Moves {
+		switch m.Type {
+		case PassMove:
+			script.passes[m.Player] = append(script.passes[m.Player], m.Cards)
+		case PlayMove:
+			if len(m.Cards) != 1 {
+				return nil, ErrRecordMismatch
+			}
+			script.plays[m.Player] = append(script.plays[m.Player], m.Cards[0])
+		}
+	}
+
+	deciders := []Decider{}
+	for _, name := range r.Record.PlayerOrder {
+		deciders = append(deciders, &replayDecider{name, script})
+	}
+	g := NewHeartsGame(deciders, r.Record.MaxPoints, r.Record.Seed, r.Record.Rules)
+	if listener != nil {
+		g.Subscribe(listener)
+	}
+
+	done := g.Start()
+	var err error
+	select {
+	case <-done:
+	case err = <-script.stopped:
+		g.Cancel()
+		<-done
+	}
+	close(script.release)
+
+	return g, err
+}
+
+// replayScript feeds the recorded moves back to the players of a replayed game
+type replayScript struct {
+	lock sync.Mutex
+	passes map[string][]Deck
+	plays map[string][]Card
+	played int
+	stopAt int
+	stopped chan error
+	release chan bool
+}
+
+// outOfMoves stops the replay when a player has nothing left in the record, which is
+// only an error if the replay was meant to stop somewhere before that
+func (s *replayScript) outOfMoves() Answer {
+	if s.stopAt < 0 {
+		return s.stop(nil)
+	}
+	return s.stop(ErrPositionNotRecorded)
+}
+
+func (s *replayScript) stop(err error) Answer {
+	select {
+	case s.stopped <- err:
+	default:
+	}
+	<-s.release
+	return nil
+}
+
+type replayDecider struct {
+	name string
+	script *replayScript
+}
+
+func (d *replayDecider) Decide(q Question, g GameState) Answer {
+	hand := g.GetDeciderInfo(d).(*HeartsGameInfo).Hand
+	s := d.script
+
+	s.lock.Lock()
+	switch q {
+	case PassCardsQuestion:
+		if len(s.passes[d.name]) == 0 {
+			s.lock.Unlock()
+			return s.outOfMoves()
+		}
+		cards := s.passes[d.name][0]
+		s.passes[d.name] = s.passes[d.name][1:]
+		s.lock.Unlock()
+
+		indices := []int{}
+		for _, c := range cards {
+			i := hand.Index(c)
+			if i == -1 {
+				return s.stop(fmt.Errorf("%w: %v cannot pass %v", ErrRecordMismatch, d.name, c))
+			}
+			indices = append(indices, i)
+		}
+		return PassSelection{indices}
+
+	case PlayOnTrickQuestion:
+		if s.played == s.stopAt {
+			s.lock.Unlock()
+			return s.stop(nil)
+		}
+		if len(s.plays[d.name]) == 0 {
+			s.lock.Unlock()
+			return s.outOfMoves()
+		}
+		card := s.plays[d.name][0]
+		s.plays[d.name] = s.plays[d.name][1:]
+		s.played++
+		s.lock.Unlock()
+
+		i := hand.Index(card)
+		if i == -1 {
+			return s.stop(fmt.Errorf("%w: %v cannot play %v", ErrRecordMismatch, d.name, card))
+		}
+		return CardPlay{i}
+	}
+
+	s.lock.Unlock()
+	return nil
+}
+
+func (d *replayDecider) ShowInfo(info string) {}
+
+func (d *replayDecider) GetName() string {
+	return d.name
+}
+
+func (d *replayDecider) Notify(GameState) {}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"sort"
+)
+
+type EndReason string
+const (
+	MaxPointsReachedReason = EndReason("max_points_reached")
+	CancelledReason = EndReason("cancelled")
+)
+
+// Placement is where a player finished. Tied players share a place, and the place after
+// a tie is skipped, so two players tied for first are followed by third.
+type Placement struct {
+	Place int `json:"place"`
+	Player string `json:"player"`
+	Score int `json:"score"`
+}
+
+type GameResult struct {
+	Placements []Placement `json:"placements"`
+	// The points each player scored in each round, in the order the rounds were played
+	ScoreSheet []map[string]int `json:"scoreSheet"`
+	Reason EndReason `json:"reason"`
+}
+
+// Winners returns everyone sharing first place
+func (r GameResult) Winners() []string {
+	winners := []string{}
+	for _, p := range r.Placements {
+		if p.Place == 1 {
+			winners = append(winners, p.Player)
+		}
+	}
+	return winners
+}
+
+// RankPlayers orders players by score, lowest first if lowestWins. Tied players keep
+// their seating order.
+func RankPlayers(playerOrder []string, scores map[string]int, lowestWins bool) []Placement {
+	placements := []Placement{}
+	for _, name := range playerOrder {
+		placements = append(placements, Placement{Player: name, Score: scores[name]})
+	}
+	sort.SliceStable(placements, func(i, j int) bool {
+		if lowestWins {
+			return placements[i].Score < placements[j].Score
+		}
+		return placements[i].Score > placements[j].Score
+	})
+
+	for i := range placements {
+		if i > 0 && placements[i].Score == placements[i-1].Score {
+			placements[i].Place = placements[i-1].Place
+		} else {
+			placements[i].Place = i + 1
+		}
+	}
+	return placements
+}
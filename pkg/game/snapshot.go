@@ -0,0 +1,249 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/thecreatorguy/cards/pkg/jsonyaml"
+)
+
+// Snapshots are everything needed to carry on a game, without the deciders playing it.
+// They are only whole between moves: from an event listener, or once the game is over or
+// cancelled. Each game's snapshot embeds a TableSnapshot for the part kept by its Table.
+type snapshot interface {
+	Validate() error
+	table() *TableSnapshot
+}
+
+// TableSnapshot is who sits where, the seed, the turn timers and where the game is up to
+type TableSnapshot struct {
+	Seed int64 `json:"seed,string"`
+	PlayerOrder []string `json:"playerOrder"`
+	Timer TurnTimer `json:"timer"`
+	Phase Phase `json:"phase"`
+	Round int `json:"round"`
+	ScoreSheet []map[string]int `json:"scoreSheet"`
+}
+
+func (s *TableSnapshot) table() *TableSnapshot {
+	return s
+}
+
+func (t *Table) snapshotTable() TableSnapshot {
+	return TableSnapshot{
+		Seed: t.Seed,
+		PlayerOrder: append([]string{}, t.PlayerOrder...),
+		Timer: t.Timer,
+		Phase: t.Phase,
+		Round: t.Round,
+		ScoreSheet: copyScoreSheet(t.ScoreSheet),
+	}
+}
+
+// restoreTable picks the table up from the snapshot. The seed and seating order are
+// already set by making the game with them.
+func (t *Table) restoreTable(s TableSnapshot) {
+	t.SetTimer(s.Timer)
+	t.Phase = s.Phase
+	t.Round = s.Round
+	t.ScoreSheet = copyScoreSheet(s.ScoreSheet)
+}
+
+// checkTable checks there are between min and max players, seated once each, that the
+// phase is one of the game's, and that the snapshot has a player for every seat and
+// nobody else
+func (s *TableSnapshot) checkTable(min int, max int, phases PhaseTransitions, players int, hasPlayer func(name string) bool) error {
+	if len(s.PlayerOrder) < min || len(s.PlayerOrder) > max {
+		return fmt.Errorf("cannot play with %d players", len(s.PlayerOrder))
+	}
+	seated := map[string]bool{}
+	for _, name := range s.PlayerOrder {
+		if seated[name] {
+			return fmt.Errorf("[%v] is seated twice", name)
+		}
+		seated[name] = true
+		if !hasPlayer(name) {
+			return fmt.Errorf("no hand for [%v]", name)
+		}
+	}
+	if players != len(s.PlayerOrder) {
+		return fmt.Errorf("snapshot has players who are not seated")
+	}
+	if !phases.Valid(s.Phase) {
+		return fmt.Errorf("[%v] is not a phase", s.Phase)
+	}
+	return nil
+}
+
+// seatSnapshot checks the snapshot, and puts the deciders in the seats with their names
+// ready to make the game with
+func seatSnapshot(s snapshot, deciders []Decider) ([]Decider, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return seatByName(s.table().PlayerOrder, deciders)
+}
+
+// parseSnapshot reads a snapshot written as either JSON or YAML into s, and checks it
+func parseSnapshot(data []byte, s snapshot) error {
+	if err := jsonyaml.Unmarshal(data, s); err != nil {
+		return err
+	}
+	return s.Validate()
+}
+
+// HeartsSnapshot is everything needed to carry on a game of hearts. A round being passed
+// is picked up from the start of passing.
+type HeartsSnapshot struct {
+	TableSnapshot
+	MaxPoints int `json:"maxPoints"`
+	Rules HeartsRules `json:"rules"`
+	Trick int `json:"trick"`
+	PassDirection PassDirection `json:"passDirection"`
+	HeartsBroken bool `json:"heartsBroken"`
+	Leader string `json:"leader"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Players map[string]PlayerSnapshot `json:"players"`
+}
+
+type PlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Score int `json:"score"`
+	Taken Deck `json:"taken"`
+	TricksWon int `json:"tricksWon"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *HeartsGame) Snapshot() *HeartsSnapshot {
+	players := map[string]PlayerSnapshot{}
+	for name, p := range g.Players {
+		timeBank := p.TimeBank()
+		players[name] = PlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Score: p.Score,
+			Taken: append(Deck{}, p.taken...),
+			TricksWon: p.tricksWon,
+			TimeBank: timeBank,
+		}
+	}
+
+	return &HeartsSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		MaxPoints: g.MaxPoints,
+		Rules: g.Rules,
+		Trick: g.Trick,
+		PassDirection: g.PassDirection,
+		HeartsBroken: g.HeartsBroken,
+		Leader: g.Leader,
+		CurrentTrick: g.CurrentTrick.Copy(),
+		Tricks: append([]CompletedTrick{}, g.Tricks...),
+		Players: players,
+	}
+}
+
+// RestoreHeartsGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names. Starting it carries on from where the snapshot was taken.
+func RestoreHeartsGame(s *HeartsSnapshot, deciders []Decider) (*HeartsGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewHeartsGame(seated, s.MaxPoints, s.Seed, s.Rules)
+	g.restoreTable(s.TableSnapshot)
+	g.Trick = s.Trick
+	g.PassDirection = s.PassDirection
+	g.HeartsBroken = s.HeartsBroken
+	g.Leader = s.Leader
+	g.CurrentTrick = s.CurrentTrick.Copy()
+	g.Tricks = append([]CompletedTrick{}, s.Tricks...)
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Score = ps.Score
+		p.taken = append(Deck{}, ps.Taken...)
+		p.tricksWon = ps.TricksWon
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// seatByName puts the deciders in the seats with their names
+func seatByName(playerOrder []string, deciders []Decider) ([]Decider, error) {
+	byName := map[string]Decider{}
+	for _, d := range deciders {
+		byName[d.GetName()] = d
+	}
+	if len(byName) != len(playerOrder) || len(deciders) != len(playerOrder) {
+		return nil, fmt.Errorf("snapshot has %d players, but %d deciders were given", len(playerOrder), len(deciders))
+	}
+	seated := []Decider{}
+	for _, name := range playerOrder {
+		d, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no decider for [%v]", name)
+		}
+		seated = append(seated, d)
+	}
+	return seated, nil
+}
+
+func copyScoreSheet(sheet []map[string]int) []map[string]int {
+	copied := []map[string]int{}
+	for _, round := range sheet {
+		points := map[string]int{}
+		for name, p := range round {
+			points[name] = p
+		}
+		copied = append(copied, points)
+	}
+	return copied
+}
+
+// Validate checks the rules can be played with the players seated and have a pass
+// rotation, and that a trick being played has a seated leader and no more cards than
+// players
+func (s *HeartsSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(MinHeartsPlayers, MaxHeartsPlayers, HeartsPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Rules.Validate(len(s.PlayerOrder)); err != nil {
+		return err
+	}
+	if len(s.Rules.PassRotation) == 0 {
+		return fmt.Errorf("no pass rotation")
+	}
+	if s.Phase == PlayingPhase {
+		found := false
+		for _, name := range s.PlayerOrder {
+			found = found || name == s.Leader
+		}
+		if !found {
+			return fmt.Errorf("[%v] is not playing, so cannot lead", s.Leader)
+		}
+		if len(s.CurrentTrick) > len(s.PlayerOrder) {
+			return fmt.Errorf("too many cards on the trick")
+		}
+	}
+	return nil
+}
+
+func (s *HeartsSnapshot) JSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *HeartsSnapshot) YAML() ([]byte, error) {
+	return jsonyaml.Marshal(s)
+}
+
+// ParseSnapshot reads a hearts snapshot written as either JSON or YAML
+func ParseSnapshot(data []byte) (*HeartsSnapshot, error) {
+	s := &HeartsSnapshot{}
+	return s, parseSnapshot(data, s)
+}
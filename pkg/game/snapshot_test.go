@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHeartsRestoreMidRound(t *testing.T) {
+	deciders := func() []Decider {
+		ds := []Decider{}
+		for _, name := range []string{"a", "b", "c", "d"} {
+			ds = append(ds, NewStrategyCPU(name, 1, firstLegal))
+		}
+		return ds
+	}
+
+	// Saves part way through a trick, then plays on to the end
+	g := NewHeartsGame(deciders(), 50, 4, DefaultHeartsRules())
+	var saved []byte
+	events := []string{}
+	after := 0
+	g.Subscribe(func(e Event) {
+		events = append(events, fmt.Sprintf("%T %+v", e, e))
+		if e, ok := e.(CardPlayed); ok && saved == nil && e.Round == 1 && e.Trick == 5 {
+			var err error
+			if saved, err = g.Save(); err != nil {
+				t.Errorf("saving: %v", err)
+			}
+			after = len(events)
+		}
+	})
+	<-g.Start()
+	if saved == nil {
+		t.Fatalf("the game ended before round 1 trick 5")
+	}
+
+	s, err := ParseSnapshot(saved)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	if s.Round != 1 || s.Trick != 5 || len(s.CurrentTrick) != 1 || s.Phase != PlayingPhase {
+		t.Fatalf("saved round %d trick %d with %v played in %v", s.Round, s.Trick, s.CurrentTrick, s.Phase)
+	}
+	restored, err := RestoreHeartsGame(s, deciders())
+	if err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	carriedOn := playHearts(restored)
+	if len(carriedOn) != len(events) - after {
+		t.Fatalf("got %d events after restoring, want %d", len(carriedOn), len(events) - after)
+	}
+	for i, e := range carriedOn {
+		if e != events[after + i] {
+			t.Fatalf("event %d after restoring differs:\n%s\n%s", i, e, events[after + i])
+		}
+	}
+}
+
+func TestHeartsSnapshotValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		change func(s *HeartsSnapshot)
+	}{
+		{"too few players", func(s *HeartsSnapshot) {
+			s.PlayerOrder = s.PlayerOrder[:2]
+			delete(s.Players, "c")
+			delete(s.Players, "d")
+		}},
+		{"seated twice", func(s *HeartsSnapshot) { s.PlayerOrder[1] = "a" }},
+		{"no hand", func(s *HeartsSnapshot) { delete(s.Players, "b") }},
+		{"not seated", func(s *HeartsSnapshot) { s.Players["e"] = PlayerSnapshot{} }},
+		{"not a phase", func(s *HeartsSnapshot) { s.Phase = Phase("bidding") }},
+		{"no pass rotation", func(s *HeartsSnapshot) { s.Rules.PassRotation = nil }},
+		{"leader not seated", func(s *HeartsSnapshot) { s.Phase, s.Leader = PlayingPhase, "e" }},
+		{"too many cards on the trick", func(s *HeartsSnapshot) {
+			s.Phase, s.Leader, s.CurrentTrick = PlayingPhase, "a", cards("2C 3C 4C 5C 6C")
+		}},
+	}
+	for _, tt := range tests {
+		s := NewHeartsGame(heartsCPUs(4, 1), 100, 1, DefaultHeartsRules()).Snapshot()
+		if err := s.Validate(); err != nil {
+			t.Fatalf("a new game is not valid: %v", err)
+		}
+		tt.change(s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: got no error", tt.name)
+		}
+	}
+}
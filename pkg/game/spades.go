@@ -0,0 +1,580 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	SpadesPlayers = 4
+	SpadesHandSize = 13
+	NilBidPoints = 100
+	BlindNilPoints = 200
+)
+
+const (
+	BidQuestion = Question("bid")
+	BlindNilQuestion = Question("blind_nil")
+)
+
+// NilBid is how a nil bid is written in prompts, every other bid being its number
+const NilBid = "nil"
+
+var SpadesPhases = PhaseTransitions{
+	DealingPhase: {BiddingPhase},
+	BiddingPhase: {PlayingPhase},
+	PlayingPhase: {ScoringPhase},
+	ScoringPhase: {DealingPhase, FinishedPhase},
+	FinishedPhase: {},
+}
+
+type SpadesPlayer struct {
+	*Seat
+	Hand Deck
+	Bid int
+	HasBid bool
+	// Blind nil is bid before looking at the hand, so the hand stays hidden until the
+	// player passes on it
+	Blind bool
+	SawHand bool
+	TricksWon int
+}
+
+// SpadesTeam is a partnership of the players sitting across from each other
+type SpadesTeam struct {
+	Name string
+	Players []string
+	Score int
+	Bags int
+}
+
+type SpadesGame struct {
+	Players map[string]*SpadesPlayer
+	Teams []*SpadesTeam
+	Options SpadesOptions
+	SpadesBroken bool
+	Table
+	TrickTaking
+}
+
+type SpadesPlayerInfo struct {
+	NumCards int `json:"numCards"`
+	Score int `json:"score"`
+	Team string `json:"team"`
+	Bid string `json:"bid"`
+	TricksWon int `json:"tricksWon"`
+	Lead bool `json:"lead"`
+	Dealer bool `json:"dealer"`
+	TimeLeftMs *int64 `json:"timeLeftMs,omitempty"`
+	TimeBankMs *int64 `json:"timeBankMs,omitempty"`
+}
+
+type SpadesTeamInfo struct {
+	Name string `json:"name"`
+	Players []string `json:"players"`
+	Score int `json:"score"`
+	Bags int `json:"bags"`
+}
+
+type SpadesGameInfo struct {
+	Name string `json:"name"`
+	PlayerInfo map[string]SpadesPlayerInfo `json:"playerInfo"`
+	PlayerOrder []string `json:"playerOrder"`
+	Teams []SpadesTeamInfo `json:"teams"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	SpadesBroken bool `json:"spadesBroken"`
+	Trick int `json:"trick"`
+	TargetScore int `json:"targetScore"`
+	Hand Deck `json:"hand"`
+	LegalPlays Deck `json:"legalPlays"`
+	Phase Phase `json:"phase"`
+	Paused bool `json:"paused"`
+	Seed string `json:"seed,omitempty"`
+}
+
+func NewSpadesGame(deciders []Decider, seed int64, options SpadesOptions) *SpadesGame {
+	g := &SpadesGame{
+		Players: map[string]*SpadesPlayer{},
+		Teams: []*SpadesTeam{},
+		Options: options,
+	}
+	// Running out of time bids what the hand looks to be worth, and never blind nil
+	g.Init(g, seed, SpadesPhases, DealingPhase, SpadesCPU)
+	g.InitTricks(spadesTricks{PlainTricks: PlainTricks{Trump: Spades}, g: g})
+	g.SeatPlayers(deciders, func(s *Seat) {
+		g.Players[s.GetName()] = &SpadesPlayer{Seat: s}
+	})
+	// Partners sit across from each other
+	for i := 0; i < len(g.PlayerOrder) / 2; i++ {
+		partners := []string{g.PlayerOrder[i], g.PlayerOrder[i + len(g.PlayerOrder) / 2]}
+		g.Teams = append(g.Teams, &SpadesTeam{Name: strings.Join(partners, " & "), Players: partners})
+	}
+	return g
+}
+
+func (g *SpadesGame) GetDeciderInfo(decider Decider) interface{} {
+	playerInfo := map[string]SpadesPlayerInfo{}
+	for name, p := range g.Players {
+		timeLeft, timeBank := p.ClockInfo(&g.Table)
+		playerInfo[name] = SpadesPlayerInfo{
+			NumCards: len(p.Hand),
+			Score: g.Team(name).Score,
+			Team: g.Team(name).Name,
+			Bid: p.BidString(),
+			TricksWon: p.TricksWon,
+			Lead: name == g.Leader,
+			Dealer: name == g.PlayerOrder[g.Dealer()],
+			TimeLeftMs: timeLeft,
+			TimeBankMs: timeBank,
+		}
+	}
+	teams := []SpadesTeamInfo{}
+	for _, t := range g.Teams {
+		teams = append(teams, SpadesTeamInfo{Name: t.Name, Players: t.Players, Score: t.Score, Bags: t.Bags})
+	}
+
+	hand := Deck{}
+	if p := g.Players[decider.GetName()]; p.SawHand {
+		hand = p.Hand
+	}
+
+	return &SpadesGameInfo{
+		Name: decider.GetName(),
+		PlayerInfo: playerInfo,
+		PlayerOrder: g.PlayerOrder,
+		Teams: teams,
+		CurrentTrick: g.CurrentTrick,
+		Tricks: g.Tricks,
+		SpadesBroken: g.SpadesBroken,
+		Trick: g.Trick,
+		TargetScore: g.Options.TargetScore,
+		Hand: hand,
+		LegalPlays: g.LegalPlays(decider.GetName()),
+		Phase: g.Phase,
+		Paused: g.Paused(),
+		Seed: g.ShownSeed(),
+	}
+}
+
+func (sg *SpadesGameInfo) String() string {
+	var b strings.Builder
+	for _, t := range sg.Teams {
+		fmt.Fprintf(&b, "( %v: %v, %v bags ) ", t.Name, t.Score, t.Bags)
+	}
+	b.WriteString("\nBids: ")
+	for _, name := range sg.PlayerOrder {
+		info := sg.PlayerInfo[name]
+		fmt.Fprintf(&b, "( %v: %v, took %v ) ", name, info.Bid, info.TricksWon)
+	}
+	fmt.Fprintf(&b, "\nCurrent Trick: %v\n", sg.CurrentTrick)
+	fmt.Fprintf(&b, "Hand: %v", sg.Hand.NumberedString())
+	return b.String()
+}
+
+// BidString is the bid as players see it, empty until it is made
+func (p *SpadesPlayer) BidString() string {
+	switch {
+	case !p.HasBid:
+		return ""
+	case p.Blind:
+		return "blind " + NilBid
+	case p.Bid == 0:
+		return NilBid
+	}
+	return strconv.Itoa(p.Bid)
+}
+
+func (g *SpadesGame) Prompt(d Decider, q Question) Prompt {
+	name := d.GetName()
+	p := g.Players[name]
+	switch q {
+	case BlindNilQuestion:
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: fmt.Sprintf("Bid blind nil for %d points, or look at your cards?", BlindNilPoints),
+			Options: []string{"blind nil", "look"},
+		}
+	case BidQuestion:
+		options := []string{NilBid}
+		for i := 1; i <= SpadesHandSize; i++ {
+			options = append(options, strconv.Itoa(i))
+		}
+		return Prompt{
+			Question: q,
+			Kind: OptionPrompt,
+			Text: "How many tricks will you take?",
+			Hand: p.Hand.Copy(),
+			Options: options,
+		}
+	case PlayOnTrickQuestion:
+		return Prompt{
+			Question: q,
+			Kind: CardsPrompt,
+			Text: "Play a card",
+			Hand: p.Hand.Copy(),
+			Count: 1,
+			Allowed: g.LegalPlays(name),
+		}
+	}
+	return Prompt{Question: q}
+}
+
+func (g *SpadesGame) Answer(q Question, r Response) Answer {
+	switch q {
+	case BlindNilQuestion:
+		return BlindNil{r.Option == "blind nil"}
+	case BidQuestion:
+		if r.Option == NilBid {
+			return TrickBid{0}
+		}
+		tricks, err := strconv.Atoi(r.Option)
+		if err != nil {
+			return nil
+		}
+		return TrickBid{tricks}
+	case PlayOnTrickQuestion:
+		if len(r.Cards) != 1 {
+			return nil
+		}
+		return CardPlay{r.Cards[0]}
+	}
+	return nil
+}
+
+func (g *SpadesGame) GetPlayer(i int) *SpadesPlayer {
+	return g.Players[g.PlayerOrder[i]]
+}
+
+// Team returns the partnership the player is in
+func (g *SpadesGame) Team(name string) *SpadesTeam {
+	return g.Teams[g.GetOrder(name) % len(g.Teams)]
+}
+
+// Dealer is the seat of the player dealing this round, which moves to the left each round
+func (g *SpadesGame) Dealer() int {
+	return g.Round % g.NumPlayers()
+}
+
+// Winner returns the team with the highest score once a team reaches the target, or
+// another team falls to the losing score, or nil while the game goes on. A tie at the top
+// is played out with another round.
+func (g *SpadesGame) Winner() *SpadesTeam {
+	var best *SpadesTeam
+	tied := false
+	lost := false
+	for _, t := range g.Teams {
+		if best == nil || t.Score > best.Score {
+			best, tied = t, false
+		} else if t.Score == best.Score {
+			tied = true
+		}
+		lost = lost || g.Options.LoseScore > 0 && t.Score <= -g.Options.LoseScore
+	}
+	if best.Score < g.Options.TargetScore && !lost || tied {
+		return nil
+	}
+	return best
+}
+
+func (g *SpadesGame) GameOver() bool {
+	return g.Cancelled || g.Winner() != nil
+}
+
+// Scores gives every player their team's score
+func (g *SpadesGame) Scores() map[string]int {
+	scores := map[string]int{}
+	for name := range g.Players {
+		scores[name] = g.Team(name).Score
+	}
+	return scores
+}
+
+// Result ranks the players, partners sharing their team's place
+func (g *SpadesGame) Result() GameResult {
+	return g.RankedResult(false)
+}
+
+func (g *SpadesGame) PlayRound() bool {
+	if g.Phase == DealingPhase {
+		g.Deal()
+	}
+
+	if g.Phase == BiddingPhase {
+		if cancelled := g.Bid(); cancelled {
+			return true
+		}
+	}
+
+	if g.Phase == PlayingPhase {
+		for g.Trick < SpadesHandSize {
+			if cancelled := g.PlayTrick(); cancelled {
+				return true
+			}
+		}
+		g.setPhase(ScoringPhase)
+	}
+
+	round := g.Round
+	roundPoints := g.ScoreRound()
+	g.ScoreSheet = append(g.ScoreSheet, roundPoints)
+	g.Leader = ""
+	g.Round++
+	if g.Winner() != nil {
+		g.setPhase(FinishedPhase)
+	} else {
+		g.setPhase(DealingPhase)
+	}
+	g.emit(RoundScored{Round: round, RoundPoints: roundPoints, Scores: g.Scores()})
+	g.NotifyAll()
+
+	return false
+}
+
+// Deal hands out the next set of cards
+func (g *SpadesGame) Deal() {
+	g.SpadesBroken = false
+	g.StartTricks()
+	for _, p := range g.Players {
+		p.Hand = Deck{}
+		p.Bid, p.HasBid, p.Blind = 0, false, false
+		p.TricksWon = 0
+	}
+	d := NewDeck()
+	d.Shuffle(g.RoundRand())
+	pi := (g.Dealer() + 1) % g.NumPlayers()
+	for !d.Empty() {
+		g.GetPlayer(pi).Hand = append(g.GetPlayer(pi).Hand, d.Deal())
+		pi = (pi + 1) % g.NumPlayers()
+	}
+	for name, p := range g.Players {
+		p.Hand.Sort()
+		p.SawHand = !g.CanBidBlindNil(name)
+	}
+	g.setPhase(BiddingPhase)
+
+	for i := 0; i < g.NumPlayers(); i++ {
+		g.emit(HandDealt{Round: g.Round, Player: g.PlayerOrder[i], Hand: g.GetPlayer(i).Hand.Copy()})
+	}
+}
+
+// CanBidBlindNil is true when blind nil is played, and the player's team is far enough
+// behind to need it
+func (g *SpadesGame) CanBidBlindNil(name string) bool {
+	if !g.Options.BlindNil {
+		return false
+	}
+	team := g.Team(name)
+	for _, t := range g.Teams {
+		if t != team && t.Score - team.Score >= g.Options.BlindNilBehind {
+			return true
+		}
+	}
+	return false
+}
+
+// Bid goes round the table from the left of the dealer, picking up after any bids already
+// made. The player to the left of the dealer then leads.
+func (g *SpadesGame) Bid() bool {
+	for i := 1; i <= g.NumPlayers(); i++ {
+		p := g.GetPlayer((g.Dealer() + i) % g.NumPlayers())
+		if p.HasBid {
+			continue
+		}
+		if cancelled := p.MakeBid(g); cancelled {
+			return true
+		}
+		g.emit(BidMade{Round: g.Round, Player: p.GetName(), Tricks: p.Bid, Blind: p.Blind})
+		g.NotifyAll()
+	}
+
+	g.Leader = g.PlayerOrder[(g.Dealer() + 1) % g.NumPlayers()]
+	g.setPhase(PlayingPhase)
+	return false
+}
+
+func (p *SpadesPlayer) MakeBid(g *SpadesGame) bool {
+	p.StartTurn(&g.Table)
+	defer p.EndTurn(&g.Table)
+
+	if !p.SawHand {
+		answer, cancelled := g.Ask(p.Seat, BlindNilQuestion, g)
+		if cancelled {
+			return true
+		}
+		if choice, ok := answer.(BlindNil); ok && choice.Blind {
+			p.Bid, p.Blind, p.HasBid = 0, true, true
+			p.SawHand = true
+			return false
+		}
+		p.SawHand = true
+	}
+
+	for {
+		answer, cancelled := g.Ask(p.Seat, BidQuestion, g)
+		if cancelled {
+			return true
+		}
+		bid, violation := ValidateBid(answer, 0, SpadesHandSize)
+		if violation != nil {
+			ShowViolation(p.Decider, violation)
+			continue
+		}
+		p.Bid, p.HasBid = bid, true
+		return false
+	}
+}
+
+func (g *SpadesGame) PlayTrick() bool {
+	play := func(name string) (Card, bool) {
+		p := g.Players[name]
+		return g.PlayFromHand(&g.Table, p.Seat, &p.Hand, g)
+	}
+	played := func(name string, card Card) {
+		if card.Suit == Spades {
+			g.SpadesBroken = true
+		}
+		g.emit(CardPlayed{Round: g.Round, Trick: g.Trick, Player: name, Card: card})
+		g.NotifyAll()
+	}
+	trick := g.Trick
+	won, cancelled := g.PlayCards(SeatsFrom(g.PlayerOrder, g.Leader), play, played)
+	if cancelled {
+		return true
+	}
+
+	g.Players[won.Winner].TricksWon++
+	g.emit(TrickWon{Round: g.Round, Trick: trick, Leader: won.Leader, Winner: won.Winner, Cards: won.Cards})
+	g.NotifyAll()
+
+	return false
+}
+
+// spadesTricks are the rules of a spades trick: spades are trumps, and can't be led until
+// they are broken unless there is nothing else to lead
+type spadesTricks struct {
+	PlainTricks
+	g *SpadesGame
+}
+
+func (r spadesTricks) CheckLead(hand Deck, card Card) *RuleViolation {
+	onlySpades := !hand.HasSuit(Clubs) && !hand.HasSuit(Diamonds) && !hand.HasSuit(Hearts)
+	if card.Suit == Spades && !r.g.SpadesBroken && !onlySpades {
+		return &RuleViolation{
+			Code: SpadesNotBrokenViolation,
+			Message: "Spades not broken, lead with another suit",
+		}
+	}
+	return nil
+}
+
+func (g *SpadesGame) LegalPlays(name string) Deck {
+	p, ok := g.Players[name]
+	if !ok || p.Asking() != PlayOnTrickQuestion {
+		return Deck{}
+	}
+	return g.LegalCards(p.Hand)
+}
+
+// ScoreRound adds each team's points for the round to its score, and returns how much
+// each player's team score changed. A team makes its contract, the bids of its players
+// who did not bid nil, with the tricks those players took. Tricks over the contract and
+// tricks taken by nil bidders are bags, and every BagLimit bags costs BagPenalty points.
+func (g *SpadesGame) ScoreRound() map[string]int {
+	roundPoints := map[string]int{}
+	for _, t := range g.Teams {
+		points, contract, tricks, bags := 0, 0, 0, 0
+		for _, name := range t.Players {
+			p := g.Players[name]
+			if p.Bid > 0 {
+				contract += p.Bid
+				tricks += p.TricksWon
+				continue
+			}
+
+			nilPoints := NilBidPoints
+			if p.Blind {
+				nilPoints = BlindNilPoints
+			}
+			if p.TricksWon == 0 {
+				points += nilPoints
+			} else {
+				points -= nilPoints
+				bags += p.TricksWon
+			}
+		}
+
+		if contract > 0 {
+			if tricks >= contract {
+				points += 10 * contract
+				bags += tricks - contract
+			} else {
+				points -= 10 * contract
+			}
+		}
+
+		points += bags
+		t.Bags += bags
+		for g.Options.BagLimit > 0 && t.Bags >= g.Options.BagLimit {
+			t.Bags -= g.Options.BagLimit
+			points -= g.Options.BagPenalty
+		}
+		t.Score += points
+		for _, name := range t.Players {
+			roundPoints[name] = points
+		}
+	}
+	return roundPoints
+}
+
+// SpadesCPU bids by counting the cards likely to take tricks, then plays at random
+func SpadesCPU(r *rand.Rand, d Decider, q Question, g GameState) Answer {
+	sg, ok := g.(*SpadesGame)
+	if !ok {
+		return RandomDecision(r, d, q, g)
+	}
+	switch q {
+	case BlindNilQuestion:
+		return BlindNil{false}
+	case BidQuestion:
+		return TrickBid{EstimateSpadesTricks(sg.Players[d.GetName()].Hand)}
+	}
+	return RandomDecision(r, d, q, g)
+}
+
+// EstimateSpadesTricks counts aces, kings with another card to guard them, high spades and
+// long spades. It never estimates nil, which is left to people.
+func EstimateSpadesTricks(hand Deck) int {
+	tricks := 0
+	for _, s := range Suits {
+		count := 0
+		for _, c := range hand {
+			if c.Suit == s {
+				count++
+			}
+		}
+		for _, c := range hand {
+			if c.Suit != s {
+				continue
+			}
+			switch {
+			case c.Value == Ace:
+				tricks++
+			case c.Value == King && count >= 2:
+				tricks++
+			case s == Spades && c.Value == Queen && count >= 3:
+				tricks++
+			}
+		}
+		if s == Spades && count > 3 {
+			tricks += count - 3
+		}
+	}
+	if tricks < 1 {
+		tricks = 1
+	}
+	return tricks
+}
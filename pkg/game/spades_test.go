@@ -0,0 +1,76 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// spadesBid is a player's bid and the tricks they took
+type spadesBid struct {
+	bid int
+	blind bool
+	took int
+}
+
+func TestSpadesScoreRound(t *testing.T) {
+	tests := []struct {
+		name string
+		a, c spadesBid
+		bags int
+		wantPoints int
+		wantBags int
+	}{
+		{"contract made", spadesBid{3, false, 3}, spadesBid{2, false, 2}, 0, 50, 0},
+		{"overtricks are bags", spadesBid{3, false, 4}, spadesBid{2, false, 3}, 0, 52, 2},
+		{"set", spadesBid{4, false, 2}, spadesBid{3, false, 3}, 0, -70, 0},
+		{"nil made", spadesBid{0, false, 0}, spadesBid{4, false, 5}, 0, 141, 1},
+		// The nil bidder's tricks do not count towards their partner's bid
+		{"nil set", spadesBid{0, false, 2}, spadesBid{4, false, 4}, 0, -58, 2},
+		{"nil set and partner set", spadesBid{0, false, 3}, spadesBid{4, false, 3}, 0, -137, 3},
+		{"blind nil made", spadesBid{0, true, 0}, spadesBid{3, false, 3}, 0, 230, 0},
+		{"blind nil set", spadesBid{0, true, 1}, spadesBid{3, false, 3}, 0, -169, 1},
+		{"both nil made", spadesBid{0, false, 0}, spadesBid{0, false, 0}, 0, 200, 0},
+		{"bag limit reached", spadesBid{3, false, 5}, spadesBid{2, false, 3}, 8, -47, 1},
+		{"bag limit reached exactly", spadesBid{3, false, 4}, spadesBid{2, false, 3}, 8, -48, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+			g := NewSpadesGame(deciders, 1, SpadesOptions{TargetScore: 500, BagLimit: 10, BagPenalty: 100})
+			for name, b := range map[string]spadesBid{"a": tt.a, "c": tt.c, "b": {1, false, 1}, "d": {1, false, 1}} {
+				p := g.Players[name]
+				p.Bid, p.Blind, p.HasBid, p.TricksWon = b.bid, b.blind, true, b.took
+			}
+			team := g.Team("a")
+			team.Bags = tt.bags
+
+			points := g.ScoreRound()
+			if points["a"] != tt.wantPoints || points["c"] != tt.wantPoints {
+				t.Errorf("got %d points, want %d", points["a"], tt.wantPoints)
+			}
+			if team.Score != tt.wantPoints || team.Bags != tt.wantBags {
+				t.Errorf("team has %d points and %d bags, want %d and %d", team.Score, team.Bags, tt.wantPoints, tt.wantBags)
+			}
+			if points["b"] != 20 || points["d"] != 20 {
+				t.Errorf("the other team got %d points, want 20", points["b"])
+			}
+		})
+	}
+}
+
+func TestSpadesTimeoutNeverBidsNil(t *testing.T) {
+	deciders := []Decider{NewRandomCPU("a", 1), NewRandomCPU("b", 2), NewRandomCPU("c", 3), NewRandomCPU("d", 4)}
+	g := NewSpadesGame(deciders, 1, SpadesOptions{TargetScore: 500, BlindNil: true, BagLimit: 10, BagPenalty: 100})
+	r := rand.New(rand.NewSource(1))
+	for seed := int64(0); seed < 50; seed++ {
+		deck := NewDeck()
+		deck.Shuffle(rand.New(rand.NewSource(seed)))
+		g.Players["a"].Hand = deck[:13]
+		if answer := g.Fallback(r, deciders[0], BlindNilQuestion, g); answer != (BlindNil{false}) {
+			t.Errorf("timing out answered %+v to blind nil", answer)
+		}
+		if answer, ok := g.Fallback(r, deciders[0], BidQuestion, g).(TrickBid); !ok || answer.Tricks < 1 {
+			t.Errorf("timing out bid %+v with %v", answer, g.Players["a"].Hand)
+		}
+	}
+}
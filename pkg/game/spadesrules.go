@@ -0,0 +1,69 @@
+package game
+
+import (
+	"fmt"
+)
+
+type SpadesOptions struct {
+	// The score a team has to reach to win
+	TargetScore int `json:"target_score"`
+	// A team falling to minus this many points loses, or never if zero
+	LoseScore int `json:"lose_score"`
+	// Whether blind nil can be bid, and how far behind a team has to be to bid it
+	BlindNil bool `json:"blind_nil"`
+	BlindNilBehind int `json:"blind_nil_behind"`
+	// Every BagLimit bags a team takes costs it BagPenalty points, no limit if zero
+	BagLimit int `json:"bag_limit"`
+	BagPenalty int `json:"bag_penalty"`
+}
+
+func (o SpadesOptions) Validate() error {
+	if o.TargetScore < 1 {
+		return fmt.Errorf("Target score must be at least 1")
+	}
+	if o.LoseScore < 0 || o.BlindNilBehind < 0 || o.BagLimit < 0 || o.BagPenalty < 0 {
+		return fmt.Errorf("Losing score, blind nil and bag settings can not be negative")
+	}
+	return nil
+}
+
+var SpadesType = &GameType{
+	Name: "spades",
+	Title: "Spades",
+	MinPlayers: SpadesPlayers,
+	MaxPlayers: SpadesPlayers,
+	Options: []Option{
+		{Key: "target_score", Label: "Target score", Type: NumberOption, Default: 500, Min: 1},
+		{Key: "lose_score", Label: "Lose at minus (0 to play on)", Type: NumberOption, Default: 200},
+		{Key: "blind_nil", Label: "Blind nil allowed", Type: BoolOption, Default: true},
+		{Key: "blind_nil_behind", Label: "Blind nil only when behind by", Type: NumberOption, Default: 100},
+		{Key: "bag_limit", Label: "Bags before a penalty (0 for none)", Type: NumberOption, Default: 10},
+		{Key: "bag_penalty", Label: "Bag penalty", Type: NumberOption, Default: 100},
+	},
+	New: func(deciders []Decider, seed int64, options Options) (Game, error) {
+		var o SpadesOptions
+		if err := options.Decode(&o); err != nil {
+			return nil, err
+		}
+		return NewSpadesGame(deciders, seed, o), nil
+	},
+	Restore: func(saved []byte, deciders []Decider) (Game, error) {
+		s, err := ParseSpadesSnapshot(saved)
+		if err != nil {
+			return nil, err
+		}
+		return RestoreSpadesGame(s, deciders)
+	},
+	CPU: SpadesCPU,
+	Validate: func(options Options) error {
+		var o SpadesOptions
+		if err := options.Decode(&o); err != nil {
+			return err
+		}
+		return o.Validate()
+	},
+}
+
+func init() {
+	Register(SpadesType)
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SpadesSnapshot is everything needed to carry on a game of spades
+type SpadesSnapshot struct {
+	TableSnapshot
+	Options SpadesOptions `json:"options"`
+	Trick int `json:"trick"`
+	Leader string `json:"leader"`
+	SpadesBroken bool `json:"spadesBroken"`
+	CurrentTrick Deck `json:"currentTrick"`
+	Tricks []CompletedTrick `json:"tricks"`
+	Teams []SpadesTeamSnapshot `json:"teams"`
+	Players map[string]SpadesPlayerSnapshot `json:"players"`
+}
+
+type SpadesTeamSnapshot struct {
+	Score int `json:"score"`
+	Bags int `json:"bags"`
+}
+
+type SpadesPlayerSnapshot struct {
+	Hand Deck `json:"hand"`
+	Bid int `json:"bid"`
+	HasBid bool `json:"hasBid"`
+	Blind bool `json:"blind"`
+	SawHand bool `json:"sawHand"`
+	TricksWon int `json:"tricksWon"`
+	TimeBank time.Duration `json:"timeBank"`
+}
+
+func (g *SpadesGame) Snapshot() *SpadesSnapshot {
+	players := map[string]SpadesPlayerSnapshot{}
+	for name, p := range g.Players {
+		players[name] = SpadesPlayerSnapshot{
+			Hand: p.Hand.Copy(),
+			Bid: p.Bid,
+			HasBid: p.HasBid,
+			Blind: p.Blind,
+			SawHand: p.SawHand,
+			TricksWon: p.TricksWon,
+			TimeBank: p.TimeBank(),
+		}
+	}
+	teams := []SpadesTeamSnapshot{}
+	for _, t := range g.Teams {
+		teams = append(teams, SpadesTeamSnapshot{Score: t.Score, Bags: t.Bags})
+	}
+
+	return &SpadesSnapshot{
+		TableSnapshot: g.snapshotTable(),
+		Options: g.Options,
+		Trick: g.Trick,
+		Leader: g.Leader,
+		SpadesBroken: g.SpadesBroken,
+		CurrentTrick: g.CurrentTrick.Copy(),
+		Tricks: append([]CompletedTrick{}, g.Tricks...),
+		Teams: teams,
+		Players: players,
+	}
+}
+
+func (g *SpadesGame) Save() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// RestoreSpadesGame sets up a game as it was in the snapshot, with the deciders taking the
+// seats with their names
+func RestoreSpadesGame(s *SpadesSnapshot, deciders []Decider) (*SpadesGame, error) {
+	seated, err := seatSnapshot(s, deciders)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewSpadesGame(seated, s.Seed, s.Options)
+	g.restoreTable(s.TableSnapshot)
+	g.Trick = s.Trick
+	g.Leader = s.Leader
+	g.SpadesBroken = s.SpadesBroken
+	g.CurrentTrick = s.CurrentTrick.Copy()
+	g.Tricks = append([]CompletedTrick{}, s.Tricks...)
+	for i, ts := range s.Teams {
+		g.Teams[i].Score = ts.Score
+		g.Teams[i].Bags = ts.Bags
+	}
+	for name, ps := range s.Players {
+		p := g.Players[name]
+		p.Hand = ps.Hand.Copy()
+		p.Bid = ps.Bid
+		p.HasBid = ps.HasBid
+		p.Blind = ps.Blind
+		p.SawHand = ps.SawHand
+		p.TricksWon = ps.TricksWon
+		p.SetTimeBank(ps.TimeBank)
+	}
+	return g, nil
+}
+
+// Validate checks there are four players split into two teams, and that a trick being played has no more cards than players
+func (s *SpadesSnapshot) Validate() error {
+	hasPlayer := func(name string) bool {
+		_, ok := s.Players[name]
+		return ok
+	}
+	if err := s.checkTable(SpadesPlayers, SpadesPlayers, SpadesPhases, len(s.Players), hasPlayer); err != nil {
+		return err
+	}
+	if err := s.Options.Validate(); err != nil {
+		return err
+	}
+	if len(s.Teams) != SpadesPlayers / 2 {
+		return fmt.Errorf("snapshot has %d teams", len(s.Teams))
+	}
+	if s.Phase == PlayingPhase && len(s.CurrentTrick) > len(s.PlayerOrder) {
+		return fmt.Errorf("too many cards on the trick")
+	}
+	return nil
+}
+
+func ParseSpadesSnapshot(data []byte) (*SpadesSnapshot, error) {
+	s := &SpadesSnapshot{}
+	return s, parseSnapshot(data, s)
+}
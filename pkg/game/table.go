@@ -0,0 +1,254 @@
+package game
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RoundGame is a game its Table plays out a round at a time
+type RoundGame interface {
+	GameState
+	GameOver() bool
+	// PlayRound plays the round out from whichever phase it is in, so a restored game
+	// carries on from where it was saved. It returns true if the game was cancelled.
+	PlayRound() bool
+	Scores() map[string]int
+	Result() GameResult
+}
+
+// Table is what every game needs to seat its deciders and ask them for moves: the seating
+// order, seeding, events, phases, cancelling, pausing and turn timers. Games embed it and
+// call Init before use.
+type Table struct {
+	eventStream
+	pauser
+	Seed int64
+	PlayerOrder []string
+	Round int
+	Phase Phase
+	ScoreSheet []map[string]int
+	phases PhaseTransitions
+	game RoundGame
+	Timer TurnTimer
+	// Answers for players who run out of time
+	Fallback Strategy
+	Cancelled bool
+	cancelled chan bool
+	cancelOnce sync.Once
+	seats []*Seat
+}
+
+// Seat is a decider at a table, along with the clock for their decisions
+type Seat struct {
+	Decider
+	// Guards the question being asked and the clock, which other goroutines read to show
+	// the table
+	lock sync.Mutex
+	asking Question
+	// Counts the questions asked, so a decision that comes back late can't clear a newer one
+	asked int
+	timeBank time.Duration
+	turnStarted time.Time
+	deadline time.Time
+	fallbackRand *rand.Rand
+}
+
+// Init sets the table up for the game, which starts in the first phase and moves through
+// the rest by the transitions given
+func (t *Table) Init(game RoundGame, seed int64, phases PhaseTransitions, first Phase, fallback Strategy) {
+	t.game = game
+	t.Seed = seed
+	t.PlayerOrder = []string{}
+	t.ScoreSheet = []map[string]int{}
+	t.phases = phases
+	t.Phase = first
+	t.Fallback = fallback
+	t.cancelled = make(chan bool)
+	t.pausing = make(chan bool)
+	t.resuming = make(chan bool)
+	close(t.resuming)
+}
+
+// SeatPlayers seats the deciders in order, calling sit with each seat so the game can set
+// up its player. The moves made for players who run out of time are seeded by where they
+// sit.
+func (t *Table) SeatPlayers(deciders []Decider, sit func(s *Seat)) {
+	for i, d := range deciders {
+		s := &Seat{Decider: d, fallbackRand: rand.New(rand.NewSource(t.Seed ^ int64(i + 1) << 32))}
+		t.seats = append(t.seats, s)
+		t.PlayerOrder = append(t.PlayerOrder, d.GetName())
+		sit(s)
+	}
+}
+
+func (t *Table) NumPlayers() int {
+	return len(t.PlayerOrder)
+}
+
+func (t *Table) GetOrder(name string) int {
+	for i, n := range t.PlayerOrder {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// RoundRand returns the random source for dealing the current round. Every round gets
+// its own source derived from the game seed, so any deal can be reproduced on its own.
+func (t *Table) RoundRand() *rand.Rand {
+	return t.SeededRand(int64(t.Round))
+}
+
+// SeededRand returns the random source n on from the game seed, for games that shuffle
+// more or less often than once a round
+func (t *Table) SeededRand(n int64) *rand.Rand {
+	return rand.New(rand.NewSource(t.Seed + n))
+}
+
+func (t *Table) Cancel() {
+	t.cancelOnce.Do(func() {
+		t.Cancelled = true
+		close(t.cancelled)
+	})
+}
+
+// Start plays the game in the background until it is over or cancelled, then lets everyone
+// see how it ended
+func (t *Table) Start() chan GameResult {
+	completeChannel := make(chan GameResult, 1)
+	go func() {
+		for !t.game.GameOver() {
+			if t.game.PlayRound() {
+				break
+			}
+		}
+		t.NotifyAll()
+		result := t.game.Result()
+		t.emit(GameOver{Result: result})
+		completeChannel <- result
+	}()
+
+	return completeChannel
+}
+
+func (t *Table) NotifyAll() {
+	for _, s := range t.seats {
+		s.Decider.Notify(t.game)
+	}
+}
+
+// RankedResult ranks the players by their scores as things stand, lowest first if
+// lowestWins
+func (t *Table) RankedResult(lowestWins bool) GameResult {
+	return GameResult{
+		Placements: RankPlayers(t.PlayerOrder, t.game.Scores(), lowestWins),
+		ScoreSheet: t.ScoreSheet,
+		Reason: t.EndReason(),
+	}
+}
+
+func (t *Table) EndReason() EndReason {
+	if t.Cancelled {
+		return CancelledReason
+	}
+	return MaxPointsReachedReason
+}
+
+// ShownSeed is the seed as players are shown it. The seed reveals every deal, so it is
+// only shown once the game is over.
+func (t *Table) ShownSeed() string {
+	if !t.game.GameOver() {
+		return ""
+	}
+	return strconv.FormatInt(t.Seed, 10)
+}
+
+// Asking is the question the seat is being asked right now, if any
+func (s *Seat) Asking() Question {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.asking
+}
+
+func (s *Seat) startAsking(q Question) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.asking = q
+	s.asked++
+	return s.asked
+}
+
+// stopAsking clears the question, as long as it is still the one asked
+func (s *Seat) stopAsking(asked int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.asked == asked {
+		s.asking = ""
+	}
+}
+
+// Ask gets an answer from the seat's decider, returning true if the game was cancelled
+// first. Answers are held back while the game is paused, and if time runs out the table's
+// fallback answers instead.
+func (t *Table) Ask(s *Seat, q Question, g GameState) (Answer, bool) {
+	if t.waitWhilePaused() {
+		return nil, true
+	}
+	asked := s.startAsking(q)
+	defer s.stopAsking(asked)
+
+	// Buffered so an abandoned decision does not leave its goroutine blocked forever. The
+	// question is cleared as soon as it is answered, so the answered question's legal moves
+	// are not shown while the game takes the answer.
+	ansChan := make(chan Answer, 1)
+	go func() {
+		answer := s.Decider.Decide(q, g)
+		s.stopAsking(asked)
+		ansChan <- answer
+	}()
+
+	for {
+		answer, answered, cancelled := t.awaitAnswer(s, q, g, ansChan)
+		if cancelled {
+			interrupt(s.Decider)
+			return nil, true
+		}
+		if answered {
+			return answer, false
+		}
+	}
+}
+
+// awaitAnswer waits for the answer until time runs out, or until the game is paused, in
+// which case nothing is answered yet and it should be called again to carry on waiting
+func (t *Table) awaitAnswer(s *Seat, q Question, g GameState, ansChan chan Answer) (Answer, bool, bool) {
+	// The deadline moves back by however long the game was paused
+	var expired <-chan time.Time
+	if deadline, ok := s.Deadline(); ok {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expired = timer.C
+	}
+	pausing, _ := t.pauseSignals()
+
+	select {
+	case answer := <-ansChan:
+		// Answers made while paused are held until the game carries on
+		if t.waitWhilePaused() {
+			return nil, false, true
+		}
+		return answer, true, false
+	case <-expired:
+		if t.Paused() {
+			return nil, false, t.waitWhilePaused()
+		}
+		return t.timedOut(s, q, g, ansChan), true, false
+	case <-pausing:
+		return nil, false, t.waitWhilePaused()
+	case <-t.cancelled:
+		return nil, false, true
+	}
+}
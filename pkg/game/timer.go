@@ -0,0 +1,145 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// TurnTimer limits how long players can take to decide. Both limits are optional; when
+// both are set, whichever runs out first ends the turn.
+type TurnTimer struct {
+	// How long each decision may take, zero for no limit
+	PerDecision time.Duration `json:"perDecision"`
+	// Thinking time each player has for the whole game, like a chess clock, zero for none
+	Bank time.Duration `json:"bank"`
+}
+
+func (t TurnTimer) Enabled() bool {
+	return t.PerDecision > 0 || t.Bank > 0
+}
+
+// Strategy picks an answer for a decider, like a CPU player would
+type Strategy func(r *rand.Rand, d Decider, q Question, g GameState) Answer
+
+// Interruptible deciders are told when the game stops waiting on their decision, because
+// time ran out or the game ended, so they can stop waiting on their player too.
+type Interruptible interface {
+	Interrupt()
+}
+
+func interrupt(d Decider) {
+	if i, ok := d.(Interruptible); ok {
+		i.Interrupt()
+	}
+}
+
+func (t *Table) SetTimer(timer TurnTimer) {
+	t.Timer = timer
+	for _, s := range t.seats {
+		s.SetTimeBank(timer.Bank)
+	}
+}
+
+func (s *Seat) TimeBank() time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.timeBank
+}
+
+func (s *Seat) SetTimeBank(bank time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.timeBank = bank
+}
+
+// StartTurn starts the seat's clock for a decision, which covers every attempt at it
+func (s *Seat) StartTurn(t *Table) {
+	now := t.clockNow()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.turnStarted = now
+	s.deadline = time.Time{}
+	if !t.Timer.Enabled() {
+		return
+	}
+
+	limit := t.Timer.PerDecision
+	if t.Timer.Bank > 0 && (limit == 0 || s.timeBank < limit) {
+		limit = s.timeBank
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	s.deadline = s.turnStarted.Add(limit)
+}
+
+// EndTurn stops the clock and takes the time used out of the seat's bank
+func (s *Seat) EndTurn(t *Table) {
+	now := t.clockNow()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if t.Timer.Bank > 0 {
+		s.timeBank -= now.Sub(s.turnStarted)
+		if s.timeBank < 0 {
+			s.timeBank = 0
+		}
+	}
+	s.deadline = time.Time{}
+	s.turnStarted = time.Time{}
+}
+
+// Deadline returns when the current decision runs out of time, if it ever does
+func (s *Seat) Deadline() (time.Time, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.deadline, !s.deadline.IsZero()
+}
+
+// ClockInfo gives the time left for the current decision and in the bank, in
+// milliseconds, for showing to players. Either is nil when there is no such limit.
+func (s *Seat) ClockInfo(t *Table) (timeLeft *int64, timeBank *int64) {
+	now := t.clockNow()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.deadline.IsZero() {
+		left := s.deadline.Sub(now).Milliseconds()
+		if left < 0 {
+			left = 0
+		}
+		timeLeft = &left
+	}
+	if t.Timer.Bank > 0 {
+		bank := s.timeBank
+		if !s.turnStarted.IsZero() {
+			bank -= now.Sub(s.turnStarted)
+		}
+		if bank < 0 {
+			bank = 0
+		}
+		ms := bank.Milliseconds()
+		timeBank = &ms
+	}
+	return timeLeft, timeBank
+}
+
+// timedOut answers for a seat whose time ran out, unless their answer turned up at the
+// last moment
+func (t *Table) timedOut(s *Seat, q Question, g GameState, ansChan chan Answer) Answer {
+	select {
+	case answer := <-ansChan:
+		return answer
+	default:
+	}
+
+	interrupt(s.Decider)
+	e := TurnTimedOut{Round: t.Round, Player: s.Decider.GetName(), Question: q}
+	if tricks, ok := t.game.(trickCounter); ok {
+		e.Trick = tricks.trickNumber()
+	}
+	t.emit(e)
+	s.Decider.ShowInfo("Ran out of time, a move was made for you")
+	return t.Fallback(s.fallbackRand, s.Decider, q, g)
+}
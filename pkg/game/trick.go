@@ -0,0 +1,228 @@
+package game
+
+// TrickRules are what a trick-taking game decides for itself: trumps, how cards rank, what
+// can be led or thrown and what a trick is worth. Following suit when able, and the best
+// card of the suit led taking the trick unless it is trumped, are the same in every game
+// and left to TrickTaking.
+type TrickRules interface {
+	// TrumpSuit beats every other suit, or is empty when nothing is trumps
+	TrumpSuit() Suit
+	// EffectiveSuit is the suit the card follows and wins as, which is usually its own
+	EffectiveSuit(c Card) Suit
+	// CardRank orders the cards of a suit, the highest taking the trick
+	CardRank(c Card) int
+	// CheckLead returns the rule broken by leading the card from the hand, or nil
+	CheckLead(hand Deck, card Card) *RuleViolation
+	// CheckFollow returns the rule broken by playing the card from the hand onto a trick
+	// that has been led, once it has followed suit if it could, or nil
+	CheckFollow(hand Deck, card Card) *RuleViolation
+	// TrickPoints is what the cards in a trick are worth to whoever takes them
+	TrickPoints(cards Deck) int
+}
+
+// PlainTricks are the rules of a trick with nothing special about it: the trump suit, if
+// any, beats the rest, cards rank two to ace, any card can be led or thrown and tricks are
+// worth nothing. Games embed it and override what they do differently.
+type PlainTricks struct {
+	Trump Suit
+}
+
+func (r PlainTricks) TrumpSuit() Suit {
+	return r.Trump
+}
+
+func (r PlainTricks) EffectiveSuit(c Card) Suit {
+	return c.Suit
+}
+
+func (r PlainTricks) CardRank(c Card) int {
+	return c.ValueIndex()
+}
+
+func (r PlainTricks) CheckLead(hand Deck, card Card) *RuleViolation {
+	return nil
+}
+
+func (r PlainTricks) CheckFollow(hand Deck, card Card) *RuleViolation {
+	return nil
+}
+
+func (r PlainTricks) TrickPoints(cards Deck) int {
+	return 0
+}
+
+// CompletedTrick is a trick that has been won, with the cards in the order they were
+// played and who played each one
+type CompletedTrick struct {
+	Leader string `json:"leader"`
+	Winner string `json:"winner"`
+	Cards Deck `json:"cards"`
+	Players []string `json:"players"`
+	Points int `json:"points"`
+}
+
+// TrickTaking is the play of a hand of tricks under a game's TrickRules. Games embed it
+// alongside their Table and call InitTricks before use.
+type TrickTaking struct {
+	Leader string
+	Trick int
+	CurrentTrick Deck
+	Tricks []CompletedTrick
+	trickRules TrickRules
+}
+
+// trickCounter is a game played in tricks, so what happens can be placed in its trick
+type trickCounter interface {
+	trickNumber() int
+}
+
+func (t *TrickTaking) trickNumber() int {
+	return t.Trick
+}
+
+func (t *TrickTaking) InitTricks(rules TrickRules) {
+	t.trickRules = rules
+}
+
+// StartTricks clears away the last hand's tricks before a new one is played
+func (t *TrickTaking) StartTricks() {
+	t.Trick = 0
+	t.Tricks = []CompletedTrick{}
+}
+
+// LeadSuit is the suit that has to be followed, or nil if nothing has been led yet
+func (t *TrickTaking) LeadSuit() *Suit {
+	if len(t.CurrentTrick) == 0 {
+		return nil
+	}
+	s := t.trickRules.EffectiveSuit(t.CurrentTrick[0])
+	return &s
+}
+
+// HasSuit is whether the hand holds a card that follows the suit
+func (t *TrickTaking) HasSuit(hand Deck, s Suit) bool {
+	for _, c := range hand {
+		if t.trickRules.EffectiveSuit(c) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPlay returns the rule broken by playing the card from the hand onto the current
+// trick, or nil if it can be played
+func (t *TrickTaking) CheckPlay(hand Deck, card Card) *RuleViolation {
+	leadSuit := t.LeadSuit()
+	if leadSuit == nil {
+		return t.trickRules.CheckLead(hand, card)
+	}
+	if t.trickRules.EffectiveSuit(card) != *leadSuit && t.HasSuit(hand, *leadSuit) {
+		return &RuleViolation{
+			Code: MustFollowSuitViolation,
+			Message: "Must play the lead suit: " + string(*leadSuit),
+			Suit: *leadSuit,
+		}
+	}
+	return t.trickRules.CheckFollow(hand, card)
+}
+
+// LegalCards returns the cards in the hand that can be played onto the current trick
+func (t *TrickTaking) LegalCards(hand Deck) Deck {
+	legal := Deck{}
+	for _, c := range hand {
+		if t.CheckPlay(hand, c) == nil {
+			legal = append(legal, c)
+		}
+	}
+	return legal
+}
+
+// TrickWinner returns the index of the highest trump in the trick, or the highest card of
+// the suit led if no trumps were played
+func (t *TrickTaking) TrickWinner(trick Deck) int {
+	trump := t.trickRules.TrumpSuit()
+	winner := 0
+	for i, c := range trick {
+		best := trick[winner]
+		suit, bestSuit := t.trickRules.EffectiveSuit(c), t.trickRules.EffectiveSuit(best)
+		switch {
+		case suit == bestSuit && t.trickRules.CardRank(c) > t.trickRules.CardRank(best):
+			winner = i
+		case suit == trump && bestSuit != trump:
+			winner = i
+		}
+	}
+	return winner
+}
+
+// PlayFromHand asks the seat for a card from the hand until they pick one that can be
+// played onto the trick, and takes it out of the hand
+func (t *TrickTaking) PlayFromHand(table *Table, s *Seat, hand *Deck, g GameState) (Card, bool) {
+	s.StartTurn(table)
+	defer s.EndTurn(table)
+	for {
+		answer, cancelled := table.Ask(s, PlayOnTrickQuestion, g)
+		if cancelled {
+			return Card{}, true
+		}
+
+		index, violation := ValidateIndex(*hand, answer)
+		if violation == nil {
+			violation = t.CheckPlay(*hand, (*hand)[index])
+		}
+		if violation != nil {
+			ShowViolation(s.Decider, violation)
+			continue
+		}
+
+		card := (*hand)[index]
+		*hand = append((*hand)[:index], (*hand)[index+1:]...)
+		return card, false
+	}
+}
+
+// PlayCards has the players, listed from the leader, play onto the trick in turn, picking
+// up after any cards already on it. play gets a player's card and played is told about it
+// once it is on the trick. When everyone has played the trick is taken and returned.
+func (t *TrickTaking) PlayCards(players []string, play func(name string) (Card, bool), played func(name string, card Card)) (CompletedTrick, bool) {
+	for i := len(t.CurrentTrick); i < len(players); i++ {
+		card, cancelled := play(players[i])
+		if cancelled {
+			return CompletedTrick{}, true
+		}
+		t.CurrentTrick = append(t.CurrentTrick, card)
+		played(players[i], card)
+	}
+	return t.TakeTrick(players), false
+}
+
+// TakeTrick gives the current trick to its winner, who leads the next one
+func (t *TrickTaking) TakeTrick(players []string) CompletedTrick {
+	won := CompletedTrick{
+		Leader: t.Leader,
+		Winner: players[t.TrickWinner(t.CurrentTrick)],
+		Cards: t.CurrentTrick,
+		Players: players[:len(t.CurrentTrick)],
+		Points: t.trickRules.TrickPoints(t.CurrentTrick),
+	}
+	t.Tricks = append(t.Tricks, won)
+	t.CurrentTrick = Deck{}
+	t.Leader = won.Winner
+	t.Trick++
+	return won
+}
+
+// SeatsFrom lists the players around the table starting with the given one
+func SeatsFrom(order []string, first string) []string {
+	start := 0
+	for i, name := range order {
+		if name == first {
+			start = i
+		}
+	}
+	seats := []string{}
+	for i := range order {
+		seats = append(seats, order[(start + i) % len(order)])
+	}
+	return seats
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestTrickWinner(t *testing.T) {
+	tests := []struct {
+		trump Suit
+		trick string
+		want int
+	}{
+		{"", "5H KH 2S AH", 3},
+		// Higher cards of other suits do not take the trick
+		{"", "5H AS 2H", 0},
+		{Spades, "AH 2S KH", 1},
+		{Spades, "AH 2S 3S", 2},
+		{Spades, "2S AH AS", 2},
+		{Spades, "KD AD", 1},
+	}
+	for _, tt := range tests {
+		tricks := &TrickTaking{}
+		tricks.InitTricks(PlainTricks{Trump: tt.trump})
+		if got := tricks.TrickWinner(cards(tt.trick)); got != tt.want {
+			t.Errorf("%s with %q trumps: got %d, want %d", tt.trick, tt.trump, got, tt.want)
+		}
+	}
+}
+
+func TestEuchreTrickWinner(t *testing.T) {
+	tests := []struct {
+		trump Suit
+		trick string
+		want int
+	}{
+		// The right bower beats the left, which beats the ace of trumps
+		{Hearts, "AH JD KH QH", 1},
+		{Hearts, "JD JH", 1},
+		{Hearts, "JH AH JD", 0},
+		// The left bower is a trump, not a card of its own suit
+		{Hearts, "AD JD", 1},
+		{Hearts, "KD QD JD 10D", 2},
+		{Clubs, "AS JS", 1},
+		{Clubs, "JS AC", 0},
+		// The jack of the other colour is just a jack
+		{Hearts, "AC JC", 0},
+		{Hearts, "9C JC", 1},
+	}
+	for _, tt := range tests {
+		g := newTestEuchreGame()
+		g.Trump = tt.trump
+		if got := g.TrickWinner(cards(tt.trick)); got != tt.want {
+			t.Errorf("%s with %v trumps: got %d, want %d", tt.trick, tt.trump, got, tt.want)
+		}
+	}
+}
+
+func TestEuchreLegalCards(t *testing.T) {
+	tests := []struct {
+		trick string
+		hand string
+		want string
+	}{
+		// The left bower follows trumps, not diamonds
+		{"AD", "JD 9C", "JD 9C"},
+		{"AD", "JD 10D 9C", "10D"},
+		{"AH", "JD 9C", "JD"},
+		{"", "JD 10D 9C", "JD 10D 9C"},
+	}
+	for _, tt := range tests {
+		g := newTestEuchreGame()
+		g.Trump = Hearts
+		g.CurrentTrick = cards(tt.trick)
+		if got := g.LegalCards(cards(tt.hand)); got.String() != cards(tt.want).String() {
+			t.Errorf("%s led, holding %s: got %v, want %s", tt.trick, tt.hand, got, tt.want)
+		}
+	}
+}
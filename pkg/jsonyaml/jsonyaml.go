@@ -18,15 +18,30 @@ func Unmarshal(in []byte, out interface{}) error {
         return err
     }
     var b bytes.Buffer
-    json.NewEncoder(&b).Encode(cleanupMapValue(res))
-    json.NewDecoder(&b).Decode(out)
-
-    return nil
+    if err := json.NewEncoder(&b).Encode(cleanupMapValue(res)); err != nil {
+        return err
+    }
+    return json.NewDecoder(&b).Decode(out)
 }
 
-// Marshal YAML wrapper function.
+// Marshal YAML wrapper function. The value goes through JSON first, so the same struct
+// tags are used both ways.
 func Marshal(in interface{}) ([]byte, error) {
-    return yaml.Marshal(in)
+    b, err := json.Marshal(in)
+    if err != nil {
+        return nil, err
+    }
+
+    // MapSlice keeps the fields in the order they were written
+    var object yaml.MapSlice
+    if err := yaml.Unmarshal(b, &object); err == nil {
+        return yaml.Marshal(object)
+    }
+    var res interface{}
+    if err := yaml.Unmarshal(b, &res); err != nil {
+        return nil, err
+    }
+    return yaml.Marshal(res)
 }
 
 func cleanupInterfaceArray(in []interface{}) []interface{} {
@@ -51,9 +66,9 @@ func cleanupMapValue(v interface{}) interface{} {
         return cleanupInterfaceArray(v)
     case map[interface{}]interface{}:
         return cleanupInterfaceMap(v)
-    case string:
+    case string, bool, int, int64, uint64, float64, nil:
         return v
     default:
         return fmt.Sprintf("%v", v)
     }
-}
\ No newline at end of file
+}
@@ -1,6 +1,11 @@
 package web
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,15 +17,17 @@ const (
 	RefreshCode = MessageCode("refresh")
 	UpdateLobbySettingsCode = MessageCode("update_lobby_settings")
 	StartGameCode = MessageCode("start_game")
-	PassedCardsCode = MessageCode("passed_cards")
-	PlayedCardCode = MessageCode("played_card")
+	AnswerCode = MessageCode("answer")
+	PauseGameCode = MessageCode("pause_game")
+	ResumeGameCode = MessageCode("resume_game")
 
 	// Sending Codes
 	InfoCode = MessageCode("info")
 	UpdateLobbyCode = MessageCode("update_lobby")
 	UpdateCode = MessageCode("update")
-	PassCardsCode = MessageCode("pass_cards")
-	PlayCardCode = MessageCode("play_card")
+	PromptCode = MessageCode("prompt")
+	GameOverCode = MessageCode("game_over")
+	PauseStatusCode = MessageCode("pause_status")
 )
 
 type LobbyMessage struct {
@@ -32,19 +39,49 @@ type GameState string
 const (
 	InLobbyState = GameState("in_lobby")
 	InGameState = GameState("in_game")
+	PausedState = GameState("paused")
 	FinishedState = GameState("finished")
 )
 
+// DefaultGameType is the game new lobbies start out playing
+var DefaultGameType = game.HeartsType.Name
+
 type Settings struct {
-	MaxPoints int `json:"max_points"`
+	// The name of the game type, and its options
+	Game string `json:"game"`
+	Options game.Options `json:"options"`
+	Seed string `json:"seed"`
+	// Seconds per decision and seconds of thinking time for the whole game, zero for no limit
+	TurnTimeLimit int `json:"turn_time_limit"`
+	TimeBank int `json:"time_bank"`
 }
 type Lobby struct {
 	ID string `json:"id"`
 	Name string `json:"name"`
 	Settings Settings `json:"settings"`
 	State GameState `json:"state"`
+	// Where the game is up to, once it has started
+	Phase game.Phase `json:"phase,omitempty"`
 	Players []*Player `json:"players"`
-	Game *game.HeartsGame `json:"-"`
+	Game game.Game `json:"-"`
+	Recorder game.Recording `json:"-"`
+	Result *game.GameResult `json:"result,omitempty"`
+	// Players asking to pause the game, or to resume it once paused
+	PauseVotes []string `json:"pause_votes"`
+	host *Session `json:"-"`
+	seed int64 `json:"-"`
+	// The game as of its last move, and the latest record waiting to be written, kept for
+	// the store
+	saved json.RawMessage `json:"-"`
+	pending *LobbyRecord `json:"-"`
+	pendingRecorder game.Recording `json:"-"`
+	writing bool `json:"-"`
+	// Set once the lobby is removed, after which it is deleted from the store rather than
+	// saved
+	removed bool `json:"-"`
+	saveLock sync.Mutex `json:"-"`
+	restored bool `json:"-"`
+	finishedAt time.Time `json:"-"`
 	messageListener chan LobbyMessage `json:"-"`
 	lock *sync.Mutex `json:"-"`
 	doneListener chan bool `json:"-"`
@@ -56,14 +93,96 @@ type Player struct {
 	Session *Session `json:"-"`
 	AnswerChannel chan Message `json:"-"`
 	ReconnectMessage *Message `json:"-"`
+	rand *rand.Rand `json:"-"`
+	strategy game.Strategy `json:"-"`
+	interrupts chan bool `json:"-"`
 }
 
 var Lobbies = map[string]*Lobby{}
+var lobbiesLock sync.Mutex
+
+// FinishedLobbyRetention is how long lobbies are kept once their game is over, so the game
+// can be replayed. Lobbies given up on before their game was over are removed straight away.
+var FinishedLobbyRetention = 24 * time.Hour
+
+// GetLobby looks up a lobby by its ID
+func GetLobby(id string) (*Lobby, bool) {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
+	l, ok := Lobbies[id]
+	return l, ok
+}
+
+func addLobby(l *Lobby) {
+	lobbiesLock.Lock()
+	Lobbies[l.ID] = l
+	lobbiesLock.Unlock()
+}
+
+// removeLobby forgets the lobby and deletes it from the store, once any save being written
+// is done
+func removeLobby(l *Lobby) {
+	lobbiesLock.Lock()
+	delete(Lobbies, l.ID)
+	lobbiesLock.Unlock()
+
+	l.saveLock.Lock()
+	defer l.saveLock.Unlock()
+	l.removed = true
+	l.pending, l.pendingRecorder = nil, nil
+	if !l.writing {
+		l.deleteSaved()
+	}
+}
+
+// expire removes the lobby once it is no use to anyone: straight away if it finished
+// without a result, or after FinishedLobbyRetention
+func (l *Lobby) expire() {
+	if l.Result == nil {
+		removeLobby(l)
+		return
+	}
+	go func() {
+		time.Sleep(time.Until(l.finishedAt.Add(FinishedLobbyRetention)))
+		removeLobby(l)
+	}()
+}
 
 //----------------------------------------------------//
 //---------------------- Lobby -----------------------//
 //----------------------------------------------------//
 
+// NewSettings starts a lobby off with the game's default options
+func NewSettings(gameType string) Settings {
+	gt, _ := game.LookupGameType(gameType)
+	return Settings{Game: gameType, Options: gt.DefaultOptions()}
+}
+
+// GameType is the type of game the lobby is set up to play. Lobbies saved before there
+// was a choice only ever played hearts.
+func (l *Lobby) GameType() *game.GameType {
+	if gt, ok := game.LookupGameType(l.Settings.Game); ok {
+		return gt
+	}
+	return game.HeartsType
+}
+
+// UpdateOptions changes the options given, as long as the game still allows them all
+func (l *Lobby) UpdateOptions(s *Session, changed game.Options) {
+	options := game.Options{}
+	for key, value := range l.Settings.Options {
+		options[key] = value
+	}
+	for key, value := range changed {
+		options[key] = value
+	}
+	if err := l.GameType().CheckOptions(options); err != nil {
+		s.SendInfo(err.Error())
+		return
+	}
+	l.Settings.Options = options
+}
+
 func (l *Lobby) Started() bool {
 	return l.State != InLobbyState
 }
@@ -73,6 +192,8 @@ func (l *Lobby) Finished() bool {
 }
 
 func GetUnstartedLobbies() []*Lobby {
+	lobbiesLock.Lock()
+	defer lobbiesLock.Unlock()
 	unstarted := []*Lobby{}
 	for _, l := range Lobbies {
 		if (!l.Started()) {
@@ -86,22 +207,27 @@ func StartNewLobby(host *Session, nickname string, lobbyName string) {
 	lobby := &Lobby{
 		ID: RandomString(12),
 		Name: lobbyName,
-		Settings: Settings{MaxPoints: 100},
+		Settings: NewSettings(DefaultGameType),
 		State: InLobbyState,
-		Players: []*Player{{Name: nickname, CPU: false, Session: host, AnswerChannel: make(chan Message)}},
+		Players: []*Player{NewHumanPlayer(nickname, host)},
+		PauseVotes: []string{},
+		host: host,
 		lock: &sync.Mutex{},
 	}
 	host.lobby = lobby
 
-	Lobbies[lobby.ID] = lobby
+	addLobby(lobby)
 	lobby.UpdateAll()
+	lobby.Save()
 	lobby.Run()
 }
 
 func (l *Lobby) Join(joiner *Session, nickname string) {
-	l.Players = append(l.Players, &Player{Name: nickname, CPU: false, Session: joiner, AnswerChannel: make(chan Message)})
+	l.lock.Lock()
+	l.Players = append(l.Players, NewHumanPlayer(nickname, joiner))
 	joiner.lobby = l
 	l.UpdateAll()
+	l.lock.Unlock()
 }
 
 func (l *Lobby) AddCPU(nickname string) {
@@ -125,10 +251,13 @@ func (l *Lobby) RemoveCPU(nickname string) {
 }
 
 
+// SendMessage hands the message to the lobby's goroutine. The lock is not held while
+// waiting, since the lobby holds it while it handles the message before.
 func (l *Lobby) SendMessage(s *Session, m Message) {
 	l.lock.Lock()
-	l.messageListener <- LobbyMessage{s, m}
+	listener := l.messageListener
 	l.lock.Unlock()
+	listener <- LobbyMessage{s, m}
 }
 
 func (l *Lobby) Alive() bool {
@@ -152,17 +281,26 @@ func (l *Lobby) Run() {
 			select {
 			case lm = <-l.messageListener:
 			case <- l.doneListener:
+				l.lock.Lock()
 				l.State = FinishedState
+				l.finishedAt = time.Now()
 				if l.Game != nil && !l.Game.GameOver() {
 					l.Game.Cancel()
 				}
+				l.lock.Unlock()
+				l.Save()
 				l.Cleanup()
+				l.expire()
 				return
 			}
 
+			// The lobby is only changed while locked, so it can be saved from the game's
+			// goroutine
+			l.lock.Lock()
 			s := lm.Source
 			p := l.GetPlayer(s)
 			m := lm.Message
+			changed := false
 			switch l.State {
 			case InLobbyState:
 				switch m.Code {
@@ -171,7 +309,11 @@ func (l *Lobby) Run() {
 
 				case UpdateLobbySettingsCode:
 					var pyld struct {
-						MaxPoints *int `json:"max_points,omitempty"`
+						Game *string `json:"game,omitempty"`
+						Options game.Options `json:"options,omitempty"`
+						Seed *string `json:"seed,omitempty"`
+						TurnTimeLimit *int `json:"turn_time_limit,omitempty"`
+						TimeBank *int `json:"time_bank,omitempty"`
 						PSI1 *int `json:"player_swap_index_1,omitempty"`
 						PSI2 *int `json:"player_swap_index_2,omitempty"`
 						AddCPU *string `json:"add_cpu"`
@@ -179,8 +321,38 @@ func (l *Lobby) Run() {
 					}
 					m.GetContent(&pyld)
 
-					if pyld.MaxPoints != nil {
-						l.Settings.MaxPoints = *pyld.MaxPoints
+					if pyld.Game != nil {
+						if _, ok := game.LookupGameType(*pyld.Game); !ok {
+							s.SendInfo(fmt.Sprintf("[%s] is not a game that can be played", *pyld.Game))
+						} else if *pyld.Game != l.Settings.Game {
+							// Only the options belong to the game, the seed and timers are kept
+							l.Settings.Game = *pyld.Game
+							l.Settings.Options = NewSettings(*pyld.Game).Options
+						}
+					}
+					if pyld.Options != nil {
+						l.UpdateOptions(s, pyld.Options)
+					}
+					if pyld.Seed != nil {
+						if _, err := strconv.ParseInt(*pyld.Seed, 10, 64); err != nil && *pyld.Seed != "" {
+							s.SendInfo("Seed must be a whole number, or empty for a random game")
+						} else {
+							l.Settings.Seed = *pyld.Seed
+						}
+					}
+					if pyld.TurnTimeLimit != nil {
+						if *pyld.TurnTimeLimit < 0 {
+							s.SendInfo("Turn time limit can not be negative")
+						} else {
+							l.Settings.TurnTimeLimit = *pyld.TurnTimeLimit
+						}
+					}
+					if pyld.TimeBank != nil {
+						if *pyld.TimeBank < 0 {
+							s.SendInfo("Time bank can not be negative")
+						} else {
+							l.Settings.TimeBank = *pyld.TimeBank
+						}
 					}
 					if pyld.PSI1 != nil {
 						l.Players[*pyld.PSI1], l.Players[*pyld.PSI2] = l.Players[*pyld.PSI2], l.Players[*pyld.PSI1]
@@ -195,33 +367,48 @@ func (l *Lobby) Run() {
 					l.UpdateAll()
 
 				case StartGameCode:
-					if len(l.Players) < 4 {
-						s.SendInfo("Too few players")
+					gt := l.GameType()
+					if err := gt.CheckPlayers(len(l.Players)); err != nil {
+						s.SendInfo(err.Error())
 						break
 					}
-					if len(l.Players) > 4 {
-						s.SendInfo("Too many players")
-						break
+					seed := time.Now().UnixNano()
+					if l.Settings.Seed != "" {
+						seed, _ = strconv.ParseInt(l.Settings.Seed, 10, 64)
 					}
 					deciders := []game.Decider{}
-					for _, p := range l.Players {
+					for i, p := range l.Players {
+						p.rand = rand.New(rand.NewSource(seed - int64(i) - 1))
+						p.strategy = gt.CPU
 						deciders = append(deciders, p)
 					}
-					l.Game = game.NewHeartsGame(deciders, l.Settings.MaxPoints)
-					l.State = InGameState
-					go func() {
-						c := l.Game.Start()
-						<-c
-						if !l.Finished() {
-							l.doneListener <- true
+					g, err := gt.NewGame(deciders, seed, l.Settings.Options)
+					if err != nil {
+						s.SendInfo(err.Error())
+						break
+					}
+					log.Printf("Lobby %s starting %s with seed %d", l.ID, gt.Name, seed)
+					l.seed = seed
+					l.Game = g
+					l.Game.SetTimer(game.TurnTimer{
+						PerDecision: time.Duration(l.Settings.TurnTimeLimit) * time.Second,
+						Bank: time.Duration(l.Settings.TimeBank) * time.Second,
+					})
+					if gt.Record != nil {
+						l.Recorder, err = gt.Record(l.Game, nil)
+						if err != nil {
+							log.Printf("Lobby %s can not record its game: %v", l.ID, err)
 						}
-					}()
+					}
+					l.State = InGameState
+					l.play()
 
 				default:
 					s.SendInvalidCodeError(m.Code)
 				}
+				changed = true
 				
-			case InGameState:
+			case InGameState, PausedState:
 				switch m.Code {
 				case RefreshCode:
 					l.Update(p)
@@ -229,17 +416,33 @@ func (l *Lobby) Run() {
 				case ReconnectedCode:
 					p.Reconnect(l)
 
-				case PassedCardsCode, PlayedCardCode:
-					p.AnswerChannel <- m
+				case AnswerCode:
+					// Nobody is listening if the player's time already ran out
+					select {
+					case p.AnswerChannel <- m:
+					default:
+						s.SendInfo("Not waiting on a move from you")
+					}
+
+				case PauseGameCode, ResumeGameCode:
+					l.VotePause(p, m.Code == PauseGameCode)
+					changed = true
 
 				default:
 					s.SendInvalidCodeError(m.Code)
 				}
 			}
+			l.lock.Unlock()
+			if changed {
+				l.Save()
+			}
 		}
 	}()
 	
 	go func() {
+		if l.restored {
+			time.Sleep(ReconnectGracePeriod)
+		}
 		for {
 			time.Sleep(30 * time.Second)
 			if l.Finished() || !l.Alive() {
@@ -253,6 +456,34 @@ func (l *Lobby) Run() {
 	}()
 }
 
+// play runs the lobby's game in the background, saving it after every move. It is called
+// with the lobby locked, or before anyone else can see it.
+func (l *Lobby) play() {
+	l.Phase = l.Game.CurrentPhase()
+	// Phases change on the game's goroutine, so the lobby is locked to keep up with them
+	l.Game.Subscribe(func(e game.Event) {
+		if pc, ok := e.(game.PhaseChanged); ok {
+			l.lock.Lock()
+			l.Phase = pc.To
+			l.lock.Unlock()
+		}
+	})
+	l.Game.Subscribe(l.saveMove)
+	go func() {
+		c := l.Game.Start()
+		result := <-c
+		if result.Reason != game.CancelledReason {
+			l.lock.Lock()
+			l.Result = &result
+			l.SendGameOver()
+			l.lock.Unlock()
+		}
+		if !l.Finished() {
+			l.doneListener <- true
+		}
+	}()
+}
+
 func (l *Lobby) Cleanup() {
 	for _, p := range l.Players {
 		p.Cleanup()
@@ -276,11 +507,66 @@ func (l *Lobby) Update(p *Player) {
 	switch l.State {
 	case InLobbyState:
 		p.Session.SendNewMessage(UpdateLobbyCode, l)
-	case InGameState:
+	case InGameState, PausedState:
 		p.Session.SendNewMessage(UpdateCode, l.Game.GetDeciderInfo(p))
 	}
 }
 
+// VotePause pauses or resumes the game once every person playing has asked to, or as
+// soon as the host asks
+func (l *Lobby) VotePause(p *Player, pause bool) {
+	if pause == (l.State == PausedState) {
+		if pause {
+			p.Session.SendInfo("The game is already paused")
+		} else {
+			p.Session.SendInfo("The game is not paused")
+		}
+		return
+	}
+
+	voted := false
+	for _, name := range l.PauseVotes {
+		voted = voted || name == p.Name
+	}
+	if !voted {
+		l.PauseVotes = append(l.PauseVotes, p.Name)
+	}
+
+	if p.Session == l.host || len(l.PauseVotes) >= l.NumHumans() {
+		l.PauseVotes = []string{}
+		if pause && l.Game.Pause() {
+			l.State = PausedState
+		} else if !pause && l.Game.Resume() {
+			l.State = InGameState
+		}
+	}
+
+	for _, player := range l.Players {
+		if !player.CPU {
+			player.Session.SendNewMessage(PauseStatusCode, l)
+		}
+	}
+	l.UpdateAll()
+}
+
+func (l *Lobby) NumHumans() int {
+	humans := 0
+	for _, p := range l.Players {
+		if !p.CPU {
+			humans++
+		}
+	}
+	return humans
+}
+
+func (l *Lobby) SendGameOver() {
+	for _, p := range l.Players {
+		if !p.CPU {
+			p.Session.SendNewMessage(GameOverCode, l.Result)
+		}
+	}
+}
+
 func (l *Lobby) UpdateAll() {
 	for _, s := range l.Players {
 		l.Update(s)
@@ -292,35 +578,64 @@ func (l *Lobby) UpdateAll() {
 //--------------------- Player -----------------------//
 //----------------------------------------------------//
 
+func NewHumanPlayer(nickname string, s *Session) *Player {
+	return &Player{
+		Name: nickname,
+		CPU: false,
+		Session: s,
+		AnswerChannel: make(chan Message),
+		interrupts: make(chan bool, 1),
+	}
+}
+
 func (p *Player) Decide(q game.Question, g game.GameState) game.Answer {
 	if p.CPU {
-		return game.RandomDecision(p, q, g)
+		return p.strategy(p.rand, p, q, g)
 	}
 
-	hg := g.GetDeciderInfo(p).(*game.HeartsGameInfo)
-	p.Session.SendNewMessage(UpdateCode, hg)
+	// An interrupt meant for the last decision may have come in after it was answered
+	select {
+	case <-p.interrupts:
+	default:
+	}
 
-	switch q {
-	case game.PassCardsQuestion:
-		p.ReconnectMessage = &Message{Code: PassCardsCode}
-		p.Session.SendMessage(*p.ReconnectMessage)
-		m := <-p.AnswerChannel
-		p.ReconnectMessage = nil
-		var payload struct{Cards []int `json:"cards"`}
-		m.GetContent(&payload)
-		return payload.Cards
-
-	case game.PlayOnTrickQuestion:
-		p.ReconnectMessage = &Message{Code: PlayCardCode}
-		p.Session.SendMessage(*p.ReconnectMessage)
-		m := <-p.AnswerChannel
-		p.ReconnectMessage = nil
-		var payload struct{Card int `json:"card"`}
-		m.GetContent(&payload)		
-		return payload.Card
+	prompter, ok := g.(game.Prompter)
+	if !ok {
+		return nil
+	}
+	p.Session.SendNewMessage(UpdateCode, g.GetDeciderInfo(p))
+	p.ReconnectMessage = &Message{Code: PromptCode, Content: prompter.Prompt(p, q)}
+	p.Session.SendMessage(*p.ReconnectMessage)
+	m, ok := p.awaitAnswer()
+	if !ok {
+		// Takes the prompt back down
+		p.Session.SendNewMessage(PromptCode, nil)
+		return nil
+	}
+	var r game.Response
+	m.GetContent(&r)
+	return prompter.Answer(q, r)
+}
+
+func (p *Player) awaitAnswer() (Message, bool) {
+	defer func() { p.ReconnectMessage = nil }()
+	select {
+	case m := <-p.AnswerChannel:
+		return m, true
+	case <-p.interrupts:
+		return Message{}, false
 	}
+}
 
-	return nil
+// Interrupt stops waiting on the player's answer, once their time is up or the game ends
+func (p *Player) Interrupt() {
+	if p.CPU {
+		return
+	}
+	select {
+	case p.interrupts <- true:
+	default:
+	}
 }
 
 func (p *Player) ShowInfo(info string) {
@@ -329,6 +644,12 @@ func (p *Player) ShowInfo(info string) {
 	}
 }
 
+func (p *Player) ShowViolation(v *game.RuleViolation) {
+	if !p.CPU {
+		p.Session.SendViolation(v)
+	}
+}
+
 func (p *Player) GetName() string {
 	return p.Name
 }
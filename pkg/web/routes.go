@@ -8,8 +8,10 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/thecreatorguy/cards/pkg/game"
 )
 
 const (
@@ -41,6 +43,8 @@ func AddRoutes(r *mux.Router, basePath string, faviconPath string) {
 	r.HandleFunc(basePath + "/lobby/list", handleLobbyList).Methods("GET")
 	r.HandleFunc(basePath + "/game", handleGame(basePath, faviconPath, templates)).Methods("GET")
 	r.HandleFunc(basePath + "/game/websocket", makeConnection).Methods("GET")
+	r.HandleFunc(basePath + "/game/types", handleGameTypes).Methods("GET")
+	r.HandleFunc(basePath + "/game/{id}/replay", handleReplay).Methods("GET")
 }
 
 func sessionMiddleware(next http.Handler) http.Handler {
@@ -100,3 +104,100 @@ func handleGame(basePath string, faviconPath string, templates *template.Templat
 		w.Write(game)
 	}
 }
+
+type ReplayEvent struct {
+	Type game.EventType `json:"type"`
+	Event game.Event `json:"event"`
+}
+
+type ReplayState struct {
+	Position game.Position `json:"position"`
+	Players map[string]interface{} `json:"players"`
+}
+
+// handleGameTypes lists the games a lobby can play, with the options each one has
+func handleGameTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.GameTypes())
+}
+
+// handleReplay returns a finished game as newline separated events. With
+// format=record it returns the raw record instead, and with round, trick and card set it
+// returns every player's view of the table at that point.
+func handleReplay(w http.ResponseWriter, r *http.Request) {
+	l, ok := GetLobby(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "No recorded game for this lobby", http.StatusNotFound)
+		return
+	}
+	l.lock.Lock()
+	recorder, gt, finished := l.Recorder, l.GameType(), l.Finished()
+	l.lock.Unlock()
+	if recorder == nil || gt.Replay == nil {
+		http.Error(w, "No recorded game for this lobby", http.StatusNotFound)
+		return
+	}
+	if !finished {
+		http.Error(w, "Game is still in progress", http.StatusForbidden)
+		return
+	}
+
+	record, err := recorder.Save()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+
+	if query.Get("format") == "record" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(record)
+		return
+	}
+
+	replayer, err := gt.Replay(record)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if query.Has("round") || query.Has("trick") || query.Has("card") {
+		var pos game.Position
+		var errs [3]error
+		pos.Round, errs[0] = strconv.Atoi(query.Get("round"))
+		pos.Trick, errs[1] = strconv.Atoi(query.Get("trick"))
+		pos.Card, errs[2] = strconv.Atoi(query.Get("card"))
+		for _, err := range errs {
+			if err != nil {
+				http.Error(w, "round, trick and card must all be numbers", http.StatusBadRequest)
+				return
+			}
+		}
+
+		views, err := replayer.ViewsAt(pos)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		state := ReplayState{Position: pos, Players: views}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+		return
+	}
+
+	// The whole replay is built before any of it is sent, so it goes out as one response
+	// well within the server's write timeout
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	err = replayer.Events(func(e game.Event) {
+		encoder.Encode(ReplayEvent{e.Type(), e})
+	})
+	if err != nil {
+		log.Printf("Replaying lobby %s failed: %v", l.ID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/thecreatorguy/cards/pkg/game"
 )
 
 var TimeoutDuration = time.Second * 5
@@ -40,15 +41,18 @@ const (
 	FailedDecodingError = ErrorCode("failed_decoding")
 	InvalidMessageCodeError = ErrorCode("invalid_message_code")
 	InvalidLobbyError = ErrorCode("invalid_lobby")
+	RuleViolationError = ErrorCode("rule_violation")
 )
 
 type ErrorMessage struct {
 	Code ErrorCode `json:"code"`
 	Text string `json:"text"`
+	Violation *game.RuleViolation `json:"violation,omitempty"`
 }
 
 var (
 	ErrTimeout = errors.New("timed out")
+	ErrNotConnected = errors.New("not connected")
 )
 
 var upgrader = websocket.Upgrader{
@@ -117,6 +121,11 @@ func (s *Session) SendMessage(m Message) (bool, error) {
 		m.ID = RandomString(15)
 	}
 	s.writeLock.Lock()
+	// Sessions restored from the store have no connection until their player comes back
+	if s.conn == nil {
+		s.writeLock.Unlock()
+		return true, ErrNotConnected
+	}
 	err := s.conn.WriteJSON(m)
 	s.writeLock.Unlock()
 
@@ -130,7 +139,11 @@ func (s *Session) SendNewMessage(code MessageCode, content interface{}) (string,
 }
 
 func (s *Session) SendError(ec ErrorCode, text string) {
-	s.SendNewMessage(ErrorMessageCode, ErrorMessage{ec, text})
+	s.SendNewMessage(ErrorMessageCode, ErrorMessage{Code: ec, Text: text})
+}
+
+func (s *Session) SendViolation(v *game.RuleViolation) {
+	s.SendNewMessage(ErrorMessageCode, ErrorMessage{Code: RuleViolationError, Text: v.Message, Violation: v})
 }
 
 func (s *Session) SendInvalidCodeError(code MessageCode) {
@@ -170,7 +183,10 @@ func (s *Session) Alive() bool {
 }
 
 func (s *Session) Reconnect(conn *websocket.Conn) {
+	s.writeLock.Lock()
 	s.conn = conn
+	s.closed = false
+	s.writeLock.Unlock()
 	s.lobby.SendMessage(s, Message{Code: ReconnectedCode})
 	s.Listen()
 }
@@ -182,6 +198,9 @@ func (s *Session) Cleanup() {
 
 func (s *Session) Close() {
 	s.closed = true
+	if s.conn == nil {
+		return
+	}
 	s.conn.WriteControl(websocket.CloseMessage, []byte{}, time.Now().Add(time.Second * 4))
 	s.conn.Close()
 }
@@ -241,7 +260,7 @@ func (s *Session) Listen() {
 		case JoinGameCode:
 			var payload struct{Nickname string `json:"nickname"`; Lobby string `json:"lobby"`}
 			m.GetContent(&payload)
-			if l, ok := Lobbies[payload.Lobby]; ok {
+			if l, ok := GetLobby(payload.Lobby); ok {
 				l.Join(s, payload.Nickname)
 			} else {
 				s.SendError(InvalidLobbyError, fmt.Sprintf("[%s] is an invalid lobby ID", payload.Lobby))
@@ -15,21 +15,72 @@ const HostGameCode = "host_game";
 const JoinGameCode = "join_game";
 const UpdateLobbySettingsCode = "update_lobby_settings";
 const StartGameCode = "start_game";
-const PassedCardsCode = "passed_cards";
-const PlayedCardCode = "played_card";
+const AnswerCode = "answer";
+const PauseGameCode = "pause_game";
+const ResumeGameCode = "resume_game";
 
 // Recieving Codes
+const ErrorCode = "error";
 const InfoCode = "info";
 const UpdateLobbyCode = "update_lobby";
 const UpdateCode = "update";
-const PassCardsCode = "pass_cards";
-const PlayCardCode = "play_card";
+const PromptCode = "prompt";
+const GameOverCode = "game_over";
+const PauseStatusCode = "pause_status";
+
+
+// Error Constants
+const RuleViolationError = "rule_violation";
+
+const VIOLATION_MESSAGES = {
+    malformed_answer: _ => "Could not understand that answer",
+    wrong_card_count: _ => "Pick the right number of cards",
+    out_of_range: v => `There is no card ${v.index} in your hand`,
+    duplicate_index: _ => "Each card can only be picked once",
+    must_follow_suit: v => `You must follow suit: ${v.suit}`,
+    hearts_not_broken: _ => "Hearts have not been broken yet",
+    must_lead_opening_card: v => `You must lead the ${v.card.value} of ${v.card.suit}`,
+    no_points_on_first_trick: _ => "No points can be played on the first trick",
+    invalid_bid: v => v.message,
+    spades_not_broken: _ => "Spades have not been broken yet",
+    invalid_trump: v => v.message,
+    dealer_must_call: _ => "The dealer has to call trump",
+    invalid_bet: v => v.message,
+    over_thirty_one: _ => "The count can not go over 31",
+    discard_taken_card: v => `You can not throw back the ${v.card.value} of ${v.card.suit} you just took`,
+    must_match: v => `Play a ${v.suit} card, a ${v.card.value} or an eight`,
+    invalid_suit: _ => "Call one of the four suits",
+    invalid_action: v => v.message,
+};
+
+
+// Prompt Kinds
+const CardsPrompt = "cards";
+const OptionPrompt = "option";
+const NumberPrompt = "number";
 
 
 // State Constants
 const SetupState = "setup";
 const InLobbyState = "in_lobby";
 const InGameState = "in_game";
+const PausedState = "paused";
+
+const PHASE_LABELS = {
+    dealing: "Dealing",
+    passing: "Passing cards",
+    bidding: "Bidding",
+    discarding: "Discarding",
+    playing: "Playing",
+    preflop: "Betting before the flop",
+    flop: "Betting on the flop",
+    turn: "Betting on the turn",
+    river: "Betting on the river",
+    betting: "Placing bets",
+    insurance: "Insurance",
+    scoring: "Scoring the round",
+    finished: "Game over",
+};
 
 
 // Images
@@ -92,6 +143,24 @@ function createCard(card) {
     return c;
 }
 
+const SUIT_SYMBOLS = {clubs: "\u2663", diamonds: "\u2666", spades: "\u2660", hearts: "\u2665"};
+const VALUE_SYMBOLS = {ace: "A", king: "K", queen: "Q", jack: "J"};
+
+function cardLabel(card) {
+    return `${VALUE_SYMBOLS[card.value] || card.value}${SUIT_SYMBOLS[card.suit]}`;
+}
+
+function updateTrickHistory(tricks) {
+    const historyDiv = document.getElementById("trick-history");
+    historyDiv.innerHTML = "";
+    for (const trick of (tricks || []).slice().reverse()) {
+        const trickDiv = document.createElement("div");
+        historyDiv.append(trickDiv);
+        const cards = trick.cards.map((c, i) => `${trick.players[i]}: ${cardLabel(c)}`).join(", ");
+        trickDiv.innerText = `${trick.winner} won (${trick.points} pts) - ${cards}`;
+    }
+}
+
 function updatePlayerDashboard(container, name, playerInfo) {
     container.innerHTML = "";
     container.classList.toggle("leader", playerInfo.lead);
@@ -108,15 +177,314 @@ function updatePlayerDashboard(container, name, playerInfo) {
     score.classList.add("score");
     score.innerHTML = `Score: ${playerInfo.score}`;
 
-    const roundPoints = document.createElement("div");
-    container.append(roundPoints);
-    roundPoints.classList.add("hidden-cards");
-    roundPoints.innerHTML = `Round Points: ${playerInfo.roundPoints}`;
+    if (playerInfo.roundPoints !== undefined) {
+        const roundPoints = document.createElement("div");
+        container.append(roundPoints);
+        roundPoints.classList.add("hidden-cards");
+        roundPoints.innerHTML = `Round Points: ${playerInfo.roundPoints}`;
+    }
+
+    if (playerInfo.bid !== undefined) {
+        const bid = document.createElement("div");
+        container.append(bid);
+        bid.innerText = `Bid: ${playerInfo.bid || "-"} Took: ${playerInfo.tricksWon}`;
+    }
+
+    if (playerInfo.numCards !== undefined) {
+        const cards = document.createElement("div");
+        container.append(cards);
+        cards.classList.add("hidden-cards");
+        cards.innerHTML = `Cards: ${playerInfo.numCards}`;
+    }
+
+    if (playerInfo.pointCards !== undefined) {
+        const pointCards = document.createElement("div");
+        container.append(pointCards);
+        pointCards.classList.add("point-cards");
+        pointCards.innerText = `Taken: ${(playerInfo.pointCards || []).map(cardLabel).join(" ")}`;
+    }
+
+    if (playerInfo.declarer) {
+        const declarer = document.createElement("div");
+        container.append(declarer);
+        declarer.innerText = "Declarer";
+    }
+
+    if (playerInfo.dummy) {
+        const dummy = document.createElement("div");
+        container.append(dummy);
+        dummy.innerText = "Dummy";
+    }
+
+    if (playerInfo.hand) {
+        const hand = document.createElement("div");
+        container.append(hand);
+        hand.innerText = `Hand: ${playerInfo.hand.map(cardLabel).join(" ")}`;
+    }
+
+    for (const h of playerInfo.hands || []) {
+        const hand = document.createElement("div");
+        container.append(hand);
+        hand.innerText = `${h.cards.map(cardLabel).join(" ")} (${h.soft ? "soft " : ""}${h.total}) on ${h.bet}`;
+    }
+
+    if (playerInfo.insurance) {
+        const insurance = document.createElement("div");
+        container.append(insurance);
+        insurance.innerText = `Insured: ${playerInfo.insurance}`;
+    }
+
+    if (playerInfo.bet) {
+        const bet = document.createElement("div");
+        container.append(bet);
+        bet.innerText = `Bet: ${playerInfo.bet}`;
+    }
+
+    if (playerInfo.folded || playerInfo.allIn || playerInfo.out) {
+        const status = document.createElement("div");
+        container.append(status);
+        status.innerText = playerInfo.out ? "Out" : playerInfo.folded ? "Folded" : "All in";
+    }
+
+    if (playerInfo.boxes !== undefined) {
+        const boxes = document.createElement("div");
+        container.append(boxes);
+        boxes.innerText = `Hands won: ${playerInfo.boxes}`;
+    }
+
+    if (playerInfo.melds) {
+        const melds = document.createElement("div");
+        container.append(melds);
+        melds.innerText = `Melds: ${playerInfo.melds.map(m => m.map(cardLabel).join(" ")).join(" | ")}`;
+        const deadwood = document.createElement("div");
+        container.append(deadwood);
+        deadwood.innerText = `Deadwood: ${(playerInfo.deadwood || []).map(cardLabel).join(" ")}`;
+        if (playerInfo.laidOff) {
+            deadwood.innerText += ` Laid off: ${playerInfo.laidOff.map(cardLabel).join(" ")}`;
+        }
+    }
+
+    if (playerInfo.handName) {
+        const handName = document.createElement("div");
+        container.append(handName);
+        handName.innerText = playerInfo.handName;
+    }
+
+    if (playerInfo.sittingOut) {
+        const sittingOut = document.createElement("div");
+        container.append(sittingOut);
+        sittingOut.innerText = "Sitting out";
+    }
+
+    if (playerInfo.partner) {
+        const partner = document.createElement("div");
+        container.append(partner);
+        partner.innerText = `Partner: ${playerInfo.partner}`;
+    }
+
+    if (playerInfo.dealer) {
+        const dealer = document.createElement("div");
+        container.append(dealer);
+        dealer.innerText = "Dealer";
+    }
+
+    if (playerInfo.timeLeftMs !== undefined || playerInfo.timeBankMs !== undefined) {
+        const clock = document.createElement("div");
+        container.append(clock);
+        clock.classList.add("clock");
+        if (playerInfo.timeLeftMs !== undefined) {
+            clock.dataset.deadline = Date.now() + playerInfo.timeLeftMs;
+        }
+        if (playerInfo.timeBankMs !== undefined) {
+            clock.dataset.bank = playerInfo.timeBankMs;
+        }
+        updateClock(clock);
+    }
+}
+
+// Clocks count down between updates from the server, which only come when something happens
+function updateClock(clock) {
+    const parts = [];
+    if (clock.dataset.deadline) {
+        const left = Math.max(0, clock.dataset.deadline - Date.now());
+        parts.push(`Time: ${Math.ceil(left / 1000)}s`);
+    }
+    if (clock.dataset.bank) {
+        parts.push(`Bank: ${Math.ceil(clock.dataset.bank / 1000)}s`);
+    }
+    clock.innerText = parts.join(" ");
+}
+
+setInterval(() => document.querySelectorAll(".clock").forEach(updateClock), 250);
+
+// Game options are drawn from the schema of each game type, so new games need no changes here
+function createOptionInput(option) {
+    let input;
+    switch (option.type) {
+    case "bool":
+        input = document.createElement("input");
+        input.type = "checkbox";
+        input.read = () => input.checked;
+        input.write = v => input.checked = v;
+        break;
+
+    case "number":
+        input = document.createElement("input");
+        input.type = "number";
+        input.min = option.min;
+        if (option.max) {
+            input.max = option.max;
+        }
+        input.read = () => parseInt(input.value) || 0;
+        input.write = v => input.value = v;
+        break;
+
+    case "choice":
+        input = document.createElement("select");
+        for (const choice of option.choices) {
+            const o = document.createElement("option");
+            input.append(o);
+            o.value = choice.value;
+            o.innerText = choice.label;
+        }
+        input.read = () => input.value;
+        input.write = v => input.value = v;
+        break;
+
+    case "list":
+        input = document.createElement("input");
+        input.type = "text";
+        input.placeholder = option.choices.map(c => c.value).join(", ");
+        input.read = () => input.value.split(",").map(v => v.trim()).filter(v => v != "");
+        input.write = v => input.value = (v || []).join(", ");
+        break;
+    }
+    input.id = `option-${option.key}`;
+    input.disabled = !IS_HOST;
+    return input;
+}
+
+function renderOptions(gameType) {
+    const optionsDiv = document.getElementById("game-options");
+    optionsDiv.innerHTML = "";
+    optionsDiv.inputs = {};
+    for (const option of gameType.options) {
+        const label = document.createElement("label");
+        optionsDiv.append(label);
+        const input = createOptionInput(option);
+        optionsDiv.inputs[option.key] = input;
+        if (option.type == "bool") {
+            label.append(input, ` ${option.label}`);
+        } else {
+            label.append(`${option.label}: `, input);
+        }
+        input.addEventListener("change", _ => {
+            CardsController.send({code: UpdateLobbySettingsCode, content: {
+                options: {[option.key]: input.read()}
+            }});
+        });
+    }
+}
+
+function writeOptions(options) {
+    const inputs = document.getElementById("game-options").inputs || {};
+    for (const [key, input] of Object.entries(inputs)) {
+        input.write(options[key]);
+    }
+}
 
-    const cards = document.createElement("div");
-    container.append(cards);
-    cards.classList.add("hidden-cards");
-    cards.innerHTML = `Cards: ${playerInfo.numCards}`;
+function callLabel(call) {
+    if (call.kind != "bid") {
+        return call.kind;
+    }
+    return `${call.level}${call.strain == "notrump" ? "NT" : SUIT_SYMBOLS[call.strain]}`;
+}
+
+// gameDetails sums up the parts of a view that only some games have
+function gameDetails(data) {
+    const details = [];
+    if (data.maxPoints) {
+        details.push(`Max Points: ${data.maxPoints}`);
+    }
+    if (data.passDirection) {
+        details.push(`Pass Direction: ${data.passDirection}`);
+    }
+    if (data.targetScore) {
+        details.push(`Target: ${data.targetScore}`);
+    }
+    for (const team of data.teams || []) {
+        let teamDetails = `${team.name}: ${team.score}`;
+        if (team.bags !== undefined) {
+            teamDetails += ` (${team.bags} bags)`;
+        }
+        if (data.scoring == "rubber") {
+            teamDetails += ` (${team.below} below, ${team.games} games)`;
+        }
+        if (team.vulnerable) {
+            teamDetails += " vulnerable";
+        }
+        details.push(teamDetails);
+    }
+    if (data.phase == "bidding" && data.auction) {
+        details.push("Auction: " + data.auction.map(e => `${e.player} ${callLabel(e.call)}`).join(", "));
+    }
+    if (data.contract) {
+        const doubled = ["", " X", " XX"][data.contract.doubled];
+        details.push(`Contract: ${callLabel({kind: "bid", ...data.contract})}${doubled} by ${data.contract.declarer}`);
+    }
+    if (data.turnedUp) {
+        details.push(`Turned up: ${cardLabel(data.turnedUp)}`);
+    }
+    if (data.trump) {
+        details.push(`Trump: ${SUIT_SYMBOLS[data.trump]} called by ${data.maker}` + (data.alone ? " alone" : ""));
+    }
+    if (data.starter) {
+        details.push(`Starter: ${cardLabel(data.starter)}`);
+    }
+    if (data.pegCards) {
+        details.push(`Count: ${data.count}`);
+    }
+    if (data.crib) {
+        details.push(`Crib: ${data.crib.map(cardLabel).join(" ")}`);
+    }
+    if (data.stockSize !== undefined) {
+        details.push(`Stock: ${data.stockSize}`);
+    }
+    if (data.deadwoodCount !== undefined) {
+        details.push(`Your deadwood: ${data.deadwoodCount}`);
+    }
+    if (data.suit) {
+        details.push(`Suit called: ${SUIT_SYMBOLS[data.suit]}`);
+    }
+    if (data.reversed) {
+        details.push("Play reversed");
+    }
+    if (data.knocker) {
+        details.push(`${data.knocker} knocked`);
+    }
+    if (data.dealerHand) {
+        details.push(`Dealer: ${data.dealerTotal}`, `Bets: ${data.minBet}-${data.maxBet}`, `Shoe: ${data.shoeSize}`, `Hands left: ${data.handsLeft}`);
+    }
+    if (data.handSize !== undefined) {
+        details.push(`Hand size: ${data.handSize}`, `Bids: ${data.bidTotal}`);
+        if (data.roundsLeft) {
+            details.push(`Rounds left: ${data.roundsLeft}`);
+        }
+    }
+    if (data.bigBlind) {
+        details.push(`Blinds: ${data.smallBlind}/${data.bigBlind}`, `Pot: ${data.pot}`);
+    }
+    if (data.handName) {
+        details.push(`Your hand: ${data.handName}`);
+    }
+    if (data.spadesBroken) {
+        details.push("Spades broken");
+    }
+    return details.join(" | ");
+}
+
+function sameCard(a, b) {
+    return a.suit == b.suit && a.value == b.value;
 }
 
 // Controller
@@ -136,6 +504,17 @@ let CardsController = {
         CardsController.conn.onclose = e => console.log(e);
 
 
+        CardsController.picked = [];
+        CardsController.gameTypes = [];
+        fetch(BASE_PATH + "/game/types")
+            .then(r => r.json())
+            .then(types => {
+                CardsController.gameTypes = types;
+                if (CardsController.settings) {
+                    CardsController.updateLobby(CardsController.settings, CardsController.players);
+                }
+            });
+
         CardsController.view(SetupState);
     },
 
@@ -159,9 +538,20 @@ let CardsController = {
             }
             break;
 
+        case ErrorCode:
+            if (msg.content.code == RuleViolationError) {
+                const violation = msg.content.violation;
+                const describe = VIOLATION_MESSAGES[violation.code];
+                const text = describe ? describe(violation) : violation.message;
+                document.getElementById("info-message").innerText = `Not allowed: ${text}`;
+            } else {
+                console.log(msg.content);
+            }
+            break;
+
         case UpdateLobbyCode:
             CardsController.view(InLobbyState);
-            CardsController.updateLobby(msg.content.settings.max_points, msg.content.players);
+            CardsController.updateLobby(msg.content.settings, msg.content.players);
             break;
 
         case UpdateCode:
@@ -169,12 +559,16 @@ let CardsController = {
             CardsController.updateGame(msg.content);
             break;
 
-        case PassCardsCode:
-            CardsController.passCards();
+        case PromptCode:
+            CardsController.showPrompt(msg.content);
             break;
 
-        case PlayCardCode:
-            CardsController.playCard();
+        case GameOverCode:
+            CardsController.showResult(msg.content);
+            break;
+
+        case PauseStatusCode:
+            CardsController.updatePause(msg.content.state == PausedState, msg.content.pause_votes);
             break;
             
         default:
@@ -254,11 +648,35 @@ let CardsController = {
         CardsController.doneLobby = true;
 
         if (IS_HOST) {
-            const maxPointsInput = document.getElementById("max-points-input");
-            maxPointsInput.disabled = false;
-            maxPointsInput.addEventListener("change", _ => {
+            const gameTypeInput = document.getElementById("game-type-input");
+            gameTypeInput.disabled = false;
+            gameTypeInput.addEventListener("change", _ => {
+                CardsController.send({code: UpdateLobbySettingsCode, content: {
+                    game: gameTypeInput.value
+                }});
+            });
+
+            const seedInput = document.getElementById("seed-input");
+            seedInput.disabled = false;
+            seedInput.addEventListener("change", _ => {
+                CardsController.send({code: UpdateLobbySettingsCode, content: {
+                    seed: seedInput.value.trim()
+                }});
+            });
+
+            const turnTimeLimitInput = document.getElementById("turn-time-limit-input");
+            turnTimeLimitInput.disabled = false;
+            turnTimeLimitInput.addEventListener("change", _ => {
+                CardsController.send({code: UpdateLobbySettingsCode, content: {
+                    turn_time_limit: parseInt(turnTimeLimitInput.value) || 0
+                }});
+            });
+
+            const timeBankInput = document.getElementById("time-bank-input");
+            timeBankInput.disabled = false;
+            timeBankInput.addEventListener("change", _ => {
                 CardsController.send({code: UpdateLobbySettingsCode, content: {
-                    max_points: parseInt(maxPointsInput.value)
+                    time_bank: parseInt(timeBankInput.value) || 0
                 }});
             });
 
@@ -282,9 +700,33 @@ let CardsController = {
         }
     },
 
-    updateLobby(maxPoints, players) {
-        const maxPointsInput = document.getElementById("max-points-input");
-        maxPointsInput.value = maxPoints;
+    updateLobby(settings, players) {
+        CardsController.settings = settings;
+        CardsController.players = players;
+
+        const gameTypeInput = document.getElementById("game-type-input");
+        if (gameTypeInput.options.length != CardsController.gameTypes.length) {
+            gameTypeInput.innerHTML = "";
+            for (const t of CardsController.gameTypes) {
+                const o = document.createElement("option");
+                gameTypeInput.append(o);
+                o.value = t.name;
+                o.innerText = `${t.title} (${t.minPlayers}-${t.maxPlayers} players)`;
+            }
+        }
+        gameTypeInput.value = settings.game;
+        const gameType = CardsController.gameTypes.find(t => t.name == settings.game);
+        if (gameType && CardsController.optionsFor != gameType.name) {
+            CardsController.optionsFor = gameType.name;
+            renderOptions(gameType);
+        }
+        writeOptions(settings.options);
+
+        const seedInput = document.getElementById("seed-input");
+        seedInput.value = settings.seed;
+
+        document.getElementById("turn-time-limit-input").value = settings.turn_time_limit || "";
+        document.getElementById("time-bank-input").value = settings.time_bank || "";
 
         
         const playerList = document.getElementById("player-list");
@@ -348,43 +790,75 @@ let CardsController = {
             return;
         }
         CardsController.doneGame = true;
-    },
 
-    updateGame(data) {
-        let playerIndex = data.playerOrder.indexOf(data.name);
-        let leftPlayer = data.playerOrder[(playerIndex + 1) % 4];
-        let acrossPlayer = data.playerOrder[(playerIndex + 2) % 4];
-        let rightPlayer = data.playerOrder[(playerIndex + 3) % 4];
+        document.getElementById("pause-button").addEventListener("click", _ => {
+            CardsController.send({code: CardsController.paused ? ResumeGameCode : PauseGameCode});
+        });
+    },
 
-        leftPlayerDiv = document.getElementById("player-left");
-        updatePlayerDashboard(leftPlayerDiv, leftPlayer, data.playerInfo[leftPlayer]);
+    updatePause(paused, votes) {
+        CardsController.paused = paused;
+        document.getElementById("pause-button").innerText = paused ? "Resume" : "Pause";
+        let text = paused ? "Game paused" : "";
+        if (votes && votes.length > 0) {
+            text += ` (${paused ? "resume" : "pause"} asked by ${votes.join(", ")})`;
+        }
+        document.getElementById("pause-info").innerText = text;
+    },
 
-        const acrossPlayerDiv = document.getElementById("player-across");
-        updatePlayerDashboard(acrossPlayerDiv, acrossPlayer, data.playerInfo[acrossPlayer]);
+    // Views share what they can with hearts, so anything with players, tricks and a hand is
+    // drawn the same way, and anything else a game shows goes in its details
+    updateGame(data) {
+        CardsController.data = data;
+        if (data.paused != CardsController.paused) {
+            CardsController.updatePause(data.paused, []);
+        }
 
-        const rightPlayerDiv = document.getElementById("player-right");
-        updatePlayerDashboard(rightPlayerDiv, rightPlayer, data.playerInfo[rightPlayer]);
+        // Opponents are shown in seat order, starting with the player to the left
+        const opponentsDiv = document.getElementById("opponents");
+        opponentsDiv.innerHTML = "";
+        if (data.playerOrder && data.playerInfo) {
+            const numPlayers = data.playerOrder.length;
+            const playerIndex = data.playerOrder.indexOf(data.name);
+            for (let i = 1; i < numPlayers; i++) {
+                const opponent = data.playerOrder[(playerIndex + i) % numPlayers];
+                const opponentDiv = document.createElement("div");
+                opponentsDiv.append(opponentDiv);
+                opponentDiv.classList.add("opponent");
+                updatePlayerDashboard(opponentDiv, opponent, data.playerInfo[opponent]);
+            }
+        }
 
         const currentTrickDiv = document.getElementById("current-trick");
         currentTrickDiv.innerHTML = "";
-        if (data.currentTrick) {
-            for (const c of data.currentTrick) {
+        // Poker has a board, rummy a discard pile and cribbage the cards pegged where
+        // trick-taking games have the trick
+        const tableCards = data.currentTrick || data.board || data.pegCards || (data.discard && [data.discard]) || data.dealerHand;
+        if (tableCards) {
+            for (const c of tableCards) {
                 currentTrickDiv.append(createCard(c));
             }
         }
 
+        updateTrickHistory(data.tricks);
+
         const playerInfoDiv = document.getElementById("player-info");
         playerInfoDiv.innerHTML = "";
-        const playerInfoContainer = document.createElement("div");
-        playerInfoDiv.append(playerInfoContainer);
-        playerInfoContainer.classList.add("info-bar")
-        updatePlayerDashboard(playerInfoContainer, data.name, data.playerInfo[data.name]);
-        
+        if (data.playerInfo && data.playerInfo[data.name]) {
+            const playerInfoContainer = document.createElement("div");
+            playerInfoDiv.append(playerInfoContainer);
+            playerInfoContainer.classList.add("info-bar")
+            updatePlayerDashboard(playerInfoContainer, data.name, data.playerInfo[data.name]);
+        }
+
+        const prompt = CardsController.prompt;
+        const picking = prompt && prompt.kind == CardsPrompt;
         const playerHandDiv = document.createElement("div");
         playerInfoDiv.append(playerHandDiv);
         playerHandDiv.classList.add("hand");
-        for (let i = 0; i < data.hand.length; i++) {
-            const c = data.hand[i];
+        const hand = picking ? prompt.hand : (data.hand || []);
+        for (let i = 0; i < hand.length; i++) {
+            const c = hand[i];
             const playerCard = document.createElement("div");
             playerCard.classList.add("playerCard");
             playerCard.append(createCard(c));
@@ -392,53 +866,126 @@ let CardsController = {
             pickCardButton.innerText = "Pick";
             playerCard.append(pickCardButton);
             playerCard.index = i;
-            pickCardButton.addEventListener("click", function() {
-                if (CardsController.passingCards) {
-                    playerCard.classList.toggle("passedCard");
-                }
-                if (CardsController.playingCard) {
-                    CardsController.playingCard = false;
-                    CardsController.send({code: PlayedCardCode, content: {
-                        card: playerCard.index
-                    }});
-                }
-            })
-
+            if (picking && !(prompt.allowed || []).some(l => sameCard(l, c))) {
+                playerCard.classList.add("illegal");
+                pickCardButton.disabled = true;
+            }
+            if (picking && CardsController.picked.includes(i)) {
+                playerCard.classList.add("pickedCard");
+            }
+            pickCardButton.addEventListener("click", _ => CardsController.pickCard(i));
             playerHandDiv.append(playerCard);
         }
-        const cards = document.querySelectorAll(".playerCard");
-        const passButton = document.createElement("button");
-        passButton.innerText = "Pass Cards";
-        passButton.id = "pass-button";
-        passButton.classList.add("hidden");
-        passButton.addEventListener("click", function() {
-            let selected = [];
-            for (const c of cards) {
-                if (c.classList.contains("passedCard")) {
-                    selected.push(c.index);
-                }
-            } 
-            if (selected.length == 3) {
-                CardsController.passingCards = false;
-                document.getElementById("pass-button").classList.add("hidden");
-                CardsController.send({code: PassedCardsCode, content: {
-                    cards: selected
-                }});
-            }
-        });
-        playerHandDiv.append(passButton);
 
-        document.getElementById("max-points-info").innerText = `Max Points: ${data.maxPoints}`;
-        document.getElementById("pass-direction-info").innerText = `Pass Direction: ${data.passDirection}`;
+        CardsController.updatePrompt();
+
+        document.getElementById("phase-info").innerText = PHASE_LABELS[data.phase] || data.phase || "";
+        document.getElementById("details-info").innerText = gameDetails(data);
+        document.getElementById("seed-info").innerText = data.seed ? `Seed: ${data.seed}` : "";
     },
 
-    passCards() {
-        CardsController.passingCards = true;
-        document.getElementById("pass-button").classList.remove("hidden");
+    // The prompt stays up until it is answered, or taken back when time runs out
+    showPrompt(prompt) {
+        CardsController.prompt = prompt;
+        CardsController.picked = [];
+        if (CardsController.data) {
+            CardsController.updateGame(CardsController.data);
+        }
     },
 
-    playCard() {
-        CardsController.playingCard = true;
+    answer(response) {
+        if (CardsController.paused) {
+            return;
+        }
+        CardsController.prompt = null;
+        CardsController.picked = [];
+        CardsController.send({code: AnswerCode, content: response});
+        CardsController.updateGame(CardsController.data);
+    },
+
+    pickCard(index) {
+        const prompt = CardsController.prompt;
+        if (CardsController.paused || !prompt || prompt.kind != CardsPrompt) {
+            return;
+        }
+        if (prompt.count == 1) {
+            CardsController.answer({cards: [index]});
+            return;
+        }
+        const picked = CardsController.picked;
+        if (picked.includes(index)) {
+            picked.splice(picked.indexOf(index), 1);
+        } else {
+            picked.push(index);
+        }
+        CardsController.updateGame(CardsController.data);
+    },
+
+    updatePrompt() {
+        const promptDiv = document.getElementById("prompt");
+        promptDiv.innerHTML = "";
+        const prompt = CardsController.prompt;
+        if (!prompt) {
+            return;
+        }
+
+        const text = document.createElement("span");
+        promptDiv.append(text);
+        text.innerText = prompt.text;
+
+        switch (prompt.kind) {
+        case CardsPrompt:
+            if (prompt.count > 1) {
+                const submit = document.createElement("button");
+                promptDiv.append(submit);
+                submit.innerText = `Confirm (${CardsController.picked.length}/${prompt.count})`;
+                submit.disabled = CardsController.picked.length != prompt.count;
+                submit.addEventListener("click", _ => {
+                    CardsController.answer({cards: CardsController.picked.slice().sort((a, b) => a - b)});
+                });
+            }
+            break;
+
+        case OptionPrompt:
+            for (const option of prompt.options) {
+                const button = document.createElement("button");
+                promptDiv.append(button);
+                button.innerText = option;
+                button.addEventListener("click", _ => CardsController.answer({option: option}));
+            }
+            break;
+
+        case NumberPrompt:
+            const input = document.createElement("input");
+            promptDiv.append(input);
+            input.type = "number";
+            input.min = prompt.min;
+            input.max = prompt.max;
+            input.value = prompt.min;
+            const submit = document.createElement("button");
+            promptDiv.append(submit);
+            submit.innerText = "Confirm";
+            submit.addEventListener("click", _ => CardsController.answer({number: parseInt(input.value)}));
+            break;
+        }
+    },
+
+    showResult(result) {
+        const gameOverDiv = document.getElementById("game-over");
+        gameOverDiv.classList.remove("hidden");
+
+        const players = result.placements.map(p => p.player);
+        let html = "<h2>Game Over</h2><table><tr><th>Place</th><th>Player</th><th>Score</th></tr>";
+        for (const p of result.placements) {
+            html += `<tr><td>${p.place}</td><td>${p.player}</td><td>${p.score}</td></tr>`;
+        }
+        html += "</table><h3>Score Sheet</h3><table><tr><th>Round</th>";
+        html += players.map(p => `<th>${p}</th>`).join("") + "</tr>";
+        result.scoreSheet.forEach((round, i) => {
+            html += `<tr><td>${i + 1}</td>` + players.map(p => `<td>${round[p]}</td>`).join("") + "</tr>";
+        });
+        html += "</table>";
+        gameOverDiv.innerHTML = html;
     }
 };
 
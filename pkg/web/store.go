@@ -0,0 +1,357 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thecreatorguy/cards/pkg/game"
+)
+
+// ReconnectGracePeriod is how long players of a restored lobby have to come back before
+// the lobby is given up on
+var ReconnectGracePeriod = 5 * time.Minute
+
+// Store keeps lobbies somewhere that outlasts the server
+type Store interface {
+	SaveLobby(r *LobbyRecord) error
+	LoadLobbies() ([]*LobbyRecord, error)
+	DeleteLobby(id string) error
+}
+
+// LobbyStore is where lobbies are saved, or nil to keep them in memory only
+var LobbyStore Store
+
+// LobbyRecord is what is saved of a lobby. Unlike the lobby sent to players, it has the
+// session IDs needed to match people back up with their seats.
+type LobbyRecord struct {
+	ID string `json:"id"`
+	Name string `json:"name"`
+	Settings Settings `json:"settings"`
+	State GameState `json:"state"`
+	HostSession string `json:"host_session"`
+	Players []PlayerRecord `json:"players"`
+	Seed int64 `json:"seed,string"`
+	// The game as its type saves it, and the record of its moves if it keeps one
+	Snapshot json.RawMessage `json:"snapshot,omitempty"`
+	Record json.RawMessage `json:"record,omitempty"`
+	Result *game.GameResult `json:"result,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+type PlayerRecord struct {
+	Name string `json:"name"`
+	CPU bool `json:"cpu"`
+	Session string `json:"session,omitempty"`
+}
+
+//----------------------------------------------------//
+//-------------------- File Store --------------------//
+//----------------------------------------------------//
+
+// FileStore keeps each lobby in its own JSON file in a directory
+type FileStore struct {
+	Dir string
+	lock sync.Mutex
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, id + ".json")
+}
+
+// SaveLobby writes to a temporary file first, so a crash part way through never leaves a
+// half written lobby behind
+func (s *FileStore) SaveLobby(r *LobbyRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tmp := s.path(r.ID) + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(r.ID))
+}
+
+func (s *FileStore) LoadLobbies() ([]*LobbyRecord, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	files, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	records := []*LobbyRecord{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.Dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		r := &LobbyRecord{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func (s *FileStore) DeleteLobby(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+//----------------------------------------------------//
+//------------------ Saving Lobbies ------------------//
+//----------------------------------------------------//
+
+// SaveDelay is how long a save waits before it is written, so a burst of moves is only
+// written once
+var SaveDelay = 250 * time.Millisecond
+
+// Save queues the lobby to be written to the store, with the game as of its last saved
+// move. The lobby is copied while locked, so it can be saved from any goroutine that is not
+// holding the lock.
+func (l *Lobby) Save() {
+	if LobbyStore == nil {
+		return
+	}
+
+	l.lock.Lock()
+	r := &LobbyRecord{
+		ID: l.ID,
+		Name: l.Name,
+		Settings: l.Settings,
+		State: l.State,
+		Players: []PlayerRecord{},
+		Seed: l.seed,
+		Result: l.Result,
+		FinishedAt: l.finishedAt,
+	}
+	r.Settings.Options = game.Options{}
+	for key, value := range l.Settings.Options {
+		r.Settings.Options[key] = value
+	}
+	if l.host != nil {
+		r.HostSession = l.host.ID
+	}
+	for _, p := range l.Players {
+		pr := PlayerRecord{Name: p.Name, CPU: p.CPU}
+		if p.Session != nil {
+			pr.Session = p.Session.ID
+		}
+		r.Players = append(r.Players, pr)
+	}
+	recorder := l.Recorder
+	l.lock.Unlock()
+
+	l.saveLock.Lock()
+	defer l.saveLock.Unlock()
+	if l.removed {
+		return
+	}
+	l.pending, l.pendingRecorder = r, recorder
+	if !l.writing {
+		l.writing = true
+		go l.writeSaves()
+	}
+}
+
+// writeSaves writes the latest queued record until there are none left. Only the latest is
+// kept, so records queued while one is being written are never written on their own.
+func (l *Lobby) writeSaves() {
+	for {
+		time.Sleep(SaveDelay)
+
+		l.saveLock.Lock()
+		r, recorder := l.pending, l.pendingRecorder
+		l.pending, l.pendingRecorder = nil, nil
+		if r == nil {
+			l.writing = false
+			if l.removed {
+				l.deleteSaved()
+			}
+			l.saveLock.Unlock()
+			return
+		}
+		r.Snapshot = l.saved
+		l.saveLock.Unlock()
+
+		if recorder != nil {
+			record, err := recorder.Save()
+			if err != nil {
+				log.Printf("Saving the record of lobby %s failed: %v", l.ID, err)
+			}
+			r.Record = record
+		}
+		if err := LobbyStore.SaveLobby(r); err != nil {
+			log.Printf("Saving lobby %s failed: %v", l.ID, err)
+		}
+	}
+}
+
+func (l *Lobby) deleteSaved() {
+	if LobbyStore == nil {
+		return
+	}
+	if err := LobbyStore.DeleteLobby(l.ID); err != nil {
+		log.Printf("Deleting lobby %s failed: %v", l.ID, err)
+	}
+}
+
+// saveMove keeps the game as it is after every move. It is called from the game's
+// goroutine, which is the only time the game holds still long enough to be copied.
+func (l *Lobby) saveMove(e game.Event) {
+	if LobbyStore == nil {
+		return
+	}
+	saved, err := l.Game.Save()
+	if err != nil {
+		log.Printf("Saving the game of lobby %s failed: %v", l.ID, err)
+		return
+	}
+	l.saveLock.Lock()
+	l.saved = saved
+	l.saveLock.Unlock()
+	l.Save()
+}
+
+// RestoreLobbies loads every lobby in the store and picks their games back up. Players
+// land back in their seats when they reconnect with the same session cookie. Lobbies that
+// can not be restored are deleted from the store.
+func RestoreLobbies(store Store) error {
+	LobbyStore = store
+	records, err := store.LoadLobbies()
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		l, err := restoreLobby(r)
+		if err != nil {
+			log.Printf("Could not restore lobby %s: %v", r.ID, err)
+			if err := store.DeleteLobby(r.ID); err != nil {
+				log.Printf("Deleting lobby %s failed: %v", r.ID, err)
+			}
+			continue
+		}
+		addLobby(l)
+		log.Printf("Restored lobby %s (%s)", l.ID, l.State)
+		if l.Finished() {
+			l.expire()
+		}
+	}
+	return nil
+}
+
+func restoreLobby(r *LobbyRecord) (*Lobby, error) {
+	l := &Lobby{
+		ID: r.ID,
+		Name: r.Name,
+		Settings: r.Settings,
+		State: r.State,
+		Players: []*Player{},
+		Result: r.Result,
+		PauseVotes: []string{},
+		lock: &sync.Mutex{},
+		seed: r.Seed,
+		saved: r.Snapshot,
+		restored: true,
+		finishedAt: r.FinishedAt,
+	}
+	// Lobbies saved before the time they finished was kept are kept from now
+	if l.Finished() && l.finishedAt.IsZero() {
+		l.finishedAt = time.Now()
+	}
+	for _, pr := range r.Players {
+		// Finished lobbies are only kept for their replays, so nobody is put back in them
+		if pr.CPU || l.Finished() {
+			l.Players = append(l.Players, &Player{Name: pr.Name, CPU: pr.CPU})
+			continue
+		}
+		s, ok := Sessions[pr.Session]
+		if !ok {
+			s = &Session{
+				ID: pr.Session,
+				closed: true,
+				writeLock: &sync.Mutex{},
+				recieveChannels: map[string]chan Message{},
+			}
+			Sessions[s.ID] = s
+		}
+		s.lobby = l
+		l.Players = append(l.Players, NewHumanPlayer(pr.Name, s))
+		if pr.Session == r.HostSession {
+			l.host = s
+		}
+	}
+
+	gt := l.GameType()
+	if r.Record != nil && gt.Record != nil {
+		recorder, err := gt.Record(nil, r.Record)
+		if err != nil {
+			return nil, err
+		}
+		l.Recorder = recorder
+	}
+
+	switch l.State {
+	case InLobbyState:
+		l.Run()
+
+	case InGameState, PausedState:
+		if r.Snapshot == nil {
+			return nil, fmt.Errorf("no game was saved")
+		}
+		// The CPUs can not pick up where their random sources left off, so they get fresh ones
+		restoredAt := time.Now().UnixNano()
+		deciders := []game.Decider{}
+		for i, p := range l.Players {
+			p.rand = rand.New(rand.NewSource(restoredAt - int64(i) - 1))
+			p.strategy = gt.CPU
+			deciders = append(deciders, p)
+		}
+		g, err := gt.Restore(r.Snapshot, deciders)
+		if err != nil {
+			return nil, err
+		}
+		l.Game = g
+		if gt.Record != nil {
+			l.Recorder, err = gt.Record(g, r.Record)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if l.State == PausedState {
+			g.Pause()
+		}
+		l.Run()
+		l.play()
+	}
+	return l, nil
+}
@@ -0,0 +1,246 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thecreatorguy/cards/pkg/game"
+)
+
+// useStore saves lobbies to a new file store for the length of the test
+func useStore(t *testing.T) *FileStore {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("making the store: %v", err)
+	}
+	LobbyStore, SaveDelay = store, time.Millisecond
+	t.Cleanup(func() { LobbyStore, SaveDelay = nil, 250 * time.Millisecond })
+	return store
+}
+
+// loadLobby returns the lobby with the ID from the store, or nil if it is not there
+func loadLobby(t *testing.T, store Store, id string) *LobbyRecord {
+	records, err := store.LoadLobbies()
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+	for _, r := range records {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// waitFor checks the lobby until done says it is ready, for up to ten seconds
+func waitFor(t *testing.T, l *Lobby, what string, done func() bool) {
+	for start := time.Now(); time.Since(start) < 10 * time.Second; time.Sleep(10 * time.Millisecond) {
+		l.lock.Lock()
+		ready := done()
+		l.lock.Unlock()
+		if ready {
+			return
+		}
+	}
+	t.Fatalf("lobby %s never %s", l.ID, what)
+}
+
+// closeLobby removes the lobby, and waits for anything it is writing to the store
+func closeLobby(l *Lobby) {
+	removeLobby(l)
+	for {
+		l.saveLock.Lock()
+		writing := l.writing
+		l.saveLock.Unlock()
+		if !writing {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// cpuRecord is a lobby of four CPUs part way through a game of hearts
+func cpuRecord(t *testing.T, id string, state GameState) *LobbyRecord {
+	r := &LobbyRecord{
+		ID: id,
+		Name: "cpus",
+		Settings: NewSettings(game.HeartsType.Name),
+		State: state,
+		Players: []PlayerRecord{},
+		Seed: 7,
+	}
+	deciders := []game.Decider{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		r.Players = append(r.Players, PlayerRecord{Name: name, CPU: true})
+		deciders = append(deciders, game.NewRandomCPU(name, 1))
+	}
+	g := game.NewHeartsGame(deciders, 30, r.Seed, game.DefaultHeartsRules())
+	recorder := game.NewRecorder(g)
+	g.Subscribe(func(e game.Event) {
+		if e, ok := e.(game.CardPlayed); ok && r.Snapshot == nil && e.Trick == 3 {
+			r.Snapshot, _ = g.Save()
+			r.Record, _ = recorder.Save()
+		}
+	})
+	<-g.Start()
+	if r.Snapshot == nil {
+		t.Fatalf("the game ended before it could be saved")
+	}
+	return r
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("making the store: %v", err)
+	}
+	r := &LobbyRecord{
+		ID: "abc",
+		Name: "lobby",
+		Settings: NewSettings(game.HeartsType.Name),
+		State: InLobbyState,
+		HostSession: "host",
+		Players: []PlayerRecord{{Name: "host", Session: "host"}, {Name: "cpu", CPU: true}},
+		Seed: 1 << 60,
+	}
+	if err := store.SaveLobby(r); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	r.Name = "renamed"
+	if err := store.SaveLobby(r); err != nil {
+		t.Fatalf("saving again: %v", err)
+	}
+
+	records, err := store.LoadLobbies()
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d lobbies, want 1", len(records))
+	}
+	loaded := records[0]
+	if loaded.Name != "renamed" || loaded.Seed != r.Seed || loaded.HostSession != "host" || len(loaded.Players) != 2 || !loaded.Players[1].CPU {
+		t.Errorf("loaded %+v, want %+v", loaded, r)
+	}
+
+	if err := store.DeleteLobby("abc"); err != nil {
+		t.Fatalf("deleting: %v", err)
+	}
+	if err := store.DeleteLobby("abc"); err != nil {
+		t.Errorf("deleting twice: %v", err)
+	}
+	if r := loadLobby(t, store, "abc"); r != nil {
+		t.Errorf("the deleted lobby was loaded")
+	}
+}
+
+func TestRestoreLobbyInLobby(t *testing.T) {
+	store := useStore(t)
+	r := &LobbyRecord{
+		ID: "waiting",
+		Name: "waiting",
+		Settings: NewSettings(game.HeartsType.Name),
+		State: InLobbyState,
+		HostSession: "waiting-host",
+		Players: []PlayerRecord{{Name: "host", Session: "waiting-host"}, {Name: "cpu", CPU: true}},
+	}
+	if err := store.SaveLobby(r); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+
+	l, err := restoreLobby(r)
+	if err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	if l.State != InLobbyState || l.Game != nil || len(l.Players) != 2 {
+		t.Errorf("restored a lobby %s with %d players and game %v", l.State, len(l.Players), l.Game)
+	}
+	// The host gets their seat back when they reconnect
+	if l.host == nil || l.host != Sessions["waiting-host"] || l.Players[0].Session != l.host || l.host.lobby != l {
+		t.Errorf("the host was not put back in their seat")
+	}
+	if l.Players[1].Session != nil || !l.Players[1].CPU {
+		t.Errorf("the CPU was restored as %+v", l.Players[1])
+	}
+
+	// Removing the lobby deletes it from the store
+	closeLobby(l)
+	if loadLobby(t, store, r.ID) != nil {
+		t.Errorf("the removed lobby is still saved")
+	}
+}
+
+func TestRestoreLobbyInGame(t *testing.T) {
+	store := useStore(t)
+	r := cpuRecord(t, "playing", InGameState)
+
+	l, err := restoreLobby(r)
+	if err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	defer closeLobby(l)
+	if l.Recorder == nil {
+		t.Errorf("the record of the game was not carried on")
+	}
+	// The CPUs play the game out, and the finished lobby is saved with its result
+	waitFor(t, l, "finished", func() bool { return l.State == FinishedState && l.Result != nil })
+	for start := time.Now(); time.Since(start) < 10 * time.Second; time.Sleep(10 * time.Millisecond) {
+		if saved := loadLobby(t, store, r.ID); saved != nil && saved.State == FinishedState {
+			if saved.Result == nil || saved.FinishedAt.IsZero() {
+				t.Errorf("the finished lobby was saved without its result or when it finished")
+			}
+			return
+		}
+	}
+	t.Errorf("the finished lobby was never saved")
+}
+
+func TestRestoreLobbyPaused(t *testing.T) {
+	useStore(t)
+	r := cpuRecord(t, "paused", PausedState)
+
+	l, err := restoreLobby(r)
+	if err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	defer closeLobby(l)
+	if !l.Game.Paused() {
+		t.Fatalf("the game was restored running")
+	}
+	time.Sleep(50 * time.Millisecond)
+	l.lock.Lock()
+	finished := l.Finished()
+	l.lock.Unlock()
+	if finished {
+		t.Fatalf("the paused game played on")
+	}
+
+	l.Game.Resume()
+	waitFor(t, l, "finished", func() bool { return l.State == FinishedState && l.Result != nil })
+}
+
+func TestRestoreLobbiesDeletesBroken(t *testing.T) {
+	store := useStore(t)
+	broken := &LobbyRecord{
+		ID: "broken",
+		Settings: NewSettings(game.HeartsType.Name),
+		State: InGameState,
+		Players: []PlayerRecord{},
+	}
+	if err := store.SaveLobby(broken); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	if _, err := restoreLobby(broken); err == nil {
+		t.Fatalf("a game with nothing saved was restored")
+	}
+
+	if err := RestoreLobbies(store); err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	if _, ok := GetLobby(broken.ID); ok {
+		t.Errorf("the broken lobby was restored")
+	}
+	if loadLobby(t, store, broken.ID) != nil {
+		t.Errorf("the broken lobby is still saved")
+	}
+}